@@ -4,24 +4,38 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type (
 	Config struct {
-		App      App
-		Db       Db
-		Jwt      Jwt
-		Kafka    Kafka
-		Grpc     Grpc
-		Paginate Paginate
+		App       App
+		Db        Db
+		Jwt       Jwt
+		Kafka     Kafka
+		Grpc      Grpc
+		Paginate  Paginate
+		Login     Login
+		Notifier  Notifier
+		Password  Password
+		Verify    Verify
+		TwoFactor TwoFactor
+		Oidc      Oidc
+		Blob      Blob
+		ItemAlert ItemAlert
+		Saga      Saga
+		Risk      Risk
 	}
 
+	// Note that: trusted_proxies are the cidrs of the proxies whose X-Forwarded-For is trusted,
+	// the ip of the connection is used when it is empty
 	App struct {
-		Name  string
-		Url   string
-		Stage string
+		Name           string
+		Url            string
+		Stage          string
+		TrustedProxies []string
 	}
 
 	Db struct {
@@ -49,11 +63,117 @@ type (
 		ItemUrl      string
 		InventoryUrl string
 		PaymentUrl   string
+		TlsCaPath    string
+		TlsCertPath  string
+		TlsKeyPath   string
 	}
 
 	Paginate struct {
-		ItemNextPageBasedUrl      string
-		InventoryNextPageBasedUrl string
+		ItemNextPageBasedUrl         string
+		InventoryNextPageBasedUrl    string
+		PlayerNextPageBasedUrl       string
+		PaymentNextPageBasedUrl      string
+		NotificationNextPageBasedUrl string
+		// Note that: the key that signs the cursors of the lists, it is the same for every service
+		CursorSecret string
+	}
+
+	// Note that: durations are in second unit
+	Login struct {
+		DelayThreshold   int64
+		LockThreshold    int64
+		IpLockThreshold  int64
+		LockDuration     int64
+		AttemptWindow    int64
+		MaxDelayDuration int64
+	}
+
+	// Note that: type is one of "log", "file" or "smtp"
+	Notifier struct {
+		Type         string
+		FilePath     string
+		SmtpHost     string
+		SmtpPort     string
+		SmtpUsername string
+		SmtpPassword string
+		SmtpFrom     string
+	}
+
+	// Note that: durations are in second unit
+	Password struct {
+		ResetDuration int64
+	}
+
+	// Note that: durations are in second unit
+	Verify struct {
+		EmailDuration        int64
+		AllowUnverifiedLogin bool
+		AllowUnverifiedBuy   bool
+	}
+
+	// Note that: durations are in second unit
+	TwoFactor struct {
+		Issuer            string
+		RequireForAdmin   bool
+		ChallengeDuration int64
+	}
+
+	// Note that: durations are in second unit
+	Oidc struct {
+		StateDuration int64
+		Providers     map[string]*OidcProvider
+	}
+
+	// Note that: the endpoints are discovered from the issuer, set them only for a provider without discovery
+	OidcProvider struct {
+		Name         string
+		Issuer       string
+		ClientId     string
+		ClientSecret string
+		RedirectUrl  string
+		Scopes       string
+		AuthUrl      string
+		TokenUrl     string
+		UserInfoUrl  string
+	}
+
+	// Note that: type is "local" or "s3", sizes are in byte unit
+	Blob struct {
+		Type             string
+		LocalPath        string
+		BaseUrl          string
+		MaxAvatarSize    int64
+		MaxItemImageSize int64
+		S3               BlobS3
+	}
+
+	// Note that: any s3 compatible storage, path style is for the storages that do not have a bucket subdomain, e.g. minio
+	BlobS3 struct {
+		Endpoint  string
+		Region    string
+		Bucket    string
+		AccessKey string
+		SecretKey string
+		PathStyle bool
+	}
+
+	// Note that: durations are in second unit, the alerts are published and the campaigns and the schedules are checked every interval
+	ItemAlert struct {
+		Interval int64
+	}
+
+	// Note that: durations are in second unit, a step of the buy or the sell that does not reply in the step timeout is compensated
+	Saga struct {
+		StepTimeout int64
+	}
+
+	// Note that: a limit of 0 turns its rule off, the new account age is in second unit,
+	// the limits are the defaults of every player and a player can have its own limits
+	Risk struct {
+		DailySpendCap      float64
+		MaxOrderValue      float64
+		MaxOrdersPerMinute int64
+		NewAccountAge      int64
 	}
 )
 
@@ -67,6 +187,15 @@ func LoadConfig(path string) Config {
 			Name:  os.Getenv("APP_NAME"),
 			Url:   os.Getenv("APP_URL"),
 			Stage: os.Getenv("APP_STAGE"),
+			TrustedProxies: func() []string {
+				results := make([]string, 0)
+				for _, v := range strings.Split(os.Getenv("APP_TRUSTED_PROXIES"), ",") {
+					if v = strings.TrimSpace(v); v != "" {
+						results = append(results, v)
+					}
+				}
+				return results
+			}(),
 		},
 		Db: Db{
 			Url: os.Getenv("DB_URL"),
@@ -101,10 +230,156 @@ func LoadConfig(path string) Config {
 			ItemUrl:      os.Getenv("GRPC_ITEM_URL"),
 			InventoryUrl: os.Getenv("GRPC_INVENTORY_URL"),
 			PaymentUrl:   os.Getenv("GRPC_PAYMENT_URL"),
+			TlsCaPath:    os.Getenv("GRPC_TLS_CA_PATH"),
+			TlsCertPath:  os.Getenv("GRPC_TLS_CERT_PATH"),
+			TlsKeyPath:   os.Getenv("GRPC_TLS_KEY_PATH"),
 		},
 		Paginate: Paginate{
-			ItemNextPageBasedUrl:      os.Getenv("PAGINATE_ITEM_NEXT_PAGE_BASED_URL"),
-			InventoryNextPageBasedUrl: os.Getenv("PAGINATE_INVENTORY_NEXT_PAGE_BASED_URL"),
+			ItemNextPageBasedUrl:         os.Getenv("PAGINATE_ITEM_NEXT_PAGE_BASED_URL"),
+			InventoryNextPageBasedUrl:    os.Getenv("PAGINATE_INVENTORY_NEXT_PAGE_BASED_URL"),
+			PlayerNextPageBasedUrl:       os.Getenv("PAGINATE_PLAYER_NEXT_PAGE_BASED_URL"),
+			PaymentNextPageBasedUrl:      os.Getenv("PAGINATE_PAYMENT_NEXT_PAGE_BASED_URL"),
+			NotificationNextPageBasedUrl: os.Getenv("PAGINATE_NOTIFICATION_NEXT_PAGE_BASED_URL"),
+			CursorSecret:                 requiredEnv("PAGINATE_CURSOR_SECRET"),
+		},
+		Login: Login{
+			DelayThreshold:   parseInt64Env("LOGIN_DELAY_THRESHOLD", 3),
+			LockThreshold:    parseInt64Env("LOGIN_LOCK_THRESHOLD", 10),
+			IpLockThreshold:  parseInt64Env("LOGIN_IP_LOCK_THRESHOLD", 50),
+			LockDuration:     parseInt64Env("LOGIN_LOCK_DURATION", 900),
+			AttemptWindow:    parseInt64Env("LOGIN_ATTEMPT_WINDOW", 3600),
+			MaxDelayDuration: parseInt64Env("LOGIN_MAX_DELAY_DURATION", 60),
+		},
+		Notifier: Notifier{
+			Type:         os.Getenv("NOTIFIER_TYPE"),
+			FilePath:     os.Getenv("NOTIFIER_FILE_PATH"),
+			SmtpHost:     os.Getenv("NOTIFIER_SMTP_HOST"),
+			SmtpPort:     os.Getenv("NOTIFIER_SMTP_PORT"),
+			SmtpUsername: os.Getenv("NOTIFIER_SMTP_USERNAME"),
+			SmtpPassword: os.Getenv("NOTIFIER_SMTP_PASSWORD"),
+			SmtpFrom:     os.Getenv("NOTIFIER_SMTP_FROM"),
+		},
+		Password: Password{
+			ResetDuration: parseInt64Env("PASSWORD_RESET_DURATION", 900),
+		},
+		Verify: Verify{
+			EmailDuration:        parseInt64Env("VERIFY_EMAIL_DURATION", 86400),
+			AllowUnverifiedLogin: parseBoolEnv("VERIFY_ALLOW_UNVERIFIED_LOGIN", true),
+			AllowUnverifiedBuy:   parseBoolEnv("VERIFY_ALLOW_UNVERIFIED_BUY", false),
 		},
+		TwoFactor: TwoFactor{
+			Issuer:            os.Getenv("TWO_FACTOR_ISSUER"),
+			RequireForAdmin:   parseBoolEnv("TWO_FACTOR_REQUIRE_ADMIN", false),
+			ChallengeDuration: parseInt64Env("TWO_FACTOR_CHALLENGE_DURATION", 300),
+		},
+		Oidc: Oidc{
+			StateDuration: parseInt64Env("OIDC_STATE_DURATION", 600),
+			Providers:     loadOidcProviders(),
+		},
+		Blob: Blob{
+			Type:             os.Getenv("BLOB_TYPE"),
+			LocalPath:        os.Getenv("BLOB_LOCAL_PATH"),
+			BaseUrl:          os.Getenv("BLOB_BASE_URL"),
+			MaxAvatarSize:    parseInt64Env("BLOB_MAX_AVATAR_SIZE", 2097152),
+			MaxItemImageSize: parseInt64Env("BLOB_MAX_ITEM_IMAGE_SIZE", 5242880),
+			S3: BlobS3{
+				Endpoint:  os.Getenv("BLOB_S3_ENDPOINT"),
+				Region:    os.Getenv("BLOB_S3_REGION"),
+				Bucket:    os.Getenv("BLOB_S3_BUCKET"),
+				AccessKey: os.Getenv("BLOB_S3_ACCESS_KEY"),
+				SecretKey: os.Getenv("BLOB_S3_SECRET_KEY"),
+				PathStyle: parseBoolEnv("BLOB_S3_PATH_STYLE", false),
+			},
+		},
+		ItemAlert: ItemAlert{
+			Interval: parseInt64Env("ITEM_ALERT_INTERVAL", 30),
+		},
+		Saga: Saga{
+			StepTimeout: parseInt64Env("SAGA_STEP_TIMEOUT", 10),
+		},
+		Risk: Risk{
+			DailySpendCap:      parseFloat64Env("RISK_DAILY_SPEND_CAP", 0),
+			MaxOrderValue:      parseFloat64Env("RISK_MAX_ORDER_VALUE", 0),
+			MaxOrdersPerMinute: parseInt64Env("RISK_MAX_ORDERS_PER_MINUTE", 0),
+			NewAccountAge:      parseInt64Env("RISK_NEW_ACCOUNT_AGE", 0),
+		},
+	}
+}
+
+// Note that: every provider in OIDC_PROVIDERS reads its own keys, e.g. google reads OIDC_GOOGLE_CLIENT_ID
+func loadOidcProviders() map[string]*OidcProvider {
+	providers := make(map[string]*OidcProvider)
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers[name] = &OidcProvider{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientId:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectUrl:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       os.Getenv(prefix + "SCOPES"),
+			AuthUrl:      os.Getenv(prefix + "AUTH_URL"),
+			TokenUrl:     os.Getenv(prefix + "TOKEN_URL"),
+			UserInfoUrl:  os.Getenv(prefix + "USERINFO_URL"),
+		}
+	}
+
+	return providers
+}
+
+// Note that: the service does not start when the env is not set, e.g. a secret that has no safe default
+func requiredEnv(key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		log.Fatalf("Error loading %s failed: it is required", key)
+	}
+	return value
+}
+
+// Note that: fallback is used when the env is not set
+func parseInt64Env(key string, fallback int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Fatalf("Error loading %s failed", key)
+	}
+	return result
+}
+
+// Note that: fallback is used when the env is not set
+func parseFloat64Env(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Fatalf("Error loading %s failed", key)
+	}
+	return result
+}
+
+// Note that: fallback is used when the env is not set
+func parseBoolEnv(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Error loading %s failed", key)
 	}
+	return result
 }
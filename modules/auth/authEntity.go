@@ -18,9 +18,30 @@ type (
 	}
 
 	Role struct {
-		Id    primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
-		Title string             `json:"title" bson:"title"`
-		Code  int                `json:"code" bson:"code"`
+		Id          primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Title       string             `json:"title" bson:"title"`
+		Code        int                `json:"code" bson:"code"`
+		Permissions []string           `json:"permissions" bson:"permissions"`
+	}
+
+	LoginAttempt struct {
+		Id           primitive.ObjectID `bson:"_id,omitempty"`
+		Key          string             `bson:"key"`
+		FailedCount  int64              `bson:"failed_count"`
+		LastFailedAt time.Time          `bson:"last_failed_at"`
+		LockedUntil  time.Time          `bson:"locked_until"`
+		UpdatedAt    time.Time          `bson:"updated_at"`
+	}
+
+	LoginAudit struct {
+		Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Email     string             `json:"email" bson:"email"`
+		PlayerId  string             `json:"player_id" bson:"player_id"`
+		Ip        string             `json:"ip" bson:"ip"`
+		UserAgent string             `json:"user_agent" bson:"user_agent"`
+		IsSuccess bool               `json:"is_success" bson:"is_success"`
+		Reason    string             `json:"reason" bson:"reason"`
+		CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	}
 
 	UpdateRefreshTokenReq struct {
@@ -29,4 +50,46 @@ type (
 		RefreshToken string    `bson:"refresh_token"`
 		UpdatedAt    time.Time `bson:"updated_at"`
 	}
+
+	TwoFactor struct {
+		Id            primitive.ObjectID `bson:"_id,omitempty"`
+		PlayerId      string             `bson:"player_id"`
+		Secret        string             `bson:"secret"`
+		IsEnabled     bool               `bson:"is_enabled"`
+		RecoveryCodes []string           `bson:"recovery_codes"`
+		LastUsedStep  int64              `bson:"last_used_step"`
+		CreatedAt     time.Time          `bson:"created_at"`
+		UpdatedAt     time.Time          `bson:"updated_at"`
+	}
+
+	LoginChallenge struct {
+		Id        primitive.ObjectID `bson:"_id,omitempty"`
+		TokenHash string             `bson:"token_hash"`
+		PlayerId  string             `bson:"player_id"`
+		Email     string             `bson:"email"`
+		ExpiresAt time.Time          `bson:"expires_at"`
+		CreatedAt time.Time          `bson:"created_at"`
+	}
+
+	// Note that: the code verifier never leaves the server
+	OidcState struct {
+		Id           primitive.ObjectID `bson:"_id,omitempty"`
+		StateHash    string             `bson:"state_hash"`
+		Provider     string             `bson:"provider"`
+		CodeVerifier string             `bson:"code_verifier"`
+		Nonce        string             `bson:"nonce"`
+		ExpiresAt    time.Time          `bson:"expires_at"`
+		CreatedAt    time.Time          `bson:"created_at"`
+	}
+
+	// Note that: a ban without expires_at never expires, an expired ban is removed by mongo
+	PlayerBan struct {
+		Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId  string             `json:"player_id" bson:"player_id"`
+		Kind      string             `json:"kind" bson:"kind"`
+		Reason    string             `json:"reason" bson:"reason"`
+		ExpiresAt time.Time          `json:"expires_at" bson:"expires_at,omitempty"`
+		CreatedBy string             `json:"created_by" bson:"created_by"`
+		CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	}
 )
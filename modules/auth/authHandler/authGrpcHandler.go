@@ -27,3 +27,19 @@ func (g *authGrpcHandler) AccessTokenSearch(ctx context.Context, req *authPb.Acc
 func (g *authGrpcHandler) RolesCount(ctx context.Context, req *authPb.RolesCountReq) (*authPb.RolesCountRes, error) {
 	return g.authUsecase.RolesCount(ctx)
 }
+
+func (g *authGrpcHandler) FindPermissions(ctx context.Context, req *authPb.FindPermissionsReq) (*authPb.FindPermissionsRes, error) {
+	return g.authUsecase.FindPermissions(ctx, int(req.RoleCode))
+}
+
+func (g *authGrpcHandler) CheckPermission(ctx context.Context, req *authPb.CheckPermissionReq) (*authPb.CheckPermissionRes, error) {
+	return g.authUsecase.CheckPermission(ctx, int(req.RoleCode), req.Permission)
+}
+
+func (g *authGrpcHandler) RevokePlayerCredentials(ctx context.Context, req *authPb.RevokePlayerCredentialsReq) (*authPb.RevokePlayerCredentialsRes, error) {
+	return g.authUsecase.RevokePlayerCredentials(ctx, req.PlayerId)
+}
+
+func (g *authGrpcHandler) FindPlayerSessions(ctx context.Context, req *authPb.FindPlayerSessionsReq) (*authPb.FindPlayerSessionsRes, error) {
+	return g.authUsecase.FindPlayerSessions(ctx, req.PlayerId)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
@@ -16,8 +17,25 @@ import (
 type (
 	AuthHttpHandlerService interface {
 		Login(c echo.Context) error
+		LoginTwoFactor(c echo.Context) error
+		OidcAuthorize(c echo.Context) error
+		OidcCallback(c echo.Context) error
+		EnrollTwoFactor(c echo.Context) error
+		ConfirmTwoFactor(c echo.Context) error
+		DisableTwoFactor(c echo.Context) error
+		RegenerateRecoveryCodes(c echo.Context) error
 		RefreshToken(c echo.Context) error
 		Logout(c echo.Context) error
+		UnlockLogin(c echo.Context) error
+		FindLoginAudits(c echo.Context) error
+		CreateRole(c echo.Context) error
+		FindAllRoles(c echo.Context) error
+		UpdateRolePermissions(c echo.Context) error
+		FindPlayerRoles(c echo.Context) error
+		GrantPlayerRoles(c echo.Context) error
+		RevokePlayerRole(c echo.Context) error
+		BanPlayer(c echo.Context) error
+		UnbanPlayer(c echo.Context) error
 	}
 
 	authHttpHandler struct {
@@ -40,6 +58,8 @@ func (h *authHttpHandler) Login(c echo.Context) error {
 	if err := wrapper.Bind(req); err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
+	req.Ip = c.RealIP()
+	req.UserAgent = c.Request().UserAgent()
 
 	res, err := h.authUsecase.Login(ctx, h.cfg, req)
 	if err != nil {
@@ -49,6 +69,134 @@ func (h *authHttpHandler) Login(c echo.Context) error {
 	return response.SuccessResponse(c, http.StatusOK, res)
 }
 
+func (h *authHttpHandler) LoginTwoFactor(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.LoginTwoFactorReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.Ip = c.RealIP()
+	req.UserAgent = c.Request().UserAgent()
+
+	res, err := h.authUsecase.LoginTwoFactor(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusUnauthorized, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) OidcAuthorize(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.authUsecase.OidcAuthorize(ctx, h.cfg, c.Param("provider"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) OidcCallback(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.OidcCallbackReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.Provider = c.Param("provider")
+	req.Ip = c.RealIP()
+	req.UserAgent = c.Request().UserAgent()
+
+	res, err := h.authUsecase.OidcCallback(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusUnauthorized, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) EnrollTwoFactor(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	res, err := h.authUsecase.EnrollTwoFactor(ctx, h.cfg, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) ConfirmTwoFactor(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.TwoFactorCodeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	res, err := h.authUsecase.ConfirmTwoFactor(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) DisableTwoFactor(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.TwoFactorCodeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	if err := h.authUsecase.DisableTwoFactor(ctx, h.cfg, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Two-factor authentication is disabled",
+	})
+}
+
+func (h *authHttpHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.TwoFactorCodeReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	res, err := h.authUsecase.RegenerateRecoveryCodes(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
 func (h *authHttpHandler) RefreshToken(c echo.Context) error {
 	ctx := context.Background()
 
@@ -88,3 +236,177 @@ func (h *authHttpHandler) Logout(c echo.Context) error {
 		Message: fmt.Sprintf("Deleted count: %d", res),
 	})
 }
+
+func (h *authHttpHandler) CreateRole(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.CreateRoleReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.authUsecase.CreateRole(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *authHttpHandler) FindAllRoles(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.authUsecase.FindAllRoles(ctx)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) UpdateRolePermissions(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	roleCode, err := strconv.Atoi(c.Param("role_code"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: role_code is invalid")
+	}
+
+	req := new(auth.UpdateRolePermissionsReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.authUsecase.UpdateRolePermissions(ctx, roleCode, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) FindPlayerRoles(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.authUsecase.FindPlayerRoles(ctx, h.cfg, c.Param("player_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) GrantPlayerRoles(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.InsertPlayerRole)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Param("player_id")
+
+	res, err := h.authUsecase.GrantPlayerRoles(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) RevokePlayerRole(c echo.Context) error {
+	ctx := context.Background()
+
+	roleCode, err := strconv.Atoi(c.Param("role_code"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: role_code is invalid")
+	}
+
+	res, err := h.authUsecase.RevokePlayerRole(ctx, h.cfg, c.Param("player_id"), roleCode)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) UnlockLogin(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.UnlockLoginReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.authUsecase.UnlockLogin(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: fmt.Sprintf("Unlocked count: %d", res),
+	})
+}
+
+func (h *authHttpHandler) FindLoginAudits(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.LoginAuditSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.authUsecase.FindLoginAudits(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) BanPlayer(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(auth.BanPlayerReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Param("player_id")
+	req.CreatedBy = c.Get("player_id").(string)
+
+	res, err := h.authUsecase.BanPlayer(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *authHttpHandler) UnbanPlayer(c echo.Context) error {
+	ctx := context.Background()
+
+	if err := h.authUsecase.UnbanPlayer(ctx, c.Param("player_id")); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Player is unbanned",
+	})
+}
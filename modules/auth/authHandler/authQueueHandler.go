@@ -0,0 +1,98 @@
+package authHandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/IBM/sarama"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
+)
+
+type (
+	AuthQueueHandlerService interface {
+		DeletePlayerCredentials()
+	}
+
+	authQueueHandler struct {
+		cfg         *config.Config
+		authUsecase authUsecase.AuthUsecaseService
+	}
+)
+
+func NewAuthQueueHandler(cfg *config.Config, authUsecase authUsecase.AuthUsecaseService) AuthQueueHandlerService {
+	return &authQueueHandler{
+		cfg:         cfg,
+		authUsecase: authUsecase,
+	}
+}
+
+func (h *authQueueHandler) AuthConsumer(pctx context.Context) (sarama.PartitionConsumer, error) {
+	worker, err := queue.ConnectConsumer([]string{h.cfg.Kafka.Url}, h.cfg.Kafka.ApiKey, h.cfg.Kafka.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := h.authUsecase.GetOffset(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := worker.ConsumePartition("auth", 0, offset)
+	if err != nil {
+		log.Println("Trying to set offset as 0")
+		consumer, err = worker.ConsumePartition("auth", 0, 0)
+		if err != nil {
+			log.Println("Error: AuthConsumer failed: ", err.Error())
+			return nil, err
+		}
+	}
+
+	return consumer, nil
+}
+
+func (h *authQueueHandler) DeletePlayerCredentials() {
+	ctx := context.Background()
+
+	consumer, err := h.AuthConsumer(ctx)
+	if err != nil {
+		log.Println("Error: DeletePlayerCredentials is not started: ", err.Error())
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Start DeletePlayerCredentials ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Println("Error: DeletePlayerCredentials failed: ", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			if string(msg.Key) == "pdelete" {
+				h.authUsecase.UpserOffset(ctx, msg.Offset+1)
+
+				req := new(player.PlayerDeletedEvent)
+
+				if err := queue.DecodeMessage(req, msg.Value); err != nil {
+					continue
+				}
+
+				h.authUsecase.DeletePlayerAuthData(ctx, req)
+
+				log.Printf("DeletePlayerCredentials | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigchan:
+			log.Println("Stop DeletePlayerCredentials...")
+			return
+		}
+	}
+}
@@ -8,8 +8,31 @@ import (
 
 type (
 	PlayerLoginReq struct {
-		Email    string `json:"email" form:"email" validate:"required,email,max=255"`
-		Password string `json:"password" form:"password" validate:"required,max=32"`
+		Email     string `json:"email" form:"email" validate:"required,email,max=255"`
+		Password  string `json:"password" form:"password" validate:"required,max=32"`
+		Ip        string `json:"-"`
+		UserAgent string `json:"-"`
+	}
+
+	UnlockLoginReq struct {
+		Email string `json:"email" form:"email" validate:"omitempty,email,max=255"`
+		Ip    string `json:"ip" form:"ip" validate:"omitempty,ip"`
+	}
+
+	LoginAuditSearchReq struct {
+		Email    string `query:"email" validate:"omitempty,max=255"`
+		PlayerId string `query:"player_id" validate:"omitempty,max=64"`
+		Ip       string `query:"ip" validate:"omitempty,max=64"`
+		Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
+	// Note that: kind is "ban" or "suspend", a suspend must have an expiry
+	BanPlayerReq struct {
+		PlayerId  string    `json:"-"`
+		CreatedBy string    `json:"-"`
+		Kind      string    `json:"kind" form:"kind" validate:"required,oneof=ban suspend"`
+		Reason    string    `json:"reason" form:"reason" validate:"required,max=255"`
+		ExpiresAt time.Time `json:"expires_at" form:"expires_at"`
 	}
 
 	RefreshTokenReq struct {
@@ -19,12 +42,71 @@ type (
 
 	InsertPlayerRole struct {
 		PlayerId string `json:"player_id" validate:"required"`
-		RoleCode []int  `json:"role_id" validate:"required"`
+		RoleCode []int  `json:"role_code" validate:"required"`
 	}
 
+	CreateRoleReq struct {
+		Title       string   `json:"title" form:"title" validate:"required,max=64"`
+		Permissions []string `json:"permissions" form:"permissions"`
+	}
+
+	UpdateRolePermissionsReq struct {
+		Permissions []string `json:"permissions" form:"permissions" validate:"required"`
+	}
+
+	PlayerRolesRes struct {
+		PlayerId          string              `json:"player_id"`
+		RoleCode          int                 `json:"role_code"`
+		Roles             []player.PlayerRole `json:"roles"`
+		RevokedCredential int64               `json:"revoked_credential"`
+	}
+
+	// Note that: only the challenge is set when the player has to pass the two-factor authentication
 	ProfileIntercepter struct {
 		*player.PlayerProfile
-		Credential *CredentialRes `json:"credential"`
+		Credential *CredentialRes     `json:"credential"`
+		Challenge  *LoginChallengeRes `json:"challenge,omitempty"`
+	}
+
+	LoginChallengeRes struct {
+		ChallengeToken string    `json:"challenge_token"`
+		ExpiresAt      time.Time `json:"expires_at"`
+	}
+
+	LoginTwoFactorReq struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" validate:"required,max=128"`
+		Code           string `json:"code" form:"code" validate:"required,max=32"`
+		Ip             string `json:"-"`
+		UserAgent      string `json:"-"`
+	}
+
+	OidcAuthorizeRes struct {
+		AuthorizationUrl string    `json:"authorization_url"`
+		State            string    `json:"state"`
+		ExpiresAt        time.Time `json:"expires_at"`
+	}
+
+	OidcCallbackReq struct {
+		Provider  string `param:"provider"`
+		Code      string `query:"code" validate:"max=2048"`
+		State     string `query:"state" validate:"max=256"`
+		Error     string `query:"error"`
+		Ip        string `json:"-"`
+		UserAgent string `json:"-"`
+	}
+
+	TwoFactorCodeReq struct {
+		PlayerId string `json:"-"`
+		Code     string `json:"code" form:"code" validate:"required,max=32"`
+	}
+
+	TwoFactorEnrollRes struct {
+		Secret          string `json:"secret"`
+		ProvisioningUri string `json:"provisioning_uri"`
+	}
+
+	TwoFactorRecoveryCodesRes struct {
+		RecoveryCodes []string `json:"recovery_codes"`
 	}
 
 	CredentialRes struct {
@@ -21,8 +21,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	AuthGrpcService_AccessTokenSearch_FullMethodName = "/AuthGrpcService/AccessTokenSearch"
-	AuthGrpcService_RolesCount_FullMethodName        = "/AuthGrpcService/RolesCount"
+	AuthGrpcService_AccessTokenSearch_FullMethodName       = "/AuthGrpcService/AccessTokenSearch"
+	AuthGrpcService_RolesCount_FullMethodName              = "/AuthGrpcService/RolesCount"
+	AuthGrpcService_FindPermissions_FullMethodName         = "/AuthGrpcService/FindPermissions"
+	AuthGrpcService_CheckPermission_FullMethodName         = "/AuthGrpcService/CheckPermission"
+	AuthGrpcService_RevokePlayerCredentials_FullMethodName = "/AuthGrpcService/RevokePlayerCredentials"
+	AuthGrpcService_FindPlayerSessions_FullMethodName      = "/AuthGrpcService/FindPlayerSessions"
 )
 
 // AuthGrpcServiceClient is the client API for AuthGrpcService service.
@@ -31,6 +35,10 @@ const (
 type AuthGrpcServiceClient interface {
 	AccessTokenSearch(ctx context.Context, in *AccessTokenSearchReq, opts ...grpc.CallOption) (*AccessTokenSearchRes, error)
 	RolesCount(ctx context.Context, in *RolesCountReq, opts ...grpc.CallOption) (*RolesCountRes, error)
+	FindPermissions(ctx context.Context, in *FindPermissionsReq, opts ...grpc.CallOption) (*FindPermissionsRes, error)
+	CheckPermission(ctx context.Context, in *CheckPermissionReq, opts ...grpc.CallOption) (*CheckPermissionRes, error)
+	RevokePlayerCredentials(ctx context.Context, in *RevokePlayerCredentialsReq, opts ...grpc.CallOption) (*RevokePlayerCredentialsRes, error)
+	FindPlayerSessions(ctx context.Context, in *FindPlayerSessionsReq, opts ...grpc.CallOption) (*FindPlayerSessionsRes, error)
 }
 
 type authGrpcServiceClient struct {
@@ -59,12 +67,52 @@ func (c *authGrpcServiceClient) RolesCount(ctx context.Context, in *RolesCountRe
 	return out, nil
 }
 
+func (c *authGrpcServiceClient) FindPermissions(ctx context.Context, in *FindPermissionsReq, opts ...grpc.CallOption) (*FindPermissionsRes, error) {
+	out := new(FindPermissionsRes)
+	err := c.cc.Invoke(ctx, AuthGrpcService_FindPermissions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authGrpcServiceClient) CheckPermission(ctx context.Context, in *CheckPermissionReq, opts ...grpc.CallOption) (*CheckPermissionRes, error) {
+	out := new(CheckPermissionRes)
+	err := c.cc.Invoke(ctx, AuthGrpcService_CheckPermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authGrpcServiceClient) RevokePlayerCredentials(ctx context.Context, in *RevokePlayerCredentialsReq, opts ...grpc.CallOption) (*RevokePlayerCredentialsRes, error) {
+	out := new(RevokePlayerCredentialsRes)
+	err := c.cc.Invoke(ctx, AuthGrpcService_RevokePlayerCredentials_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authGrpcServiceClient) FindPlayerSessions(ctx context.Context, in *FindPlayerSessionsReq, opts ...grpc.CallOption) (*FindPlayerSessionsRes, error) {
+	out := new(FindPlayerSessionsRes)
+	err := c.cc.Invoke(ctx, AuthGrpcService_FindPlayerSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthGrpcServiceServer is the server API for AuthGrpcService service.
 // All implementations must embed UnimplementedAuthGrpcServiceServer
 // for forward compatibility
 type AuthGrpcServiceServer interface {
 	AccessTokenSearch(context.Context, *AccessTokenSearchReq) (*AccessTokenSearchRes, error)
 	RolesCount(context.Context, *RolesCountReq) (*RolesCountRes, error)
+	FindPermissions(context.Context, *FindPermissionsReq) (*FindPermissionsRes, error)
+	CheckPermission(context.Context, *CheckPermissionReq) (*CheckPermissionRes, error)
+	RevokePlayerCredentials(context.Context, *RevokePlayerCredentialsReq) (*RevokePlayerCredentialsRes, error)
+	FindPlayerSessions(context.Context, *FindPlayerSessionsReq) (*FindPlayerSessionsRes, error)
 	mustEmbedUnimplementedAuthGrpcServiceServer()
 }
 
@@ -78,6 +126,18 @@ func (UnimplementedAuthGrpcServiceServer) AccessTokenSearch(context.Context, *Ac
 func (UnimplementedAuthGrpcServiceServer) RolesCount(context.Context, *RolesCountReq) (*RolesCountRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RolesCount not implemented")
 }
+func (UnimplementedAuthGrpcServiceServer) FindPermissions(context.Context, *FindPermissionsReq) (*FindPermissionsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindPermissions not implemented")
+}
+func (UnimplementedAuthGrpcServiceServer) CheckPermission(context.Context, *CheckPermissionReq) (*CheckPermissionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPermission not implemented")
+}
+func (UnimplementedAuthGrpcServiceServer) RevokePlayerCredentials(context.Context, *RevokePlayerCredentialsReq) (*RevokePlayerCredentialsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokePlayerCredentials not implemented")
+}
+func (UnimplementedAuthGrpcServiceServer) FindPlayerSessions(context.Context, *FindPlayerSessionsReq) (*FindPlayerSessionsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindPlayerSessions not implemented")
+}
 func (UnimplementedAuthGrpcServiceServer) mustEmbedUnimplementedAuthGrpcServiceServer() {}
 
 // UnsafeAuthGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -127,6 +187,78 @@ func _AuthGrpcService_RolesCount_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthGrpcService_FindPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindPermissionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthGrpcServiceServer).FindPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthGrpcService_FindPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthGrpcServiceServer).FindPermissions(ctx, req.(*FindPermissionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthGrpcService_CheckPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPermissionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthGrpcServiceServer).CheckPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthGrpcService_CheckPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthGrpcServiceServer).CheckPermission(ctx, req.(*CheckPermissionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthGrpcService_RevokePlayerCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokePlayerCredentialsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthGrpcServiceServer).RevokePlayerCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthGrpcService_RevokePlayerCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthGrpcServiceServer).RevokePlayerCredentials(ctx, req.(*RevokePlayerCredentialsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthGrpcService_FindPlayerSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindPlayerSessionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthGrpcServiceServer).FindPlayerSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthGrpcService_FindPlayerSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthGrpcServiceServer).FindPlayerSessions(ctx, req.(*FindPlayerSessionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthGrpcService_ServiceDesc is the grpc.ServiceDesc for AuthGrpcService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -142,6 +274,22 @@ var AuthGrpcService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RolesCount",
 			Handler:    _AuthGrpcService_RolesCount_Handler,
 		},
+		{
+			MethodName: "FindPermissions",
+			Handler:    _AuthGrpcService_FindPermissions_Handler,
+		},
+		{
+			MethodName: "CheckPermission",
+			Handler:    _AuthGrpcService_CheckPermission_Handler,
+		},
+		{
+			MethodName: "RevokePlayerCredentials",
+			Handler:    _AuthGrpcService_RevokePlayerCredentials_Handler,
+		},
+		{
+			MethodName: "FindPlayerSessions",
+			Handler:    _AuthGrpcService_FindPlayerSessions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "modules/auth/authPb/authPb.proto",
@@ -2,19 +2,30 @@ package authRepository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
 	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/oidc"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AuthRepositoryMock struct {
 	mock.Mock
 }
 
+func (m *AuthRepositoryMock) GetOffset(pctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *AuthRepositoryMock) UpserOffset(pctx context.Context, offset int64) error {
+	return nil
+}
+
 func (m *AuthRepositoryMock) CredentialSearch(pctx context.Context, grpcUrl string, req *playerPb.CredentialSearchReq) (*playerPb.PlayerProfile, error) {
 	args := m.Called(pctx, grpcUrl, req)
 	return args.Get(0).(*playerPb.PlayerProfile), args.Error(1)
@@ -59,3 +70,136 @@ func (m *AuthRepositoryMock) FindOneAccessToken(pctx context.Context, accessToke
 func (m *AuthRepositoryMock) RolesCount(pctx context.Context) (int64, error) {
 	return 0, nil
 }
+
+func (m *AuthRepositoryMock) FindOneLoginAttempt(pctx context.Context, key string) (*auth.LoginAttempt, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) IncreaseOneLoginAttempt(pctx context.Context, key string, now, windowStart time.Time) (*auth.LoginAttempt, error) {
+	return &auth.LoginAttempt{Key: key, FailedCount: 1, LastFailedAt: now}, nil
+}
+
+func (m *AuthRepositoryMock) LockOneLoginAttempt(pctx context.Context, key string, threshold int64, lockedUntil time.Time) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) DeleteOneLoginAttempt(pctx context.Context, key string) (int64, error) {
+	return 0, nil
+}
+
+func (m *AuthRepositoryMock) InsertOneLoginAudit(pctx context.Context, req *auth.LoginAudit) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) FindManyLoginAudits(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*auth.LoginAudit, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) FindAllRoles(pctx context.Context) ([]*auth.Role, error) {
+	args := m.Called(pctx)
+	return args.Get(0).([]*auth.Role), args.Error(1)
+}
+
+func (m *AuthRepositoryMock) IsUniqueRole(pctx context.Context, title string) bool {
+	return true
+}
+
+func (m *AuthRepositoryMock) InsertOneRole(pctx context.Context, req *auth.Role) (primitive.ObjectID, error) {
+	return primitive.NilObjectID, nil
+}
+
+func (m *AuthRepositoryMock) FindOneRole(pctx context.Context, roleCode int) (*auth.Role, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) UpdateOneRolePermissions(pctx context.Context, roleCode int, permissions []string) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) DeleteManyPlayerCredentials(pctx context.Context, playerId string) (int64, error) {
+	return 0, nil
+}
+
+func (m *AuthRepositoryMock) DeleteManyLoginAudits(pctx context.Context, playerId string) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) FindManyPlayerCredentials(pctx context.Context, playerId string) ([]*auth.Credential, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) UpsertOnePlayerBan(pctx context.Context, req *auth.PlayerBan) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) FindOnePlayerBan(pctx context.Context, playerId string) (*auth.PlayerBan, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) DeleteOnePlayerBan(pctx context.Context, playerId string) (int64, error) {
+	return 0, nil
+}
+
+func (m *AuthRepositoryMock) FindPlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.FindPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) AddPlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.AddPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) RemovePlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.RemovePlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) FindOneTwoFactor(pctx context.Context, playerId string) (*auth.TwoFactor, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) UpsertOneTwoFactor(pctx context.Context, req *auth.TwoFactor) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) UpdateOneTwoFactorLastUsedStep(pctx context.Context, playerId string, step int64) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) UseOneTwoFactorRecoveryCode(pctx context.Context, playerId, codeHash string) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) DeleteOneTwoFactor(pctx context.Context, playerId string) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) InsertOneLoginChallenge(pctx context.Context, req *auth.LoginChallenge) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) FindOneLoginChallenge(pctx context.Context, tokenHash string) (*auth.LoginChallenge, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) DeleteOneLoginChallenge(pctx context.Context, tokenHash string) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) InsertOneOidcState(pctx context.Context, req *auth.OidcState) error {
+	return nil
+}
+
+func (m *AuthRepositoryMock) UseOneOidcState(pctx context.Context, stateHash string) (*auth.OidcState, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) OidcAuthCodeUrl(pctx context.Context, provider *config.OidcProvider, state, nonce, codeChallenge string) (string, error) {
+	return "", nil
+}
+
+func (m *AuthRepositoryMock) OidcIdentity(pctx context.Context, provider *config.OidcProvider, code, codeVerifier, nonce string) (*oidc.Identity, error) {
+	return nil, nil
+}
+
+func (m *AuthRepositoryMock) FindOrCreatePlayerByIdentity(pctx context.Context, grpcUrl string, req *playerPb.FindOrCreatePlayerByIdentityReq) (*playerPb.PlayerProfile, error) {
+	return nil, nil
+}
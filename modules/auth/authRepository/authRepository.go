@@ -8,17 +8,26 @@ import (
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
 	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/oidc"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/status"
 )
 
+// Note that: a wrong email or password, anything else is a system failure
+var ErrCredentialInvalid = errors.New("error: email or password is incorrect")
+
 type (
 	AuthRepositoryService interface {
+		GetOffset(pctx context.Context) (int64, error)
+		UpserOffset(pctx context.Context, offset int64) error
 		CredentialSearch(pctx context.Context, grpcUrl string, req *playerPb.CredentialSearchReq) (*playerPb.PlayerProfile, error)
 		InsertOnePlayerCredential(pctx context.Context, req *auth.Credential) (primitive.ObjectID, error)
 		FindOnePlayerCredential(pctx context.Context, credentialId string) (*auth.Credential, error)
@@ -27,6 +36,39 @@ type (
 		DeleteOnePlayerCredential(pctx context.Context, credentialId string) (int64, error)
 		FindOneAccessToken(pctx context.Context, accessToken string) (*auth.Credential, error)
 		RolesCount(pctx context.Context) (int64, error)
+		FindOneLoginAttempt(pctx context.Context, key string) (*auth.LoginAttempt, error)
+		IncreaseOneLoginAttempt(pctx context.Context, key string, now, windowStart time.Time) (*auth.LoginAttempt, error)
+		LockOneLoginAttempt(pctx context.Context, key string, threshold int64, lockedUntil time.Time) error
+		DeleteOneLoginAttempt(pctx context.Context, key string) (int64, error)
+		InsertOneLoginAudit(pctx context.Context, req *auth.LoginAudit) error
+		FindManyLoginAudits(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*auth.LoginAudit, error)
+		FindOneTwoFactor(pctx context.Context, playerId string) (*auth.TwoFactor, error)
+		UpsertOneTwoFactor(pctx context.Context, req *auth.TwoFactor) error
+		UpdateOneTwoFactorLastUsedStep(pctx context.Context, playerId string, step int64) error
+		UseOneTwoFactorRecoveryCode(pctx context.Context, playerId, codeHash string) error
+		DeleteOneTwoFactor(pctx context.Context, playerId string) error
+		InsertOneLoginChallenge(pctx context.Context, req *auth.LoginChallenge) error
+		FindOneLoginChallenge(pctx context.Context, tokenHash string) (*auth.LoginChallenge, error)
+		DeleteOneLoginChallenge(pctx context.Context, tokenHash string) error
+		InsertOneOidcState(pctx context.Context, req *auth.OidcState) error
+		UseOneOidcState(pctx context.Context, stateHash string) (*auth.OidcState, error)
+		OidcAuthCodeUrl(pctx context.Context, provider *config.OidcProvider, state, nonce, codeChallenge string) (string, error)
+		OidcIdentity(pctx context.Context, provider *config.OidcProvider, code, codeVerifier, nonce string) (*oidc.Identity, error)
+		FindOrCreatePlayerByIdentity(pctx context.Context, grpcUrl string, req *playerPb.FindOrCreatePlayerByIdentityReq) (*playerPb.PlayerProfile, error)
+		FindAllRoles(pctx context.Context) ([]*auth.Role, error)
+		IsUniqueRole(pctx context.Context, title string) bool
+		InsertOneRole(pctx context.Context, req *auth.Role) (primitive.ObjectID, error)
+		FindOneRole(pctx context.Context, roleCode int) (*auth.Role, error)
+		UpdateOneRolePermissions(pctx context.Context, roleCode int, permissions []string) error
+		DeleteManyPlayerCredentials(pctx context.Context, playerId string) (int64, error)
+		DeleteManyLoginAudits(pctx context.Context, playerId string) error
+		FindManyPlayerCredentials(pctx context.Context, playerId string) ([]*auth.Credential, error)
+		UpsertOnePlayerBan(pctx context.Context, req *auth.PlayerBan) error
+		FindOnePlayerBan(pctx context.Context, playerId string) (*auth.PlayerBan, error)
+		DeleteOnePlayerBan(pctx context.Context, playerId string) (int64, error)
+		FindPlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.FindPlayerRolesReq) (*playerPb.PlayerRolesRes, error)
+		AddPlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.AddPlayerRolesReq) (*playerPb.PlayerRolesRes, error)
+		RemovePlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.RemovePlayerRolesReq) (*playerPb.PlayerRolesRes, error)
 		AccessToken(cfg *config.Config, claims *jwtauth.Claims) string
 		RefreshToken(cfg *config.Config, claims *jwtauth.Claims) string
 	}
@@ -44,6 +86,43 @@ func (r *authRepository) authDbConn(pctx context.Context) *mongo.Database {
 	return r.db.Database("auth_db")
 }
 
+func (r *authRepository) GetOffset(pctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("auth_queue")
+
+	result := new(models.KafkaOffset)
+	if err := col.FindOne(ctx, bson.M{}).Decode(result); err != nil {
+		// A deployment that is older than the auth_queue migration has no offset yet, it starts from the newest message
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return -1, nil
+		}
+		log.Printf("Error: GetOffset failed: %s", err.Error())
+		return -1, errors.New("error: GetOffset failed")
+	}
+
+	return result.Offset, nil
+}
+
+func (r *authRepository) UpserOffset(pctx context.Context, offset int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("auth_queue")
+
+	result, err := col.UpdateOne(ctx, bson.M{}, bson.M{"$set": bson.M{"offset": offset}}, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Printf("Error: UpserOffset failed: %s", err.Error())
+		return errors.New("error: UpserOffset failed")
+	}
+	log.Printf("Info: UpserOffset result: %v", result)
+
+	return nil
+}
+
 func (r *authRepository) CredentialSearch(pctx context.Context, grpcUrl string, req *playerPb.CredentialSearchReq) (*playerPb.PlayerProfile, error) {
 	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
 	defer cancel()
@@ -58,7 +137,7 @@ func (r *authRepository) CredentialSearch(pctx context.Context, grpcUrl string,
 	result, err := conn.Player().CredentialSearch(ctx, req)
 	if err != nil {
 		log.Printf("Error: CredentialSearch failed: %s", err.Error())
-		return nil, errors.New("error: email or password is incorrect")
+		return nil, ErrCredentialInvalid
 	}
 
 	return result, nil
@@ -196,6 +275,420 @@ func (r *authRepository) RolesCount(pctx context.Context) (int64, error) {
 	return count, nil
 }
 
+func (r *authRepository) FindOneLoginAttempt(pctx context.Context, key string) (*auth.LoginAttempt, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_attempts")
+
+	result := new(auth.LoginAttempt)
+	if err := col.FindOne(ctx, bson.M{"key": key}).Decode(result); err != nil {
+		log.Printf("Error: FindOneLoginAttempt failed: %s", err.Error())
+		return nil, errors.New("error: login attempt not found")
+	}
+
+	return result, nil
+}
+
+// IncreaseOneLoginAttempt counts one failure with one atomic $inc and returns the attempt after it,
+// the failures before the window start are reset first so they are not counted
+func (r *authRepository) IncreaseOneLoginAttempt(pctx context.Context, key string, now, windowStart time.Time) (*auth.LoginAttempt, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_attempts")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"key": key, "last_failed_at": bson.M{"$lt": windowStart}},
+		bson.M{"$set": bson.M{"failed_count": 0}},
+	); err != nil {
+		log.Printf("Error: IncreaseOneLoginAttempt failed: %s", err.Error())
+		return nil, errors.New("error: increase login attempt failed")
+	}
+
+	result := new(auth.LoginAttempt)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"key": key},
+		bson.M{
+			"$inc": bson.M{"failed_count": 1},
+			"$set": bson.M{"last_failed_at": now, "updated_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(result); err != nil {
+		log.Printf("Error: IncreaseOneLoginAttempt failed: %s", err.Error())
+		return nil, errors.New("error: increase login attempt failed")
+	}
+
+	return result, nil
+}
+
+// LockOneLoginAttempt locks the key when its failures are still over the threshold, the failures are reset by the lock
+func (r *authRepository) LockOneLoginAttempt(pctx context.Context, key string, threshold int64, lockedUntil time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_attempts")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"key": key, "failed_count": bson.M{"$gte": threshold}},
+		bson.M{"$set": bson.M{
+			"failed_count": 0,
+			"locked_until": lockedUntil,
+			"updated_at":   utils.LocalTime(),
+		}},
+	); err != nil {
+		log.Printf("Error: LockOneLoginAttempt failed: %s", err.Error())
+		return errors.New("error: lock login attempt failed")
+	}
+
+	return nil
+}
+
+func (r *authRepository) DeleteOneLoginAttempt(pctx context.Context, key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_attempts")
+
+	result, err := col.DeleteOne(ctx, bson.M{"key": key})
+	if err != nil {
+		log.Printf("Error: DeleteOneLoginAttempt failed: %s", err.Error())
+		return -1, errors.New("error: delete login attempt failed")
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *authRepository) InsertOneLoginAudit(pctx context.Context, req *auth.LoginAudit) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_audits")
+
+	req.CreatedAt = utils.LocalTime()
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneLoginAudit failed: %s", err.Error())
+		return errors.New("error: insert login audit failed")
+	}
+
+	return nil
+}
+
+func (r *authRepository) FindManyLoginAudits(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*auth.LoginAudit, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_audits")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyLoginAudits failed: %s", err.Error())
+		return nil, errors.New("error: find login audits failed")
+	}
+
+	results := make([]*auth.LoginAudit, 0)
+	for cursors.Next(ctx) {
+		result := new(auth.LoginAudit)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("Error: FindManyLoginAudits failed: %s", err.Error())
+			return nil, errors.New("error: find login audits failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *authRepository) FindAllRoles(pctx context.Context) ([]*auth.Role, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("roles")
+
+	cursors, err := col.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"code": 1}))
+	if err != nil {
+		log.Printf("Error: FindAllRoles failed: %s", err.Error())
+		return nil, errors.New("error: find all roles failed")
+	}
+
+	results := make([]*auth.Role, 0)
+	for cursors.Next(ctx) {
+		result := new(auth.Role)
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("Error: FindAllRoles failed: %s", err.Error())
+			return nil, errors.New("error: find all roles failed")
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *authRepository) IsUniqueRole(pctx context.Context, title string) bool {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("roles")
+
+	result := new(auth.Role)
+	if err := col.FindOne(ctx, bson.M{"title": title}).Decode(result); err != nil {
+		log.Printf("Error: IsUniqueRole: %s", err.Error())
+		return true
+	}
+	return false
+}
+
+func (r *authRepository) InsertOneRole(pctx context.Context, req *auth.Role) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("roles")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("Error: InsertOneRole failed: %s", err.Error())
+		return primitive.NilObjectID, errors.New("error: insert one role failed")
+	}
+
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *authRepository) FindOneRole(pctx context.Context, roleCode int) (*auth.Role, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("roles")
+
+	result := new(auth.Role)
+	if err := col.FindOne(ctx, bson.M{"code": roleCode}).Decode(result); err != nil {
+		log.Printf("Error: FindOneRole failed: %s", err.Error())
+		return nil, errors.New("error: role not found")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) UpdateOneRolePermissions(pctx context.Context, roleCode int, permissions []string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("roles")
+
+	result, err := col.UpdateOne(ctx, bson.M{"code": roleCode}, bson.M{"$set": bson.M{"permissions": permissions}})
+	if err != nil {
+		log.Printf("Error: UpdateOneRolePermissions failed: %s", err.Error())
+		return errors.New("error: update role permissions failed")
+	}
+	log.Printf("UpdateOneRolePermissions result: %v", result.ModifiedCount)
+
+	return nil
+}
+
+func (r *authRepository) DeleteManyPlayerCredentials(pctx context.Context, playerId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("auth")
+
+	// Note that: a refreshed credential stores the player_id without the "player:" prefix
+	result, err := col.DeleteMany(ctx, bson.M{"player_id": bson.M{"$in": []string{"player:" + playerId, playerId}}})
+	if err != nil {
+		log.Printf("Error: DeleteManyPlayerCredentials failed: %s", err.Error())
+		return -1, errors.New("error: delete player credentials failed")
+	}
+	log.Printf("DeleteManyPlayerCredentials result: %v", result)
+
+	return result.DeletedCount, nil
+}
+
+// Note that: the audits of a deleted player are removed, the email and ip address are personal data
+func (r *authRepository) DeleteManyLoginAudits(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_audits")
+
+	result, err := col.DeleteMany(ctx, bson.M{"player_id": "player:" + playerId})
+	if err != nil {
+		log.Printf("Error: DeleteManyLoginAudits failed: %s", err.Error())
+		return errors.New("error: delete login audits failed")
+	}
+	log.Printf("DeleteManyLoginAudits result: %v", result)
+
+	return nil
+}
+
+func (r *authRepository) FindManyPlayerCredentials(pctx context.Context, playerId string) ([]*auth.Credential, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("auth")
+
+	cursors, err := col.Find(
+		ctx,
+		bson.M{"player_id": bson.M{"$in": []string{"player:" + playerId, playerId}}},
+		options.Find().SetSort(bson.D{{"updated_at", -1}}),
+	)
+	if err != nil {
+		log.Printf("Error: FindManyPlayerCredentials failed: %s", err.Error())
+		return nil, errors.New("error: find player credentials failed")
+	}
+
+	results := make([]*auth.Credential, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyPlayerCredentials failed: %s", err.Error())
+		return nil, errors.New("error: find player credentials failed")
+	}
+
+	return results, nil
+}
+
+func (r *authRepository) UpsertOnePlayerBan(pctx context.Context, req *auth.PlayerBan) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("player_bans")
+
+	if _, err := col.ReplaceOne(
+		ctx,
+		bson.M{"player_id": req.PlayerId},
+		req,
+		options.Replace().SetUpsert(true),
+	); err != nil {
+		log.Printf("Error: UpsertOnePlayerBan failed: %s", err.Error())
+		return errors.New("error: ban player failed")
+	}
+
+	return nil
+}
+
+// Note that: an expired ban is not returned, even before mongo removes it
+func (r *authRepository) FindOnePlayerBan(pctx context.Context, playerId string) (*auth.PlayerBan, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("player_bans")
+
+	result := new(auth.PlayerBan)
+
+	if err := col.FindOne(
+		ctx,
+		bson.M{
+			"player_id": playerId,
+			"$or": []bson.M{
+				{"expires_at": bson.M{"$exists": false}},
+				{"expires_at": bson.M{"$gt": utils.LocalTime()}},
+			},
+		},
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("Error: FindOnePlayerBan failed: %s", err.Error())
+		return nil, errors.New("error: find player ban failed")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) DeleteOnePlayerBan(pctx context.Context, playerId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("player_bans")
+
+	result, err := col.DeleteOne(ctx, bson.M{"player_id": playerId})
+	if err != nil {
+		log.Printf("Error: DeleteOnePlayerBan failed: %s", err.Error())
+		return -1, errors.New("error: unban player failed")
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *authRepository) FindPlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.FindPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Player().FindPlayerRoles(ctx, req)
+	if err != nil {
+		log.Printf("Error: FindPlayerRoles failed: %s", err.Error())
+		return nil, errors.New("error: player not found")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) AddPlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.AddPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Player().AddPlayerRoles(ctx, req)
+	if err != nil {
+		log.Printf("Error: AddPlayerRoles failed: %s", err.Error())
+		return nil, errors.New("error: add player roles failed")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) RemovePlayerRoles(pctx context.Context, grpcUrl string, req *playerPb.RemovePlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Player().RemovePlayerRoles(ctx, req)
+	if err != nil {
+		log.Printf("Error: RemovePlayerRoles failed: %s", err.Error())
+		return nil, errors.New("error: remove player roles failed")
+	}
+
+	return result, nil
+}
+
 func (r *authRepository) AccessToken(cfg *config.Config, claims *jwtauth.Claims) string {
 	return jwtauth.NewAccessToken(cfg.Jwt.AccessSecretKey, cfg.Jwt.AccessDuration, &jwtauth.Claims{
 		PlayerId: claims.PlayerId,
@@ -209,3 +702,259 @@ func (r *authRepository) RefreshToken(cfg *config.Config, claims *jwtauth.Claims
 		RoleCode: int(claims.RoleCode),
 	}).SignToken()
 }
+
+func (r *authRepository) FindOneTwoFactor(pctx context.Context, playerId string) (*auth.TwoFactor, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("two_factors")
+
+	result := new(auth.TwoFactor)
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("Error: FindOneTwoFactor failed: %s", err.Error())
+		return nil, errors.New("error: find two-factor failed")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) UpsertOneTwoFactor(pctx context.Context, req *auth.TwoFactor) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("two_factors")
+
+	req.UpdatedAt = utils.LocalTime()
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": req.PlayerId},
+		bson.M{
+			"$set": bson.M{
+				"secret":         req.Secret,
+				"is_enabled":     req.IsEnabled,
+				"recovery_codes": req.RecoveryCodes,
+				"last_used_step": req.LastUsedStep,
+				"updated_at":     req.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{
+				"created_at": req.UpdatedAt,
+			},
+		},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		log.Printf("Error: UpsertOneTwoFactor failed: %s", err.Error())
+		return errors.New("error: upsert two-factor failed")
+	}
+
+	return nil
+}
+
+// Note that: a step that is not newer than the last used one is rejected, so a code can not be replayed
+func (r *authRepository) UpdateOneTwoFactorLastUsedStep(pctx context.Context, playerId string, step int64) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("two_factors")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": playerId, "last_used_step": bson.M{"$lt": step}},
+		bson.M{
+			"$set": bson.M{
+				"last_used_step": step,
+				"updated_at":     utils.LocalTime(),
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOneTwoFactorLastUsedStep failed: %s", err.Error())
+		return errors.New("error: update two-factor failed")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("error: code is already used")
+	}
+
+	return nil
+}
+
+func (r *authRepository) UseOneTwoFactorRecoveryCode(pctx context.Context, playerId, codeHash string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("two_factors")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": playerId, "recovery_codes": codeHash},
+		bson.M{
+			"$pull": bson.M{"recovery_codes": codeHash},
+			"$set":  bson.M{"updated_at": utils.LocalTime()},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: UseOneTwoFactorRecoveryCode failed: %s", err.Error())
+		return errors.New("error: update two-factor failed")
+	}
+
+	if result.ModifiedCount == 0 {
+		return errors.New("error: code is invalid")
+	}
+
+	return nil
+}
+
+func (r *authRepository) DeleteOneTwoFactor(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("two_factors")
+
+	if _, err := col.DeleteOne(ctx, bson.M{"player_id": playerId}); err != nil {
+		log.Printf("Error: DeleteOneTwoFactor failed: %s", err.Error())
+		return errors.New("error: delete two-factor failed")
+	}
+
+	return nil
+}
+
+func (r *authRepository) InsertOneLoginChallenge(pctx context.Context, req *auth.LoginChallenge) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_challenges")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneLoginChallenge failed: %s", err.Error())
+		return errors.New("error: insert login challenge failed")
+	}
+
+	return nil
+}
+
+func (r *authRepository) FindOneLoginChallenge(pctx context.Context, tokenHash string) (*auth.LoginChallenge, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_challenges")
+
+	result := new(auth.LoginChallenge)
+	if err := col.FindOne(ctx, bson.M{"token_hash": tokenHash, "expires_at": bson.M{"$gt": utils.LocalTime()}}).Decode(result); err != nil {
+		log.Printf("Error: FindOneLoginChallenge failed: %s", err.Error())
+		return nil, errors.New("error: challenge token is invalid or expired")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) DeleteOneLoginChallenge(pctx context.Context, tokenHash string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("login_challenges")
+
+	if _, err := col.DeleteOne(ctx, bson.M{"token_hash": tokenHash}); err != nil {
+		log.Printf("Error: DeleteOneLoginChallenge failed: %s", err.Error())
+		return errors.New("error: delete login challenge failed")
+	}
+
+	return nil
+}
+
+func (r *authRepository) InsertOneOidcState(pctx context.Context, req *auth.OidcState) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("oidc_states")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneOidcState failed: %s", err.Error())
+		return errors.New("error: insert oidc state failed")
+	}
+
+	return nil
+}
+
+// Note that: the state is deleted in the same query, so it can be used only once
+func (r *authRepository) UseOneOidcState(pctx context.Context, stateHash string) (*auth.OidcState, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.authDbConn(ctx)
+	col := db.Collection("oidc_states")
+
+	result := new(auth.OidcState)
+	if err := col.FindOneAndDelete(ctx, bson.M{"state_hash": stateHash, "expires_at": bson.M{"$gt": utils.LocalTime()}}).Decode(result); err != nil {
+		log.Printf("Error: UseOneOidcState failed: %s", err.Error())
+		return nil, errors.New("error: state is invalid or expired")
+	}
+
+	return result, nil
+}
+
+func (r *authRepository) OidcAuthCodeUrl(pctx context.Context, provider *config.OidcProvider, state, nonce, codeChallenge string) (string, error) {
+	endpoints, err := oidc.Discover(pctx, provider)
+	if err != nil {
+		log.Printf("Error: OidcAuthCodeUrl failed: %s", err.Error())
+		return "", errors.New("error: identity provider is not available")
+	}
+
+	return oidc.AuthCodeUrl(endpoints, provider, state, nonce, codeChallenge), nil
+}
+
+// Note that: the id token is used when the provider returns one, otherwise the userinfo endpoint is used
+func (r *authRepository) OidcIdentity(pctx context.Context, provider *config.OidcProvider, code, codeVerifier, nonce string) (*oidc.Identity, error) {
+	endpoints, err := oidc.Discover(pctx, provider)
+	if err != nil {
+		log.Printf("Error: OidcIdentity failed: %s", err.Error())
+		return nil, errors.New("error: identity provider is not available")
+	}
+
+	token, err := oidc.Exchange(pctx, endpoints, provider, code, codeVerifier)
+	if err != nil {
+		log.Printf("Error: OidcIdentity failed: %s", err.Error())
+		return nil, errors.New("error: exchange authorization code failed")
+	}
+
+	if token.IdToken != "" {
+		return oidc.VerifyIdToken(pctx, endpoints, provider, token.IdToken, nonce)
+	}
+
+	return oidc.UserInfo(pctx, endpoints, token.AccessToken)
+}
+
+func (r *authRepository) FindOrCreatePlayerByIdentity(pctx context.Context, grpcUrl string, req *playerPb.FindOrCreatePlayerByIdentityReq) (*playerPb.PlayerProfile, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Player().FindOrCreatePlayerByIdentity(ctx, req)
+	if err != nil {
+		log.Printf("Error: FindOrCreatePlayerByIdentity failed: %s", err.Error())
+		if s, ok := status.FromError(err); ok {
+			return nil, errors.New(s.Message())
+		}
+		return nil, errors.New("error: find or create player failed")
+	}
+
+	return result, nil
+}
@@ -2,7 +2,12 @@ package authUsecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"time"
@@ -14,16 +19,45 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
 	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/oidc"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/totp"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type (
 	AuthUsecaseService interface {
+		GetOffset(pctx context.Context) (int64, error)
+		UpserOffset(pctx context.Context, offset int64) error
 		Login(pctx context.Context, cfg *config.Config, req *auth.PlayerLoginReq) (*auth.ProfileIntercepter, error)
+		LoginTwoFactor(pctx context.Context, cfg *config.Config, req *auth.LoginTwoFactorReq) (*auth.ProfileIntercepter, error)
+		EnrollTwoFactor(pctx context.Context, cfg *config.Config, playerId string) (*auth.TwoFactorEnrollRes, error)
+		ConfirmTwoFactor(pctx context.Context, req *auth.TwoFactorCodeReq) (*auth.TwoFactorRecoveryCodesRes, error)
+		DisableTwoFactor(pctx context.Context, cfg *config.Config, req *auth.TwoFactorCodeReq) error
+		RegenerateRecoveryCodes(pctx context.Context, cfg *config.Config, req *auth.TwoFactorCodeReq) (*auth.TwoFactorRecoveryCodesRes, error)
+		OidcAuthorize(pctx context.Context, cfg *config.Config, providerName string) (*auth.OidcAuthorizeRes, error)
+		OidcCallback(pctx context.Context, cfg *config.Config, req *auth.OidcCallbackReq) (*auth.ProfileIntercepter, error)
 		RefreshToken(pctx context.Context, cfg *config.Config, req *auth.RefreshTokenReq) (*auth.ProfileIntercepter, error)
 		Logout(pctx context.Context, credentialId string) (int64, error)
 		AccessTokenSearch(pctx context.Context, accessToken string) (*authPb.AccessTokenSearchRes, error)
 		RolesCount(pctx context.Context) (*authPb.RolesCountRes, error)
+		UnlockLogin(pctx context.Context, req *auth.UnlockLoginReq) (int64, error)
+		FindLoginAudits(pctx context.Context, req *auth.LoginAuditSearchReq) ([]*auth.LoginAudit, error)
+		FindPermissions(pctx context.Context, roleCode int) (*authPb.FindPermissionsRes, error)
+		CheckPermission(pctx context.Context, roleCode int, permission string) (*authPb.CheckPermissionRes, error)
+		RevokePlayerCredentials(pctx context.Context, playerId string) (*authPb.RevokePlayerCredentialsRes, error)
+		DeletePlayerAuthData(pctx context.Context, req *player.PlayerDeletedEvent)
+		BanPlayer(pctx context.Context, cfg *config.Config, req *auth.BanPlayerReq) (*auth.PlayerBan, error)
+		UnbanPlayer(pctx context.Context, playerId string) error
+		FindPlayerSessions(pctx context.Context, playerId string) (*authPb.FindPlayerSessionsRes, error)
+		CreateRole(pctx context.Context, req *auth.CreateRoleReq) (*auth.Role, error)
+		UpdateRolePermissions(pctx context.Context, roleCode int, req *auth.UpdateRolePermissionsReq) (*auth.Role, error)
+		FindAllRoles(pctx context.Context) ([]*auth.Role, error)
+		FindPlayerRoles(pctx context.Context, cfg *config.Config, playerId string) (*auth.PlayerRolesRes, error)
+		GrantPlayerRoles(pctx context.Context, cfg *config.Config, req *auth.InsertPlayerRole) (*auth.PlayerRolesRes, error)
+		RevokePlayerRole(pctx context.Context, cfg *config.Config, playerId string, roleCode int) (*auth.PlayerRolesRes, error)
 	}
 
 	authUsecase struct {
@@ -35,25 +69,257 @@ func NewAuthUsecase(authRepository authRepository.AuthRepositoryService) AuthUse
 	return &authUsecase{authRepository}
 }
 
+func (u *authUsecase) GetOffset(pctx context.Context) (int64, error) {
+	return u.authRepository.GetOffset(pctx)
+}
+func (u *authUsecase) UpserOffset(pctx context.Context, offset int64) error {
+	return u.authRepository.UpserOffset(pctx, offset)
+}
+
 func (u *authUsecase) Login(pctx context.Context, cfg *config.Config, req *auth.PlayerLoginReq) (*auth.ProfileIntercepter, error) {
+	emailKey := "email:" + strings.ToLower(req.Email)
+	ipKey := "ip:" + req.Ip
+
+	// Brute-force protection
+	if err := u.checkLoginAttempt(pctx, cfg, emailKey); err != nil {
+		u.insertLoginAudit(pctx, req, "", false, err.Error())
+		return nil, err
+	}
+	if req.Ip != "" {
+		if err := u.checkLoginAttempt(pctx, cfg, ipKey); err != nil {
+			u.insertLoginAudit(pctx, req, "", false, err.Error())
+			return nil, err
+		}
+	}
+
 	profile, err := u.authRepository.CredentialSearch(pctx, cfg.Grpc.PlayerUrl, &playerPb.CredentialSearchReq{
 		Email:    req.Email,
 		Password: req.Password,
 	})
+	if err != nil {
+		if errors.Is(err, authRepository.ErrCredentialInvalid) {
+			u.recordLoginFailure(pctx, cfg, emailKey, cfg.Login.LockThreshold)
+			if req.Ip != "" {
+				u.recordLoginFailure(pctx, cfg, ipKey, cfg.Login.IpLockThreshold)
+			}
+		}
+		u.insertLoginAudit(pctx, req, "", false, err.Error())
+		return nil, err
+	}
+
+	u.authRepository.DeleteOneLoginAttempt(pctx, emailKey)
+
+	return u.completeLogin(pctx, cfg, req, profile)
+}
+
+// Note that: it runs after the player is identified, by password or by an identity provider
+func (u *authUsecase) completeLogin(pctx context.Context, cfg *config.Config, req *auth.PlayerLoginReq, profile *playerPb.PlayerProfile) (*auth.ProfileIntercepter, error) {
+	if !profile.IsVerified && !cfg.Verify.AllowUnverifiedLogin {
+		u.insertLoginAudit(pctx, req, "player:"+profile.Id, false, "error: email is not verified")
+		return nil, errors.New("error: email is not verified")
+	}
+
+	if err := u.checkPlayerBan(pctx, profile.Id); err != nil {
+		u.insertLoginAudit(pctx, req, "player:"+profile.Id, false, err.Error())
+		return nil, err
+	}
+
+	profile.Id = "player:" + profile.Id
+
+	// Two-factor authentication
+	twoFactor, err := u.authRepository.FindOneTwoFactor(pctx, profile.Id)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.IsEnabled {
+		u.insertLoginAudit(pctx, req, profile.Id, false, "two-factor challenge is issued")
+		return u.newLoginChallenge(pctx, cfg, profile)
+	}
+
+	return u.issueCredential(pctx, cfg, req, profile, false)
+}
+
+func (u *authUsecase) OidcAuthorize(pctx context.Context, cfg *config.Config, providerName string) (*auth.OidcAuthorizeRes, error) {
+	provider, ok := cfg.Oidc.Providers[strings.ToLower(providerName)]
+	if !ok {
+		return nil, errors.New("error: identity provider is not supported")
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationUrl, err := u.authRepository.OidcAuthCodeUrl(pctx, provider, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	expiresAt := now.Add(time.Duration(cfg.Oidc.StateDuration) * time.Second)
+
+	if err := u.authRepository.InsertOneOidcState(pctx, &auth.OidcState{
+		StateHash:    hashToken(state),
+		Provider:     provider.Name,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &auth.OidcAuthorizeRes{
+		AuthorizationUrl: authorizationUrl,
+		State:            state,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+func (u *authUsecase) OidcCallback(pctx context.Context, cfg *config.Config, req *auth.OidcCallbackReq) (*auth.ProfileIntercepter, error) {
+	if req.Error != "" {
+		return nil, fmt.Errorf("error: identity provider returned: %s", req.Error)
+	}
+	if req.Code == "" || req.State == "" {
+		return nil, errors.New("error: code and state are required")
+	}
+
+	provider, ok := cfg.Oidc.Providers[strings.ToLower(req.Provider)]
+	if !ok {
+		return nil, errors.New("error: identity provider is not supported")
+	}
+
+	state, err := u.authRepository.UseOneOidcState(pctx, hashToken(req.State))
+	if err != nil {
+		return nil, err
+	}
+	if state.Provider != provider.Name {
+		return nil, errors.New("error: state is invalid or expired")
+	}
+
+	identity, err := u.authRepository.OidcIdentity(pctx, provider, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Subject == "" {
+		return nil, errors.New("error: identity provider did not return a subject")
+	}
+
+	loginReq := &auth.PlayerLoginReq{
+		Email:     identity.Email,
+		Ip:        req.Ip,
+		UserAgent: req.UserAgent,
+	}
+
+	profile, err := u.authRepository.FindOrCreatePlayerByIdentity(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOrCreatePlayerByIdentityReq{
+		Provider:      provider.Name,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Username:      identity.Name,
+	})
+	if err != nil {
+		u.insertLoginAudit(pctx, loginReq, "", false, err.Error())
+		return nil, err
+	}
+
+	return u.completeLogin(pctx, cfg, loginReq, profile)
+}
+
+func (u *authUsecase) newLoginChallenge(pctx context.Context, cfg *config.Config, profile *playerPb.PlayerProfile) (*auth.ProfileIntercepter, error) {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.LocalTime()
+	expiresAt := now.Add(time.Duration(cfg.TwoFactor.ChallengeDuration) * time.Second)
+
+	if err := u.authRepository.InsertOneLoginChallenge(pctx, &auth.LoginChallenge{
+		TokenHash: tokenHash,
+		PlayerId:  profile.Id,
+		Email:     profile.Email,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &auth.ProfileIntercepter{
+		Challenge: &auth.LoginChallengeRes{
+			ChallengeToken: token,
+			ExpiresAt:      expiresAt,
+		},
+	}, nil
+}
+
+func (u *authUsecase) LoginTwoFactor(pctx context.Context, cfg *config.Config, req *auth.LoginTwoFactorReq) (*auth.ProfileIntercepter, error) {
+	if req.ChallengeToken == "" || req.Code == "" {
+		return nil, errors.New("error: challenge_token and code are required")
+	}
+
+	tokenHash := hashToken(req.ChallengeToken)
+
+	challenge, err := u.authRepository.FindOneLoginChallenge(pctx, tokenHash)
 	if err != nil {
 		return nil, err
 	}
 
+	loginReq := &auth.PlayerLoginReq{
+		Email:     challenge.Email,
+		Ip:        req.Ip,
+		UserAgent: req.UserAgent,
+	}
+
+	if err := u.verifyTwoFactorCode(pctx, cfg, challenge.PlayerId, req.Code); err != nil {
+		if errors.Is(err, errTwoFactorLocked) {
+			u.authRepository.DeleteOneLoginChallenge(pctx, tokenHash)
+		}
+		u.insertLoginAudit(pctx, loginReq, challenge.PlayerId, false, err.Error())
+		return nil, err
+	}
+
+	// Note that: the challenge can be used only once
+	if err := u.authRepository.DeleteOneLoginChallenge(pctx, tokenHash); err != nil {
+		return nil, err
+	}
+
+	// The player may have been banned after the challenge is issued
+	if err := u.checkPlayerBan(pctx, challenge.PlayerId); err != nil {
+		u.insertLoginAudit(pctx, loginReq, challenge.PlayerId, false, err.Error())
+		return nil, err
+	}
+
+	profile, err := u.authRepository.FindOnePlayerProfileToRefresh(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+		PlayerId: strings.TrimPrefix(challenge.PlayerId, "player:"),
+	})
+	if err != nil {
+		return nil, err
+	}
 	profile.Id = "player:" + profile.Id
 
+	return u.issueCredential(pctx, cfg, loginReq, profile, true)
+}
+
+func (u *authUsecase) issueCredential(pctx context.Context, cfg *config.Config, req *auth.PlayerLoginReq, profile *playerPb.PlayerProfile, mfa bool) (*auth.ProfileIntercepter, error) {
 	accessToken := u.authRepository.AccessToken(cfg, &jwtauth.Claims{
 		PlayerId: profile.Id,
 		RoleCode: int(profile.RoleCode),
+		Mfa:      mfa,
 	})
 
 	refreshToken := u.authRepository.RefreshToken(cfg, &jwtauth.Claims{
 		PlayerId: profile.Id,
 		RoleCode: int(profile.RoleCode),
+		Mfa:      mfa,
 	})
 
 	credentialId, err := u.authRepository.InsertOnePlayerCredential(pctx, &auth.Credential{
@@ -68,6 +334,8 @@ func (u *authUsecase) Login(pctx context.Context, cfg *config.Config, req *auth.
 		return nil, err
 	}
 
+	u.insertLoginAudit(pctx, req, profile.Id, true, "")
+
 	loc, _ := time.LoadLocation("Asia/Bangkok")
 
 	return &auth.ProfileIntercepter{
@@ -90,6 +358,328 @@ func (u *authUsecase) Login(pctx context.Context, cfg *config.Config, req *auth.
 	}, nil
 }
 
+// Note that: the codes of a player are locked after this many wrong codes, on every challenge and every endpoint of the codes
+const maxTwoFactorFailed = 5
+
+var errTwoFactorLocked = errors.New("error: too many failed attempts, please try again later")
+
+func (u *authUsecase) EnrollTwoFactor(pctx context.Context, cfg *config.Config, playerId string) (*auth.TwoFactorEnrollRes, error) {
+	twoFactor, err := u.authRepository.FindOneTwoFactor(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.IsEnabled {
+		return nil, errors.New("error: two-factor authentication is already enabled")
+	}
+
+	profile, err := u.authRepository.FindOnePlayerProfileToRefresh(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+		PlayerId: strings.TrimPrefix(playerId, "player:"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that: the secret is not active until it is confirmed with a valid code
+	if err := u.authRepository.UpsertOneTwoFactor(pctx, &auth.TwoFactor{
+		PlayerId:      playerId,
+		Secret:        secret,
+		IsEnabled:     false,
+		RecoveryCodes: make([]string, 0),
+		LastUsedStep:  0,
+	}); err != nil {
+		return nil, err
+	}
+
+	issuer := cfg.TwoFactor.Issuer
+	if issuer == "" {
+		issuer = "HelloSekaiShop"
+	}
+
+	return &auth.TwoFactorEnrollRes{
+		Secret:          secret,
+		ProvisioningUri: totp.ProvisioningUri(issuer, profile.Email, secret),
+	}, nil
+}
+
+func (u *authUsecase) ConfirmTwoFactor(pctx context.Context, req *auth.TwoFactorCodeReq) (*auth.TwoFactorRecoveryCodesRes, error) {
+	twoFactor, err := u.authRepository.FindOneTwoFactor(pctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, errors.New("error: two-factor authentication is not enrolled")
+	}
+	if twoFactor.IsEnabled {
+		return nil, errors.New("error: two-factor authentication is already enabled")
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.New("error: code is invalid")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor.IsEnabled = true
+	twoFactor.RecoveryCodes = hashes
+	twoFactor.LastUsedStep = step
+
+	if err := u.authRepository.UpsertOneTwoFactor(pctx, twoFactor); err != nil {
+		return nil, err
+	}
+
+	return &auth.TwoFactorRecoveryCodesRes{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (u *authUsecase) DisableTwoFactor(pctx context.Context, cfg *config.Config, req *auth.TwoFactorCodeReq) error {
+	if err := u.verifyTwoFactorCode(pctx, cfg, req.PlayerId, req.Code); err != nil {
+		return err
+	}
+
+	return u.authRepository.DeleteOneTwoFactor(pctx, req.PlayerId)
+}
+
+func (u *authUsecase) RegenerateRecoveryCodes(pctx context.Context, cfg *config.Config, req *auth.TwoFactorCodeReq) (*auth.TwoFactorRecoveryCodesRes, error) {
+	if err := u.verifyTwoFactorCode(pctx, cfg, req.PlayerId, req.Code); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := u.authRepository.FindOneTwoFactor(pctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, errors.New("error: two-factor authentication is not enabled")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.RecoveryCodes = hashes
+
+	if err := u.authRepository.UpsertOneTwoFactor(pctx, twoFactor); err != nil {
+		return nil, err
+	}
+
+	return &auth.TwoFactorRecoveryCodesRes{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Note that: the code is either a TOTP code or one of the recovery codes, every code counts as a failure of the player
+// before it is checked, so concurrent guesses can not pass the limit, the failures are reset when a code is valid
+func (u *authUsecase) verifyTwoFactorCode(pctx context.Context, cfg *config.Config, playerId, code string) error {
+	twoFactor, err := u.authRepository.FindOneTwoFactor(pctx, playerId)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled {
+		return errors.New("error: two-factor authentication is not enabled")
+	}
+
+	// Note that: only the lock is checked, the delay of the login is not used for the codes,
+	// a delay is not a lock and the challenge of the player must not be dropped by it
+	key := "2fa:" + playerId
+	now := utils.LocalTime()
+	if attempt, err := u.authRepository.FindOneLoginAttempt(pctx, key); err == nil && attempt != nil && now.Before(attempt.LockedUntil) {
+		log.Printf("Error: Two-factor is locked: %s", key)
+		return errTwoFactorLocked
+	}
+
+	attempt, err := u.authRepository.IncreaseOneLoginAttempt(pctx, key, now, now.Add(-time.Duration(cfg.Login.AttemptWindow)*time.Second))
+	if err != nil {
+		return err
+	}
+	if attempt.FailedCount > maxTwoFactorFailed {
+		log.Printf("Warning: Two-factor is locked: %s", key)
+		u.authRepository.LockOneLoginAttempt(pctx, key, maxTwoFactorFailed+1, now.Add(time.Duration(cfg.Login.LockDuration)*time.Second))
+		return errTwoFactorLocked
+	}
+
+	if err := u.checkTwoFactorCode(pctx, twoFactor, playerId, code); err != nil {
+		return err
+	}
+
+	u.authRepository.DeleteOneLoginAttempt(pctx, key)
+	return nil
+}
+
+func (u *authUsecase) checkTwoFactorCode(pctx context.Context, twoFactor *auth.TwoFactor, playerId, code string) error {
+	if step, ok := totp.Validate(twoFactor.Secret, code, time.Now()); ok {
+		return u.authRepository.UpdateOneTwoFactorLastUsedStep(pctx, playerId, step)
+	}
+
+	return u.authRepository.UseOneTwoFactorRecoveryCode(pctx, playerId, hashToken(normalizeRecoveryCode(code)))
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0)
+	hashes := make([]string, 0)
+
+	for i := 0; i < 10; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			log.Printf("Error: newRecoveryCodes: %s", err.Error())
+			return nil, nil, errors.New("error: generate recovery codes failed")
+		}
+
+		code := base32.StdEncoding.EncodeToString(raw)
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, hashToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// Note that: only the hash of the token is stored
+func newToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("Error: newToken: %s", err.Error())
+		return "", "", errors.New("error: generate token failed")
+	}
+
+	token := hex.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	hashed := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hashed[:])
+}
+
+func (u *authUsecase) checkLoginAttempt(pctx context.Context, cfg *config.Config, key string) error {
+	attempt, err := u.authRepository.FindOneLoginAttempt(pctx, key)
+	if err != nil || attempt == nil {
+		return nil
+	}
+
+	now := utils.LocalTime()
+
+	if now.Before(attempt.LockedUntil) {
+		log.Printf("Error: Login is locked: %s", key)
+		return fmt.Errorf("error: too many failed login attempts, try again in %s", attempt.LockedUntil.Sub(now).Round(time.Second))
+	}
+
+	retryAt := attempt.LastFailedAt.Add(loginDelay(cfg, attempt.FailedCount))
+	if now.Before(retryAt) {
+		log.Printf("Error: Login is delayed: %s", key)
+		return fmt.Errorf("error: too many failed login attempts, try again in %s", retryAt.Sub(now).Round(time.Second))
+	}
+
+	return nil
+}
+
+// Note that: the failure is counted atomically, concurrent failures are all counted and the lock is decided
+// on the count that the database returns
+func (u *authUsecase) recordLoginFailure(pctx context.Context, cfg *config.Config, key string, lockThreshold int64) {
+	now := utils.LocalTime()
+
+	attempt, err := u.authRepository.IncreaseOneLoginAttempt(pctx, key, now, now.Add(-time.Duration(cfg.Login.AttemptWindow)*time.Second))
+	if err != nil {
+		return
+	}
+
+	if attempt.FailedCount >= lockThreshold {
+		log.Printf("Warning: Login is locked: %s", key)
+		u.authRepository.LockOneLoginAttempt(pctx, key, lockThreshold, now.Add(time.Duration(cfg.Login.LockDuration)*time.Second))
+	}
+}
+
+// Note that: the delay is doubled on every failure after the threshold, capped at the max delay duration
+func loginDelay(cfg *config.Config, failedCount int64) time.Duration {
+	if failedCount < cfg.Login.DelayThreshold {
+		return 0
+	}
+
+	exponent := failedCount - cfg.Login.DelayThreshold
+	if exponent > 30 {
+		return time.Duration(cfg.Login.MaxDelayDuration) * time.Second
+	}
+
+	delay := int64(1) << exponent
+	if delay > cfg.Login.MaxDelayDuration {
+		delay = cfg.Login.MaxDelayDuration
+	}
+	return time.Duration(delay) * time.Second
+}
+
+func (u *authUsecase) insertLoginAudit(pctx context.Context, req *auth.PlayerLoginReq, playerId string, isSuccess bool, reason string) {
+	u.authRepository.InsertOneLoginAudit(pctx, &auth.LoginAudit{
+		Email:     strings.ToLower(req.Email),
+		PlayerId:  playerId,
+		Ip:        req.Ip,
+		UserAgent: req.UserAgent,
+		IsSuccess: isSuccess,
+		Reason:    reason,
+	})
+}
+
+func (u *authUsecase) UnlockLogin(pctx context.Context, req *auth.UnlockLoginReq) (int64, error) {
+	if req.Email == "" && req.Ip == "" {
+		return -1, errors.New("error: email or ip is required")
+	}
+
+	deletedCount := int64(0)
+	if req.Email != "" {
+		count, err := u.authRepository.DeleteOneLoginAttempt(pctx, "email:"+strings.ToLower(req.Email))
+		if err != nil {
+			return -1, err
+		}
+		deletedCount += count
+	}
+	if req.Ip != "" {
+		count, err := u.authRepository.DeleteOneLoginAttempt(pctx, "ip:"+req.Ip)
+		if err != nil {
+			return -1, err
+		}
+		deletedCount += count
+	}
+
+	return deletedCount, nil
+}
+
+func (u *authUsecase) FindLoginAudits(pctx context.Context, req *auth.LoginAuditSearchReq) ([]*auth.LoginAudit, error) {
+	filter := bson.D{}
+
+	if req.Email != "" {
+		filter = append(filter, bson.E{"email", strings.ToLower(req.Email)})
+	}
+	if req.PlayerId != "" {
+		filter = append(filter, bson.E{"player_id", "player:" + strings.TrimPrefix(req.PlayerId, "player:")})
+	}
+	if req.Ip != "" {
+		filter = append(filter, bson.E{"ip", req.Ip})
+	}
+
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	opts := make([]*options.FindOptions, 0)
+	opts = append(opts, options.Find().SetSort(bson.D{{"created_at", -1}}))
+	opts = append(opts, options.Find().SetLimit(int64(req.Limit)))
+
+	return u.authRepository.FindManyLoginAudits(pctx, filter, opts)
+}
+
 func (u *authUsecase) RefreshToken(pctx context.Context, cfg *config.Config, req *auth.RefreshTokenReq) (*auth.ProfileIntercepter, error) {
 	claims, err := jwtauth.ParseToken(cfg.Jwt.RefreshSecretKey, req.RefreshToken)
 	if err != nil {
@@ -97,6 +687,10 @@ func (u *authUsecase) RefreshToken(pctx context.Context, cfg *config.Config, req
 		return nil, errors.New(err.Error())
 	}
 
+	if err := u.checkPlayerBan(pctx, claims.PlayerId); err != nil {
+		return nil, err
+	}
+
 	profile, err := u.authRepository.FindOnePlayerProfileToRefresh(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
 		PlayerId: strings.TrimPrefix(claims.PlayerId, "player:"),
 	})
@@ -107,11 +701,13 @@ func (u *authUsecase) RefreshToken(pctx context.Context, cfg *config.Config, req
 	accessToken := jwtauth.NewAccessToken(cfg.Jwt.AccessSecretKey, cfg.Jwt.AccessDuration, &jwtauth.Claims{
 		PlayerId: profile.Id,
 		RoleCode: int(profile.RoleCode),
+		Mfa:      claims.Mfa,
 	}).SignToken()
 
 	refreshToken := jwtauth.ReloadToken(cfg.Jwt.RefreshSecretKey, claims.ExpiresAt.Unix(), &jwtauth.Claims{
 		PlayerId: profile.Id,
 		RoleCode: int(profile.RoleCode),
+		Mfa:      claims.Mfa,
 	})
 
 	if err := u.authRepository.UpdateOnePlayerCredential(pctx, req.CredentialId, &auth.UpdateRefreshTokenReq{
@@ -168,6 +764,14 @@ func (u *authUsecase) AccessTokenSearch(pctx context.Context, accessToken string
 		}, errors.New("error: access token is invalid")
 	}
 
+	// Note that: a banned player is told apart from an invalid token, so the middleware can say why
+	if err := u.checkPlayerBan(pctx, credential.PlayerId); err != nil {
+		return &authPb.AccessTokenSearchRes{
+			IsValid:  false,
+			IsBanned: true,
+		}, nil
+	}
+
 	return &authPb.AccessTokenSearchRes{
 		IsValid: true,
 	}, nil
@@ -183,3 +787,384 @@ func (u *authUsecase) RolesCount(pctx context.Context) (*authPb.RolesCountRes, e
 		Count: result,
 	}, nil
 }
+
+func (u *authUsecase) FindPermissions(pctx context.Context, roleCode int) (*authPb.FindPermissionsRes, error) {
+	roles, err := u.authRepository.FindAllRoles(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	setPermissions := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, r := range roles {
+		if !rbac.HasRole(roleCode, r.Code) {
+			continue
+		}
+		for _, p := range r.Permissions {
+			if !setPermissions[p] {
+				setPermissions[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+
+	return &authPb.FindPermissionsRes{
+		Permissions: permissions,
+	}, nil
+}
+
+func (u *authUsecase) CheckPermission(pctx context.Context, roleCode int, permission string) (*authPb.CheckPermissionRes, error) {
+	result, err := u.FindPermissions(pctx, roleCode)
+	if err != nil {
+		return nil, err
+	}
+
+	permission = rbac.NormalizePermission(permission)
+	for _, p := range result.Permissions {
+		if p == permission {
+			return &authPb.CheckPermissionRes{
+				IsAllowed: true,
+			}, nil
+		}
+	}
+
+	return &authPb.CheckPermissionRes{
+		IsAllowed: false,
+	}, nil
+}
+
+func (u *authUsecase) RevokePlayerCredentials(pctx context.Context, playerId string) (*authPb.RevokePlayerCredentialsRes, error) {
+	if playerId == "" {
+		return nil, errors.New("error: player_id is required")
+	}
+
+	result, err := u.authRepository.DeleteManyPlayerCredentials(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authPb.RevokePlayerCredentialsRes{
+		RevokedCount: result,
+	}, nil
+}
+
+func (u *authUsecase) checkPlayerBan(pctx context.Context, playerId string) error {
+	ban, err := u.authRepository.FindOnePlayerBan(pctx, strings.TrimPrefix(playerId, "player:"))
+	if err != nil {
+		return err
+	}
+	if ban == nil {
+		return nil
+	}
+
+	if ban.ExpiresAt.IsZero() {
+		return fmt.Errorf("error: player is banned: %s", ban.Reason)
+	}
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+	return fmt.Errorf("error: player is suspended until %s: %s", ban.ExpiresAt.In(loc).Format(time.RFC3339), ban.Reason)
+}
+
+func (u *authUsecase) BanPlayer(pctx context.Context, cfg *config.Config, req *auth.BanPlayerReq) (*auth.PlayerBan, error) {
+	playerId := strings.TrimPrefix(req.PlayerId, "player:")
+	reason := strings.TrimSpace(req.Reason)
+	now := utils.LocalTime()
+
+	if playerId == "" {
+		return nil, errors.New("error: player_id is required")
+	}
+	if playerId == strings.TrimPrefix(req.CreatedBy, "player:") {
+		return nil, errors.New("error: you can not ban yourself")
+	}
+	if reason == "" || len(reason) > 255 {
+		return nil, errors.New("error: reason is required and must not be longer than 255 characters")
+	}
+
+	switch req.Kind {
+	case "ban":
+		if !req.ExpiresAt.IsZero() && !req.ExpiresAt.After(now) {
+			return nil, errors.New("error: expires_at must be in the future")
+		}
+	case "suspend":
+		if !req.ExpiresAt.After(now) {
+			return nil, errors.New("error: expires_at in the future is required to suspend a player")
+		}
+	default:
+		return nil, errors.New("error: kind must be ban or suspend")
+	}
+
+	if _, err := u.authRepository.FindOnePlayerProfileToRefresh(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+		PlayerId: playerId,
+	}); err != nil {
+		return nil, err
+	}
+
+	ban := &auth.PlayerBan{
+		PlayerId:  playerId,
+		Kind:      req.Kind,
+		Reason:    reason,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: now,
+	}
+	if err := u.authRepository.UpsertOnePlayerBan(pctx, ban); err != nil {
+		return nil, err
+	}
+
+	// Sign out every session, the ban is enforced on the next request anyway
+	if _, err := u.authRepository.DeleteManyPlayerCredentials(pctx, playerId); err != nil {
+		log.Printf("Error: BanPlayer: %s", err.Error())
+	}
+
+	return u.authRepository.FindOnePlayerBan(pctx, playerId)
+}
+
+func (u *authUsecase) UnbanPlayer(pctx context.Context, playerId string) error {
+	result, err := u.authRepository.DeleteOnePlayerBan(pctx, strings.TrimPrefix(playerId, "player:"))
+	if err != nil {
+		return err
+	}
+	if result == 0 {
+		return errors.New("error: player is not banned")
+	}
+	return nil
+}
+
+func (u *authUsecase) FindPlayerSessions(pctx context.Context, playerId string) (*authPb.FindPlayerSessionsRes, error) {
+	playerId = strings.TrimPrefix(playerId, "player:")
+	if playerId == "" {
+		return nil, errors.New("error: player_id is required")
+	}
+
+	credentials, err := u.authRepository.FindManyPlayerCredentials(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	ban, err := u.authRepository.FindOnePlayerBan(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	res := &authPb.FindPlayerSessionsRes{
+		Sessions: make([]*authPb.PlayerSession, 0),
+	}
+	for _, v := range credentials {
+		res.Sessions = append(res.Sessions, &authPb.PlayerSession{
+			CredentialId: v.Id.Hex(),
+			RoleCode:     int64(v.RoleCode),
+			CreatedAt:    v.CreatedAt.In(loc).String(),
+			UpdatedAt:    v.UpdatedAt.In(loc).String(),
+		})
+	}
+	if ban != nil {
+		res.Ban = &authPb.PlayerBanStatus{
+			Kind:      ban.Kind,
+			Reason:    ban.Reason,
+			CreatedBy: ban.CreatedBy,
+			CreatedAt: ban.CreatedAt.In(loc).String(),
+		}
+		if !ban.ExpiresAt.IsZero() {
+			res.Ban.ExpiresAt = ban.ExpiresAt.In(loc).String()
+		}
+	}
+
+	return res, nil
+}
+
+// Note that: the player is anonymized by the player service, everything of the player in auth is removed here
+func (u *authUsecase) DeletePlayerAuthData(pctx context.Context, req *player.PlayerDeletedEvent) {
+	playerId := strings.TrimPrefix(req.PlayerId, "player:")
+
+	if _, err := u.authRepository.DeleteManyPlayerCredentials(pctx, playerId); err != nil {
+		log.Printf("Error: DeletePlayerAuthData: %s", err.Error())
+	}
+	if err := u.authRepository.DeleteOneTwoFactor(pctx, playerId); err != nil {
+		log.Printf("Error: DeletePlayerAuthData: %s", err.Error())
+	}
+	if err := u.authRepository.DeleteManyLoginAudits(pctx, playerId); err != nil {
+		log.Printf("Error: DeletePlayerAuthData: %s", err.Error())
+	}
+}
+
+func (u *authUsecase) CreateRole(pctx context.Context, req *auth.CreateRoleReq) (*auth.Role, error) {
+	req.Title = strings.ToLower(strings.TrimSpace(req.Title))
+	if req.Title == "" {
+		return nil, errors.New("error: title is required")
+	}
+
+	permissions, err := validatePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.authRepository.IsUniqueRole(pctx, req.Title) {
+		return nil, errors.New("error: this role is already exist")
+	}
+
+	roles, err := u.authRepository.FindAllRoles(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that: role code is a bit of the player's role bitmask, so a new role takes the lowest unused bit
+	usedCodes := make(map[int]bool)
+	for _, r := range roles {
+		usedCodes[r.Code] = true
+	}
+	code := 1
+	for usedCodes[code] {
+		code <<= 1
+	}
+
+	roleId, err := u.authRepository.InsertOneRole(pctx, &auth.Role{
+		Title:       req.Title,
+		Code:        code,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Role{
+		Id:          roleId,
+		Title:       req.Title,
+		Code:        code,
+		Permissions: permissions,
+	}, nil
+}
+
+func (u *authUsecase) UpdateRolePermissions(pctx context.Context, roleCode int, req *auth.UpdateRolePermissionsReq) (*auth.Role, error) {
+	permissions, err := validatePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.authRepository.FindOneRole(pctx, roleCode); err != nil {
+		return nil, err
+	}
+
+	if err := u.authRepository.UpdateOneRolePermissions(pctx, roleCode, permissions); err != nil {
+		return nil, err
+	}
+
+	return u.authRepository.FindOneRole(pctx, roleCode)
+}
+
+func validatePermissions(req []string) ([]string, error) {
+	setPermissions := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, p := range req {
+		p = rbac.NormalizePermission(p)
+		if !rbac.IsValidPermission(p) {
+			return nil, fmt.Errorf("error: permission %s is invalid", p)
+		}
+		if !setPermissions[p] {
+			setPermissions[p] = true
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions, nil
+}
+
+func (u *authUsecase) FindAllRoles(pctx context.Context) ([]*auth.Role, error) {
+	return u.authRepository.FindAllRoles(pctx)
+}
+
+func (u *authUsecase) FindPlayerRoles(pctx context.Context, cfg *config.Config, playerId string) (*auth.PlayerRolesRes, error) {
+	result, err := u.authRepository.FindPlayerRoles(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindPlayerRolesReq{
+		PlayerId: strings.TrimPrefix(playerId, "player:"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return playerRolesToRes(result, 0), nil
+}
+
+func (u *authUsecase) GrantPlayerRoles(pctx context.Context, cfg *config.Config, req *auth.InsertPlayerRole) (*auth.PlayerRolesRes, error) {
+	req.PlayerId = strings.TrimPrefix(req.PlayerId, "player:")
+	if len(req.RoleCode) == 0 {
+		return nil, errors.New("error: role_code is required")
+	}
+
+	roles, err := u.authRepository.FindAllRoles(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roleMaps := make(map[int]*auth.Role)
+	for _, r := range roles {
+		roleMaps[r.Code] = r
+	}
+
+	rolesReq := make([]*playerPb.PlayerRole, 0)
+	for _, code := range req.RoleCode {
+		role, ok := roleMaps[code]
+		if !ok {
+			return nil, fmt.Errorf("error: role_code %d not found", code)
+		}
+
+		rolesReq = append(rolesReq, &playerPb.PlayerRole{
+			RoleTitle: role.Title,
+			RoleCode:  int32(role.Code),
+		})
+	}
+
+	result, err := u.authRepository.AddPlayerRoles(pctx, cfg.Grpc.PlayerUrl, &playerPb.AddPlayerRolesReq{
+		PlayerId: req.PlayerId,
+		Roles:    rolesReq,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoke every existing token, the new role code shows up on the next login
+	revoked, err := u.authRepository.DeleteManyPlayerCredentials(pctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return playerRolesToRes(result, revoked), nil
+}
+
+func (u *authUsecase) RevokePlayerRole(pctx context.Context, cfg *config.Config, playerId string, roleCode int) (*auth.PlayerRolesRes, error) {
+	playerId = strings.TrimPrefix(playerId, "player:")
+	if roleCode == 0 {
+		return nil, errors.New("error: player role can not be revoked")
+	}
+
+	result, err := u.authRepository.RemovePlayerRoles(pctx, cfg.Grpc.PlayerUrl, &playerPb.RemovePlayerRolesReq{
+		PlayerId:  playerId,
+		RoleCodes: []int32{int32(roleCode)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := u.authRepository.DeleteManyPlayerCredentials(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return playerRolesToRes(result, revoked), nil
+}
+
+func playerRolesToRes(req *playerPb.PlayerRolesRes, revoked int64) *auth.PlayerRolesRes {
+	res := &auth.PlayerRolesRes{
+		PlayerId:          "player:" + req.PlayerId,
+		RoleCode:          0,
+		Roles:             make([]player.PlayerRole, 0),
+		RevokedCredential: revoked,
+	}
+	for _, r := range req.Roles {
+		res.RoleCode += int(r.RoleCode)
+		res.Roles = append(res.Roles, player.PlayerRole{
+			RoleTitle: r.RoleTitle,
+			RoleCode:  int(r.RoleCode),
+		})
+	}
+	return res
+}
@@ -1,11 +1,35 @@
 package inventory
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type (
 	Inventory struct {
 		Id       primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
 		PlayerId string             `json:"player_id" bson:"player_id"`
 		ItemId   string             `json:"item_id" bson:"item_id"`
+		// The revision of the item that was bought
+		ItemRevision int `json:"item_revision,omitempty" bson:"item_revision,omitempty"`
+		// The order of the payment that added the item
+		OrderId string `json:"order_id,omitempty" bson:"order_id,omitempty"`
+		// The order that is removing the item, it is only set while the remove runs
+		RemovingBy string `json:"-" bson:"removing_by,omitempty"`
+	}
+
+	// Note that: a rollback is kept by the order, an add or a remove of the order that comes after it undoes itself
+	InventoryRollback struct {
+		OrderId   string    `bson:"_id"`
+		CreatedAt time.Time `bson:"created_at"`
+	}
+
+	// Note that: the items that were removed by the order, they are given back when the order is rolled back
+	InventoryRemoved struct {
+		OrderId   string       `bson:"_id"`
+		PlayerId  string       `bson:"player_id"`
+		Items     []*Inventory `bson:"items"`
+		CreatedAt time.Time    `bson:"created_at"`
 	}
 )
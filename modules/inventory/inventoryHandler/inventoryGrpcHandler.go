@@ -0,0 +1,28 @@
+package inventoryHandler
+
+import (
+	"context"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	inventoryPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryUsecase"
+)
+
+type (
+	inventoryGrpcHandler struct {
+		inventoryPb.UnimplementedInventoryGrpcServiceServer
+		cfg              *config.Config
+		inventoryUsecase inventoryUsecase.InventoryUsecaseService
+	}
+)
+
+func NewInventoryGrpcHandler(cfg *config.Config, inventoryUsecase inventoryUsecase.InventoryUsecaseService) *inventoryGrpcHandler {
+	return &inventoryGrpcHandler{
+		cfg:              cfg,
+		inventoryUsecase: inventoryUsecase,
+	}
+}
+
+func (g *inventoryGrpcHandler) FindPlayerInventory(ctx context.Context, req *inventoryPb.FindPlayerInventoryReq) (*inventoryPb.FindPlayerInventoryRes, error) {
+	return g.inventoryUsecase.FindPlayerInventory(ctx, g.cfg, req)
+}
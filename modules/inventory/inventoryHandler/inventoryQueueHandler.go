@@ -11,6 +11,7 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
 )
 
@@ -18,8 +19,10 @@ type (
 	InventoryQueueHandlerService interface {
 		AddPlayerItem()
 		RemovePlayerItem()
+		RefundPlayerItem()
 		RollbackAddPlayerItem()
 		RollbackRemovePlayerItem()
+		DeletePlayerItems()
 	}
 
 	inventoryQueueHandler struct {
@@ -179,6 +182,46 @@ func (h *inventoryQueueHandler) RemovePlayerItem() {
 	}
 }
 
+func (h *inventoryQueueHandler) RefundPlayerItem() {
+	ctx := context.Background()
+
+	consumer, err := h.InventoryConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Start RefundPlayerItem ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Println("Error: RefundPlayerItem failed: ", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			if string(msg.Key) == "refund" {
+				h.inventoryUsecase.UpserOffset(ctx, msg.Offset+1)
+
+				req := new(inventory.UpdateInventoryReq)
+
+				if err := queue.DecodeMessage(req, msg.Value); err != nil {
+					continue
+				}
+
+				h.inventoryUsecase.RefundPlayerItemRes(ctx, h.cfg, req)
+
+				log.Printf("RefundPlayerItem | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigchan:
+			log.Println("Stop RefundPlayerItem...")
+			return
+		}
+	}
+}
+
 func (h *inventoryQueueHandler) RollbackRemovePlayerItem() {
 	ctx := context.Background()
 
@@ -218,3 +261,43 @@ func (h *inventoryQueueHandler) RollbackRemovePlayerItem() {
 		}
 	}
 }
+
+func (h *inventoryQueueHandler) DeletePlayerItems() {
+	ctx := context.Background()
+
+	consumer, err := h.InventoryConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Start DeletePlayerItems ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Println("Error: DeletePlayerItems failed: ", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			if string(msg.Key) == "pdelete" {
+				h.inventoryUsecase.UpserOffset(ctx, msg.Offset+1)
+
+				req := new(player.PlayerDeletedEvent)
+
+				if err := queue.DecodeMessage(req, msg.Value); err != nil {
+					continue
+				}
+
+				h.inventoryUsecase.DeletePlayerItems(ctx, req)
+
+				log.Printf("DeletePlayerItems | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigchan:
+			log.Println("Stop DeletePlayerItems...")
+			return
+		}
+	}
+}
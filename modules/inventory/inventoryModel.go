@@ -6,14 +6,26 @@ import (
 )
 
 type (
+	// Note that: the items of one order are added or removed together, all of them or none
 	UpdateInventoryReq struct {
-		PlayerId string `json:"player_id" validate:"required,max=64"`
-		ItemId   string `json:"item_id" validate:"required,max=64"`
+		OrderId  string                 `json:"order_id" validate:"required,max=64"`
+		PlayerId string                 `json:"player_id" validate:"required,max=64"`
+		Items    []*UpdateInventoryItem `json:"items" validate:"required,min=1,dive"`
 	}
 
+	// Note that: inventory_id is the item of a refund, it is removed by its id
+	UpdateInventoryItem struct {
+		InventoryId string `json:"inventory_id,omitempty" validate:"max=64"`
+		ItemId      string `json:"item_id" validate:"required,max=64"`
+		// Note that: the revision of the item when it was bought, it is empty when the item is given back
+		ItemRevision int `json:"item_revision,omitempty"`
+	}
+
+	// Note that: item_revision is the revision that was bought, revision is the current one of the item
 	ItemInInventory struct {
-		InventoryId string `json:"inventory_id"`
-		PlayerId    string `json:"player_id"`
+		InventoryId  string `json:"inventory_id"`
+		PlayerId     string `json:"player_id"`
+		ItemRevision int    `json:"item_revision,omitempty"`
 		*item.ItemShowCase
 	}
 
@@ -21,9 +33,9 @@ type (
 		models.PaginateReq
 	}
 
+	// Note that: the rollback undoes the add or the remove of the order, also the one that is done after the rollback
 	RollbackPlayerInventoryReq struct {
-		InventoryId string `json:"inventory_id"`
-		PlayerId    string `json:"player_id"`
-		ItemId      string `json:"item_id"`
+		OrderId  string `json:"order_id" validate:"required,max=64"`
+		PlayerId string `json:"player_id"`
 	}
 )
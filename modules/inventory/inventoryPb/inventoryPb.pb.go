@@ -0,0 +1,353 @@
+// Version
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: modules/inventory/inventoryPb/inventoryPb.proto
+
+package hello_sekai_shop_tutorial
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Structures
+type FindPlayerInventoryReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlayerId string `protobuf:"bytes,1,opt,name=playerId,proto3" json:"playerId,omitempty"`
+	Limit    int64  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *FindPlayerInventoryReq) Reset() {
+	*x = FindPlayerInventoryReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindPlayerInventoryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindPlayerInventoryReq) ProtoMessage() {}
+
+func (x *FindPlayerInventoryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindPlayerInventoryReq.ProtoReflect.Descriptor instead.
+func (*FindPlayerInventoryReq) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FindPlayerInventoryReq) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *FindPlayerInventoryReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type PlayerInventoryItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InventoryId string  `protobuf:"bytes,1,opt,name=inventoryId,proto3" json:"inventoryId,omitempty"`
+	ItemId      string  `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Title       string  `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Price       float64 `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	ImageUrl    string  `protobuf:"bytes,5,opt,name=imageUrl,proto3" json:"imageUrl,omitempty"`
+	Damage      int32   `protobuf:"varint,6,opt,name=damage,proto3" json:"damage,omitempty"`
+}
+
+func (x *PlayerInventoryItem) Reset() {
+	*x = PlayerInventoryItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerInventoryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInventoryItem) ProtoMessage() {}
+
+func (x *PlayerInventoryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInventoryItem.ProtoReflect.Descriptor instead.
+func (*PlayerInventoryItem) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlayerInventoryItem) GetInventoryId() string {
+	if x != nil {
+		return x.InventoryId
+	}
+	return ""
+}
+
+func (x *PlayerInventoryItem) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *PlayerInventoryItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PlayerInventoryItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PlayerInventoryItem) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *PlayerInventoryItem) GetDamage() int32 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+type FindPlayerInventoryRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*PlayerInventoryItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *FindPlayerInventoryRes) Reset() {
+	*x = FindPlayerInventoryRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindPlayerInventoryRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindPlayerInventoryRes) ProtoMessage() {}
+
+func (x *FindPlayerInventoryRes) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindPlayerInventoryRes.ProtoReflect.Descriptor instead.
+func (*FindPlayerInventoryRes) Descriptor() ([]byte, []int) {
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FindPlayerInventoryRes) GetItems() []*PlayerInventoryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *FindPlayerInventoryRes) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_modules_inventory_inventoryPb_inventoryPb_proto protoreflect.FileDescriptor
+
+var file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc = []byte{
+	0x0a, 0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x2f, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74,
+	0x6f, 0x72, 0x79, 0x2f, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x62, 0x2f,
+	0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x62, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x4a, 0x0a, 0x16, 0x46, 0x69, 0x6e, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xaf, 0x01,
+	0x0a, 0x13, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72,
+	0x79, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
+	0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x65,
+	0x6e, 0x74, 0x6f, 0x72, 0x79, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x22,
+	0x5a, 0x0a, 0x16, 0x46, 0x69, 0x6e, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x76,
+	0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x32, 0x5f, 0x0a, 0x14, 0x49,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x47, 0x72, 0x70, 0x63, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x13, 0x46, 0x69, 0x6e, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x17, 0x2e, 0x46, 0x69, 0x6e,
+	0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x42, 0x30, 0x5a, 0x2e,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x52, 0x61, 0x79, 0x61, 0x74,
+	0x6f, 0x31, 0x35, 0x39, 0x2f, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x2d, 0x73, 0x65, 0x6b, 0x61, 0x69,
+	0x2d, 0x73, 0x68, 0x6f, 0x70, 0x2d, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescOnce sync.Once
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData = file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc
+)
+
+func file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescGZIP() []byte {
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescOnce.Do(func() {
+		file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData = protoimpl.X.CompressGZIP(file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData)
+	})
+	return file_modules_inventory_inventoryPb_inventoryPb_proto_rawDescData
+}
+
+var file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_modules_inventory_inventoryPb_inventoryPb_proto_goTypes = []interface{}{
+	(*FindPlayerInventoryReq)(nil), // 0: FindPlayerInventoryReq
+	(*PlayerInventoryItem)(nil),    // 1: PlayerInventoryItem
+	(*FindPlayerInventoryRes)(nil), // 2: FindPlayerInventoryRes
+}
+var file_modules_inventory_inventoryPb_inventoryPb_proto_depIdxs = []int32{
+	1, // 0: FindPlayerInventoryRes.items:type_name -> PlayerInventoryItem
+	0, // 1: InventoryGrpcService.FindPlayerInventory:input_type -> FindPlayerInventoryReq
+	2, // 2: InventoryGrpcService.FindPlayerInventory:output_type -> FindPlayerInventoryRes
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_modules_inventory_inventoryPb_inventoryPb_proto_init() }
+func file_modules_inventory_inventoryPb_inventoryPb_proto_init() {
+	if File_modules_inventory_inventoryPb_inventoryPb_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindPlayerInventoryReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerInventoryItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindPlayerInventoryRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_modules_inventory_inventoryPb_inventoryPb_proto_goTypes,
+		DependencyIndexes: file_modules_inventory_inventoryPb_inventoryPb_proto_depIdxs,
+		MessageInfos:      file_modules_inventory_inventoryPb_inventoryPb_proto_msgTypes,
+	}.Build()
+	File_modules_inventory_inventoryPb_inventoryPb_proto = out.File
+	file_modules_inventory_inventoryPb_inventoryPb_proto_rawDesc = nil
+	file_modules_inventory_inventoryPb_inventoryPb_proto_goTypes = nil
+	file_modules_inventory_inventoryPb_inventoryPb_proto_depIdxs = nil
+}
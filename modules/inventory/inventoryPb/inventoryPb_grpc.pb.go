@@ -0,0 +1,111 @@
+// Version
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: modules/inventory/inventoryPb/inventoryPb.proto
+
+package hello_sekai_shop_tutorial
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	InventoryGrpcService_FindPlayerInventory_FullMethodName = "/InventoryGrpcService/FindPlayerInventory"
+)
+
+// InventoryGrpcServiceClient is the client API for InventoryGrpcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InventoryGrpcServiceClient interface {
+	FindPlayerInventory(ctx context.Context, in *FindPlayerInventoryReq, opts ...grpc.CallOption) (*FindPlayerInventoryRes, error)
+}
+
+type inventoryGrpcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryGrpcServiceClient(cc grpc.ClientConnInterface) InventoryGrpcServiceClient {
+	return &inventoryGrpcServiceClient{cc}
+}
+
+func (c *inventoryGrpcServiceClient) FindPlayerInventory(ctx context.Context, in *FindPlayerInventoryReq, opts ...grpc.CallOption) (*FindPlayerInventoryRes, error) {
+	out := new(FindPlayerInventoryRes)
+	err := c.cc.Invoke(ctx, InventoryGrpcService_FindPlayerInventory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InventoryGrpcServiceServer is the server API for InventoryGrpcService service.
+// All implementations must embed UnimplementedInventoryGrpcServiceServer
+// for forward compatibility
+type InventoryGrpcServiceServer interface {
+	FindPlayerInventory(context.Context, *FindPlayerInventoryReq) (*FindPlayerInventoryRes, error)
+	mustEmbedUnimplementedInventoryGrpcServiceServer()
+}
+
+// UnimplementedInventoryGrpcServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInventoryGrpcServiceServer struct {
+}
+
+func (UnimplementedInventoryGrpcServiceServer) FindPlayerInventory(context.Context, *FindPlayerInventoryReq) (*FindPlayerInventoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindPlayerInventory not implemented")
+}
+func (UnimplementedInventoryGrpcServiceServer) mustEmbedUnimplementedInventoryGrpcServiceServer() {}
+
+// UnsafeInventoryGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryGrpcServiceServer will
+// result in compilation errors.
+type UnsafeInventoryGrpcServiceServer interface {
+	mustEmbedUnimplementedInventoryGrpcServiceServer()
+}
+
+func RegisterInventoryGrpcServiceServer(s grpc.ServiceRegistrar, srv InventoryGrpcServiceServer) {
+	s.RegisterService(&InventoryGrpcService_ServiceDesc, srv)
+}
+
+func _InventoryGrpcService_FindPlayerInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindPlayerInventoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryGrpcServiceServer).FindPlayerInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryGrpcService_FindPlayerInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryGrpcServiceServer).FindPlayerInventory(ctx, req.(*FindPlayerInventoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InventoryGrpcService_ServiceDesc is the grpc.ServiceDesc for InventoryGrpcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryGrpcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "InventoryGrpcService",
+	HandlerType: (*InventoryGrpcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindPlayerInventory",
+			Handler:    _InventoryGrpcService_FindPlayerInventory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "modules/inventory/inventoryPb/inventoryPb.proto",
+}
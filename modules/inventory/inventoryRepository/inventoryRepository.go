@@ -31,10 +31,19 @@ type (
 		CountPlayerItems(pctx context.Context, playerId string) (int64, error)
 		AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
 		RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
-		InsertOnePlayerItem(pctx context.Context, req *inventory.Inventory) (primitive.ObjectID, error)
-		DeleteOneInventory(pctx context.Context, inventoryId string) error
-		FindOnePlayerItem(pctx context.Context, playerId, itemId string) bool
-		DeleteOnePlayerItem(pctx context.Context, playerId, itemId string) error
+		InsertManyPlayerItems(pctx context.Context, req []*inventory.Inventory) ([]primitive.ObjectID, error)
+		RemoveManyPlayerItems(pctx context.Context, playerId, orderId string, itemIds []string) ([]*inventory.Inventory, error)
+		RemoveManyInventories(pctx context.Context, playerId, orderId string, inventoryIds []string) ([]*inventory.Inventory, error)
+		DeleteManyRemovingItems(pctx context.Context, orderId string) error
+		ReleaseManyRemovingItems(pctx context.Context, orderId string) error
+		RefundPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
+		DeleteManyOrderItems(pctx context.Context, orderId string) error
+		InsertOneInventoryRemoved(pctx context.Context, req *inventory.InventoryRemoved) error
+		FindManyRemovedInventoryIds(pctx context.Context, playerId, orderId string, inventoryIds []string) ([]string, error)
+		RestoreInventoryRemoved(pctx context.Context, orderId string) error
+		UpsertOneInventoryRollback(pctx context.Context, orderId string) error
+		FindOneInventoryRollback(pctx context.Context, orderId string) bool
+		DeleteManyPlayerItems(pctx context.Context, playerId string) error
 	}
 
 	inventoryRepository struct {
@@ -161,39 +170,308 @@ func (r *inventoryRepository) CountPlayerItems(pctx context.Context, playerId st
 	return count, nil
 }
 
-func (r *inventoryRepository) InsertOnePlayerItem(pctx context.Context, req *inventory.Inventory) (primitive.ObjectID, error) {
-	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+// Note that: mongo runs as a standalone server (see docker-compose.db.yml) and has no transaction, so the items are not
+// added in one. The ids are set before the insert, when the insert fails the items that were inserted are deleted,
+// and the items that are left by a crash have the order_id, the rollback of the order deletes them
+func (r *inventoryRepository) InsertManyPlayerItems(pctx context.Context, req []*inventory.Inventory) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
 	defer cancel()
 
 	db := r.inventoryDbConn(ctx)
 	col := db.Collection("players_inventory")
 
-	result, err := col.InsertOne(ctx, req)
+	ids := make([]primitive.ObjectID, 0, len(req))
+	docs := make([]any, 0, len(req))
+	for _, v := range req {
+		v.Id = primitive.NewObjectID()
+		ids = append(ids, v.Id)
+		docs = append(docs, v)
+	}
+
+	if _, err := col.InsertMany(ctx, docs); err != nil {
+		log.Printf("Error: InsertManyPlayerItems failed: %s", err.Error())
+
+		if _, err := col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+			log.Printf("Error: InsertManyPlayerItems cleanup failed: %s", err.Error())
+		}
+		return nil, errors.New("error: insert player items failed")
+	}
+
+	return ids, nil
+}
+
+// Note that: one inventory is claimed for each item id, the same item can be claimed twice, when one of them is not found
+// the claims are released. The claimed items are not deleted here, see removeManyItems
+func (r *inventoryRepository) RemoveManyPlayerItems(pctx context.Context, playerId, orderId string, itemIds []string) ([]*inventory.Inventory, error) {
+	filters := make([]bson.M, 0, len(itemIds))
+	for _, itemId := range itemIds {
+		filters = append(filters, bson.M{"player_id": playerId, "item_id": itemId})
+	}
+
+	results, err := r.removeManyItems(pctx, orderId, filters, false)
+	if err != nil {
+		log.Printf("Error: RemoveManyPlayerItems failed: %s", err.Error())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("error: item not found")
+		}
+		return nil, errors.New("error: remove player items failed")
+	}
+
+	return results, nil
+}
+
+// Note that: an inventory that is not found was consumed already, it is skipped. An inventory that is claimed
+// by another order is not skipped, that order may be a sell of it and the inventory would be credited twice
+func (r *inventoryRepository) RemoveManyInventories(pctx context.Context, playerId, orderId string, inventoryIds []string) ([]*inventory.Inventory, error) {
+	filters := make([]bson.M, 0, len(inventoryIds))
+	for _, inventoryId := range inventoryIds {
+		filters = append(filters, bson.M{"_id": utils.ConvertToObjectId(inventoryId), "player_id": playerId})
+	}
+
+	results, err := r.removeManyItems(pctx, orderId, filters, true)
 	if err != nil {
-		log.Printf("Error: InsertOnePlayerItem failed: %s", err.Error())
-		return primitive.NilObjectID, errors.New("error: insert player item failed")
+		log.Printf("Error: RemoveManyInventories failed: %s", err.Error())
+		if errors.Is(err, errItemClaimed) {
+			return nil, errors.New("error: item is being removed by another order, try again")
+		}
+		return nil, errors.New("error: remove inventories failed")
+	}
+
+	return results, nil
+}
+
+var errItemClaimed = errors.New("error: item is claimed by another order")
+
+// removeManyItems is the first step of a remove, mongo has no transaction on a standalone server, so the items
+// are removed in steps that a crash can not break:
+//  1. every item is claimed with removing_by, an item that is claimed by another order is not found
+//  2. the caller keeps the claimed items by the order, see InsertOneInventoryRemoved
+//  3. the claimed items are deleted, see DeleteManyRemovingItems
+//
+// The rollback of the order puts back what was deleted and releases the claims, see RestoreInventoryRemoved
+func (r *inventoryRepository) removeManyItems(pctx context.Context, orderId string, filters []bson.M, skipMissing bool) ([]*inventory.Inventory, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory")
+
+	results := make([]*inventory.Inventory, 0, len(filters))
+	for _, filter := range filters {
+		filter["removing_by"] = bson.M{"$exists": false}
+
+		result := new(inventory.Inventory)
+		if err := col.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"removing_by": orderId}}).Decode(result); err != nil {
+			if skipMissing && errors.Is(err, mongo.ErrNoDocuments) {
+				if !r.isItemClaimed(ctx, filter) {
+					continue
+				}
+				err = errItemClaimed
+			}
+			r.ReleaseManyRemovingItems(ctx, orderId)
+			return nil, err
+		}
+
+		results = append(results, result)
 	}
 
-	return result.InsertedID.(primitive.ObjectID), nil
+	return results, nil
 }
 
-func (r *inventoryRepository) DeleteOneInventory(pctx context.Context, inventoryId string) error {
+// isItemClaimed tells whether the item of the filter is in the inventory and is claimed by another order
+func (r *inventoryRepository) isItemClaimed(pctx context.Context, filter bson.M) bool {
+	db := r.inventoryDbConn(pctx)
+	col := db.Collection("players_inventory")
+
+	claimed := bson.M{"removing_by": bson.M{"$exists": true}}
+	for k, v := range filter {
+		if k != "removing_by" {
+			claimed[k] = v
+		}
+	}
+
+	count, err := col.CountDocuments(pctx, claimed)
+	if err != nil {
+		log.Printf("Error: isItemClaimed failed: %s", err.Error())
+		return true
+	}
+
+	return count > 0
+}
+
+func (r *inventoryRepository) ReleaseManyRemovingItems(pctx context.Context, orderId string) error {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
 
 	db := r.inventoryDbConn(ctx)
 	col := db.Collection("players_inventory")
 
-	result, err := col.DeleteOne(ctx, bson.M{"_id": utils.ConvertToObjectId(inventoryId)})
+	if _, err := col.UpdateMany(ctx, bson.M{"removing_by": orderId}, bson.M{"$unset": bson.M{"removing_by": ""}}); err != nil {
+		log.Printf("Error: ReleaseManyRemovingItems failed: %s", err.Error())
+		return errors.New("error: release removing items failed")
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) DeleteManyRemovingItems(pctx context.Context, orderId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory")
+
+	if _, err := col.DeleteMany(ctx, bson.M{"removing_by": orderId}); err != nil {
+		log.Printf("Error: DeleteManyRemovingItems failed: %s", err.Error())
+		return errors.New("error: delete removing items failed")
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) DeleteManyOrderItems(pctx context.Context, orderId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory")
+
+	result, err := col.DeleteMany(ctx, bson.M{"order_id": orderId})
+	if err != nil {
+		log.Printf("Error: DeleteManyOrderItems failed: %s", err.Error())
+		return errors.New("error: delete many order items failed")
+	}
+	log.Printf("DeleteManyOrderItems result: %v", result)
+
+	return nil
+}
+
+func (r *inventoryRepository) InsertOneInventoryRemoved(pctx context.Context, req *inventory.InventoryRemoved) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory_removed")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneInventoryRemoved failed: %s", err.Error())
+		return errors.New("error: insert one inventory removed failed")
+	}
+
+	return nil
+}
+
+// FindManyRemovedInventoryIds returns the inventory ids that are kept as removed by another order of the player,
+// the removed items of a completed order are kept so these ids were sold or refunded by that order
+func (r *inventoryRepository) FindManyRemovedInventoryIds(pctx context.Context, playerId, orderId string, inventoryIds []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory_removed")
+
+	objectIds := make([]primitive.ObjectID, 0, len(inventoryIds))
+	for _, v := range inventoryIds {
+		objectIds = append(objectIds, utils.ConvertToObjectId(v))
+	}
+
+	cursors, err := col.Find(ctx, bson.M{
+		"_id":       bson.M{"$ne": orderId},
+		"player_id": playerId,
+		"items._id": bson.M{"$in": objectIds},
+	})
 	if err != nil {
-		log.Printf("Error: DeleteOneInventory failed: %s", err.Error())
-		return errors.New("error: delete one inventory failed")
+		log.Printf("Error: FindManyRemovedInventoryIds failed: %s", err.Error())
+		return make([]string, 0), errors.New("error: find removed inventories failed")
+	}
+
+	removed := make([]*inventory.InventoryRemoved, 0)
+	if err := cursors.All(ctx, &removed); err != nil {
+		log.Printf("Error: FindManyRemovedInventoryIds failed: %s", err.Error())
+		return make([]string, 0), errors.New("error: find removed inventories failed")
+	}
+
+	setIds := make(map[string]bool)
+	for _, v := range inventoryIds {
+		setIds[v] = true
+	}
+
+	results := make([]string, 0)
+	for _, order := range removed {
+		for _, v := range order.Items {
+			if setIds[v.Id.Hex()] {
+				results = append(results, v.Id.Hex())
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// Note that: the removed items are inserted back with their own ids, an item that is back already or was never deleted
+// is skipped, then the claims of the order are released
+func (r *inventoryRepository) RestoreInventoryRemoved(pctx context.Context, orderId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory")
+
+	result := new(inventory.InventoryRemoved)
+	if err := db.Collection("players_inventory_removed").FindOneAndDelete(ctx, bson.M{"_id": orderId}).Decode(result); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Printf("Error: RestoreInventoryRemoved failed: %s", err.Error())
+		return errors.New("error: restore inventory removed failed")
+	}
+
+	docs := make([]any, 0, len(result.Items))
+	for _, v := range result.Items {
+		docs = append(docs, v)
+	}
+	if len(docs) > 0 {
+		if _, err := col.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil && !mongo.IsDuplicateKeyError(err) {
+			log.Printf("Error: RestoreInventoryRemoved failed: %s", err.Error())
+			return errors.New("error: restore inventory removed failed")
+		}
+	}
+
+	return r.ReleaseManyRemovingItems(ctx, orderId)
+}
+
+func (r *inventoryRepository) UpsertOneInventoryRollback(pctx context.Context, orderId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory_rollbacks")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": orderId},
+		bson.M{"$setOnInsert": bson.M{"created_at": utils.LocalTime()}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		log.Printf("Error: UpsertOneInventoryRollback failed: %s", err.Error())
+		return errors.New("error: upsert one inventory rollback failed")
 	}
-	log.Printf("DeleteOneInventory result: %v", result)
 
 	return nil
 }
 
+func (r *inventoryRepository) FindOneInventoryRollback(pctx context.Context, orderId string) bool {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.inventoryDbConn(ctx)
+	col := db.Collection("players_inventory_rollbacks")
+
+	result := new(inventory.InventoryRollback)
+
+	if err := col.FindOne(ctx, bson.M{"_id": orderId}).Decode(result); err != nil {
+		return false
+	}
+	return true
+}
+
 func (r *inventoryRepository) AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
 	reqInBytes, err := json.Marshal(req)
 	if err != nil {
@@ -216,35 +494,41 @@ func (r *inventoryRepository) AddPlayerItemRes(pctx context.Context, cfg *config
 	return nil
 }
 
-func (r *inventoryRepository) FindOnePlayerItem(pctx context.Context, playerId, itemId string) bool {
-	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
-	defer cancel()
-
-	db := r.inventoryDbConn(ctx)
-	col := db.Collection("players_inventory")
-
-	result := new(inventory.Inventory)
+func (r *inventoryRepository) RefundPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: RefundPlayerItemRes failed: %s", err.Error())
+		return errors.New("error: refund player item res failed")
+	}
 
-	if err := col.FindOne(ctx, bson.M{"player_id": playerId, "item_id": itemId}).Decode(result); err != nil {
-		log.Printf("Error: FindOnePlayerItem failed: %s", err.Error())
-		return false
+	if err := queue.PushMessageWithKeyToQueue(
+		[]string{cfg.Kafka.Url},
+		cfg.Kafka.ApiKey,
+		cfg.Kafka.Secret,
+		"payment",
+		"refund",
+		reqInBytes,
+	); err != nil {
+		log.Printf("Error: RefundPlayerItemRes failed: %s", err.Error())
+		return errors.New("error: refund player item res failed")
 	}
-	return true
+
+	return nil
 }
 
-func (r *inventoryRepository) DeleteOnePlayerItem(pctx context.Context, playerId, itemId string) error {
+func (r *inventoryRepository) DeleteManyPlayerItems(pctx context.Context, playerId string) error {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
 
 	db := r.inventoryDbConn(ctx)
 	col := db.Collection("players_inventory")
 
-	result, err := col.DeleteOne(ctx, bson.M{"player_id": playerId, "item_id": itemId})
+	result, err := col.DeleteMany(ctx, bson.M{"player_id": bson.M{"$in": []string{"player:" + playerId, playerId}}})
 	if err != nil {
-		log.Printf("Error: DeleteOnePlayerItem failed: %s", err.Error())
-		return errors.New("error: delete one player item failed")
+		log.Printf("Error: DeleteManyPlayerItems failed: %s", err.Error())
+		return errors.New("error: delete player items failed")
 	}
-	log.Printf("DeleteOnePlayerItem result: %v", result)
+	log.Printf("DeleteManyPlayerItems result: %v", result)
 
 	return nil
 }
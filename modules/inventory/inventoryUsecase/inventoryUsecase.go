@@ -2,18 +2,22 @@ package inventoryUsecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory"
+	inventoryPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
 	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type (
@@ -23,8 +27,11 @@ type (
 		FindPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req *inventory.InventorySearchReq) (*models.PaginateRes, error)
 		AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq)
 		RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq)
+		RefundPlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq)
 		RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq)
 		RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq)
+		DeletePlayerItems(pctx context.Context, req *player.PlayerDeletedEvent)
+		FindPlayerInventory(pctx context.Context, cfg *config.Config, req *inventoryPb.FindPlayerInventoryReq) (*inventoryPb.FindPlayerInventoryRes, error)
 	}
 
 	inventoryUsecase struct {
@@ -45,40 +52,50 @@ func (u *inventoryUsecase) UpserOffset(pctx context.Context, offset int64) error
 	return u.inventoryRepository.UpserOffset(pctx, offset)
 }
 
+var inventorySorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+	{Name: "item_id", Field: "item_id", Kind: models.SortKindString},
+}
+
 func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Config, playerId string, req *inventory.InventorySearchReq) (*models.PaginateRes, error) {
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "inventory:"+playerId, &req.PaginateReq, inventorySorts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Filter
 	filter := bson.D{}
+	filter = append(filter, bson.E{"player_id", playerId})
 
-	// Filter
-	if req.Start != "" {
-		filter = append(filter, bson.E{"_id", bson.D{{"$gt", utils.ConvertToObjectId(req.Start)}}})
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
 	}
-	filter = append(filter, bson.E{"player_id", playerId})
 
-	// Option
-	opts := make([]*options.FindOptions, 0)
+	// Find
+	inventoryData, err := u.inventoryRepository.FindPlayerItems(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
 
-	opts = append(opts, options.Find().SetSort(bson.D{{"_id", 1}}))
-	opts = append(opts, options.Find().SetLimit(int64(req.Limit)))
+	inventoryData, next, prev := models.Page(paginate, inventoryData, func(v *inventory.Inventory) (any, primitive.ObjectID) {
+		if paginate.Sort.Name == "item_id" {
+			return v.ItemId, v.Id
+		}
+		return v.Id, v.Id
+	})
 
-	// Find
-	inventoryData, err := u.inventoryRepository.FindPlayerItems(pctx, filter, opts)
+	// Count
+	total, err := u.inventoryRepository.CountPlayerItems(pctx, playerId)
 	if err != nil {
 		return nil, err
 	}
+
+	baseUrl := fmt.Sprintf("%s/%s", cfg.Paginate.InventoryNextPageBasedUrl, playerId)
+
 	if len(inventoryData) == 0 {
-		return &models.PaginateRes{
-			Data:  make([]*inventory.ItemInInventory, 0),
-			Total: 0,
-			Limit: req.Limit,
-			First: models.FirstPaginate{
-				Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
-			},
-			Next: models.NextPaginate{
-				Start: "",
-				Href:  "",
-			},
-		}, nil
+		return paginate.NewPaginateRes(make([]*inventory.ItemInInventory, 0), total, baseUrl, paginate.Query(), next, prev), nil
 	}
 
 	itemData, err := u.inventoryRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{
@@ -90,122 +107,313 @@ func (u *inventoryUsecase) FindPlayerItems(pctx context.Context, cfg *config.Con
 			return itemIds
 		}(),
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	itemMaps := make(map[string]*item.ItemShowCase)
 	for _, v := range itemData.Items {
 		itemMaps[v.Id] = &item.ItemShowCase{
-			ItemId:   v.Id,
-			Title:    v.Title,
-			Price:    v.Price,
-			ImageUrl: v.ImageUrl,
-			Damage:   int(v.Damage),
+			ItemId:     v.Id,
+			Title:      v.Title,
+			Price:      v.Price,
+			ImageUrl:   v.ImageUrl,
+			Damage:     int(v.Damage),
+			Category:   v.Category,
+			Rarity:     v.Rarity,
+			Thumbnails: v.Thumbnails,
+			Revision:   int(v.Revision),
 		}
 	}
 
 	results := make([]*inventory.ItemInInventory, 0)
 	for _, v := range inventoryData {
+		// Note that: an item that is not found anymore is shown with the id only
+		if _, ok := itemMaps[v.ItemId]; !ok {
+			itemMaps[v.ItemId] = &item.ItemShowCase{ItemId: v.ItemId}
+		}
+
 		results = append(results, &inventory.ItemInInventory{
-			InventoryId: v.Id.Hex(),
-			PlayerId:    v.PlayerId,
+			InventoryId:  v.Id.Hex(),
+			PlayerId:     v.PlayerId,
+			ItemRevision: v.ItemRevision,
 			ItemShowCase: &item.ItemShowCase{
-				ItemId:   v.ItemId,
-				Title:    itemMaps[v.ItemId].Title,
-				Price:    itemMaps[v.ItemId].Price,
-				Damage:   itemMaps[v.ItemId].Damage,
-				ImageUrl: itemMaps[v.ItemId].ImageUrl,
+				ItemId:     v.ItemId,
+				Title:      itemMaps[v.ItemId].Title,
+				Price:      itemMaps[v.ItemId].Price,
+				Damage:     itemMaps[v.ItemId].Damage,
+				ImageUrl:   itemMaps[v.ItemId].ImageUrl,
+				Category:   itemMaps[v.ItemId].Category,
+				Rarity:     itemMaps[v.ItemId].Rarity,
+				Thumbnails: itemMaps[v.ItemId].Thumbnails,
+				Revision:   itemMaps[v.ItemId].Revision,
 			},
 		})
 	}
 
-	// Count
-	total, err := u.inventoryRepository.CountPlayerItems(pctx, playerId)
-	if err != nil {
-		return nil, err
-	}
-
-	return &models.PaginateRes{
-		Data:  results,
-		Total: total,
-		Limit: req.Limit,
-		First: models.FirstPaginate{
-			Href: fmt.Sprintf("%s/%s?limit=%d", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit),
-		},
-		Next: models.NextPaginate{
-			Start: results[len(results)-1].InventoryId,
-			Href:  fmt.Sprintf("%s/%s?limit=%d&start=%s", cfg.Paginate.InventoryNextPageBasedUrl, playerId, req.Limit, results[len(results)-1].InventoryId),
-		},
-	}, nil
+	return paginate.NewPaginateRes(results, total, baseUrl, paginate.Query(), next, prev), nil
 }
 
+// Note that: the items of the order are inserted together, the reply has one inventory id for each item
 func (u *inventoryUsecase) AddPlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) {
-	inventoryId, err := u.inventoryRepository.InsertOnePlayerItem(pctx, &inventory.Inventory{
-		PlayerId: req.PlayerId,
-		ItemId:   req.ItemId,
-	})
-	if err != nil {
-		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        req.ItemId,
-			Amount:        0,
-			Error:         err.Error(),
+	res := &payment.PaymentTransferRes{
+		OrderId:       req.OrderId,
+		TransactionId: "",
+		PlayerId:      req.PlayerId,
+		Amount:        0,
+		Error:         "",
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		res.Error = "error: order is rolled back"
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	docs := make([]*inventory.Inventory, 0, len(req.Items))
+	for _, v := range req.Items {
+		docs = append(docs, &inventory.Inventory{
+			PlayerId:     req.PlayerId,
+			ItemId:       v.ItemId,
+			ItemRevision: v.ItemRevision,
+			OrderId:      req.OrderId,
 		})
+	}
+
+	inventoryIds, err := u.inventoryRepository.InsertManyPlayerItems(pctx, docs)
+	if err != nil {
+		res.Error = err.Error()
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	// Note that: the rollback can come while the items are inserted, it is checked again so the items undo themselves
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		u.inventoryRepository.DeleteManyOrderItems(pctx, req.OrderId)
+		res.Error = "error: order is rolled back"
+		u.inventoryRepository.AddPlayerItemRes(pctx, cfg, res)
 		return
 	}
 
-	u.inventoryRepository.AddPlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
-		InventoryId:   inventoryId.Hex(),
+	res.InventoryIds = make([]string, 0, len(inventoryIds))
+	for _, v := range inventoryIds {
+		res.InventoryIds = append(res.InventoryIds, v.Hex())
+	}
+	u.inventoryRepository.AddPlayerItemRes(pctx, cfg, res)
+}
+
+// Note that: when one of the items is not in the inventory, none of them is removed,
+// the removed items are kept by the order so the rollback can give them back
+func (u *inventoryUsecase) RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) {
+	res := &payment.PaymentTransferRes{
+		OrderId:       req.OrderId,
+		InventoryId:   "",
 		TransactionId: "",
 		PlayerId:      req.PlayerId,
-		ItemId:        req.ItemId,
 		Amount:        0,
 		Error:         "",
-	})
-}
+	}
 
-func (u *inventoryUsecase) RemovePlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) {
-	if !u.inventoryRepository.FindOnePlayerItem(pctx, req.PlayerId, req.ItemId) {
-		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        req.ItemId,
-			Amount:        0,
-			Error:         "error: item not found",
-		})
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		res.Error = "error: order is rolled back"
+		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, res)
 		return
 	}
 
-	if err := u.inventoryRepository.DeleteOnePlayerItem(pctx, req.PlayerId, req.ItemId); err != nil {
-		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        req.ItemId,
-			Amount:        0,
-			Error:         err.Error(),
-		})
+	itemIds := make([]string, 0, len(req.Items))
+	for _, v := range req.Items {
+		itemIds = append(itemIds, v.ItemId)
+	}
+
+	removed, err := u.inventoryRepository.RemoveManyPlayerItems(pctx, req.PlayerId, req.OrderId, itemIds)
+	if err != nil {
+		res.Error = err.Error()
+		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, res)
 		return
 	}
 
-	u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, &payment.PaymentTransferRes{
-		InventoryId:   "",
+	if err := u.keepRemovedItems(pctx, req, removed); err != nil {
+		res.Error = err.Error()
+		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		u.inventoryRepository.RestoreInventoryRemoved(pctx, req.OrderId)
+		res.Error = "error: order is rolled back"
+		u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	// Note that: the removed items are in the order of the items of the request, the sell keeps which inventory ids it sold
+	res.InventoryIds = make([]string, 0, len(removed))
+	for _, v := range removed {
+		res.InventoryIds = append(res.InventoryIds, v.Id.Hex())
+	}
+	u.inventoryRepository.RemovePlayerItemRes(pctx, cfg, res)
+}
+
+// Note that: the items of a refund are removed by their inventory ids, an item that was consumed is skipped,
+// the reply has the inventory ids that were removed
+func (u *inventoryUsecase) RefundPlayerItemRes(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) {
+	res := &payment.PaymentTransferRes{
+		OrderId:       req.OrderId,
+		InventoryIds:  make([]string, 0),
 		TransactionId: "",
 		PlayerId:      req.PlayerId,
-		ItemId:        req.ItemId,
 		Amount:        0,
 		Error:         "",
-	})
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		res.Error = "error: order is rolled back"
+		u.inventoryRepository.RefundPlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	inventoryIds := make([]string, 0, len(req.Items))
+	for _, v := range req.Items {
+		if v.InventoryId != "" {
+			inventoryIds = append(inventoryIds, v.InventoryId)
+		}
+	}
+
+	removed, err := u.inventoryRepository.RemoveManyInventories(pctx, req.PlayerId, req.OrderId, inventoryIds)
+	if err != nil {
+		res.Error = err.Error()
+		u.inventoryRepository.RefundPlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	// Note that: an inventory that is not found and is kept as removed by another order was sold while the refund
+	// started, it is not consumed, so the refund fails and the claims are released
+	if err := u.checkMissingInventories(pctx, req, inventoryIds, removed); err != nil {
+		u.inventoryRepository.ReleaseManyRemovingItems(pctx, req.OrderId)
+		res.Error = err.Error()
+		u.inventoryRepository.RefundPlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	if len(removed) > 0 {
+		if err := u.keepRemovedItems(pctx, req, removed); err != nil {
+			res.Error = err.Error()
+			u.inventoryRepository.RefundPlayerItemRes(pctx, cfg, res)
+			return
+		}
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		u.inventoryRepository.RestoreInventoryRemoved(pctx, req.OrderId)
+		res.Error = "error: order is rolled back"
+		u.inventoryRepository.RefundPlayerItemRes(pctx, cfg, res)
+		return
+	}
+
+	for _, v := range removed {
+		res.InventoryIds = append(res.InventoryIds, v.Id.Hex())
+	}
+	u.inventoryRepository.RefundPlayerItemRes(pctx, cfg, res)
+}
+
+func (u *inventoryUsecase) checkMissingInventories(pctx context.Context, req *inventory.UpdateInventoryReq, inventoryIds []string, removed []*inventory.Inventory) error {
+	setIds := make(map[string]bool)
+	for _, v := range removed {
+		setIds[v.Id.Hex()] = true
+	}
+
+	missing := make([]string, 0)
+	for _, v := range inventoryIds {
+		if !setIds[v] {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	soldIds, err := u.inventoryRepository.FindManyRemovedInventoryIds(pctx, req.PlayerId, req.OrderId, missing)
+	if err != nil {
+		return err
+	}
+	if len(soldIds) > 0 {
+		return errors.New("error: items of the order were sold while it is refunded, try again")
+	}
+
+	return nil
 }
 
+// keepRemovedItems keeps the claimed items by the order before they are deleted, so a crash after any step is undone
+// by the rollback of the order. A step that fails puts the items back here
+func (u *inventoryUsecase) keepRemovedItems(pctx context.Context, req *inventory.UpdateInventoryReq, removed []*inventory.Inventory) error {
+	if err := u.inventoryRepository.InsertOneInventoryRemoved(pctx, &inventory.InventoryRemoved{
+		OrderId:   req.OrderId,
+		PlayerId:  req.PlayerId,
+		Items:     removed,
+		CreatedAt: utils.LocalTime(),
+	}); err != nil {
+		u.inventoryRepository.ReleaseManyRemovingItems(pctx, req.OrderId)
+		return err
+	}
+
+	if err := u.inventoryRepository.DeleteManyRemovingItems(pctx, req.OrderId); err != nil {
+		u.inventoryRepository.RestoreInventoryRemoved(pctx, req.OrderId)
+		return err
+	}
+
+	return nil
+}
+
+func (u *inventoryUsecase) isOrderRolledBack(pctx context.Context, orderId string) bool {
+	return u.inventoryRepository.FindOneInventoryRollback(pctx, orderId)
+}
+
+// Note that: the rollback is kept before the items are deleted, an add that comes in between sees it
 func (u *inventoryUsecase) RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq) {
-	u.inventoryRepository.DeleteOneInventory(pctx, req.InventoryId)
+	if err := u.inventoryRepository.UpsertOneInventoryRollback(pctx, req.OrderId); err != nil {
+		return
+	}
+	u.inventoryRepository.DeleteManyOrderItems(pctx, req.OrderId)
 }
 
 func (u *inventoryUsecase) RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq) {
-	u.inventoryRepository.InsertOnePlayerItem(pctx, &inventory.Inventory{
-		PlayerId: req.PlayerId,
-		ItemId:   req.ItemId,
+	if err := u.inventoryRepository.UpsertOneInventoryRollback(pctx, req.OrderId); err != nil {
+		return
+	}
+	u.inventoryRepository.RestoreInventoryRemoved(pctx, req.OrderId)
+}
+
+func (u *inventoryUsecase) DeletePlayerItems(pctx context.Context, req *player.PlayerDeletedEvent) {
+	u.inventoryRepository.DeleteManyPlayerItems(pctx, req.PlayerId)
+}
+
+// Note that: the first items of the player, for the admin console
+func (u *inventoryUsecase) FindPlayerInventory(pctx context.Context, cfg *config.Config, req *inventoryPb.FindPlayerInventoryReq) (*inventoryPb.FindPlayerInventoryRes, error) {
+	limit := int(req.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	result, err := u.FindPlayerItems(pctx, cfg, "player:"+strings.TrimPrefix(req.PlayerId, "player:"), &inventory.InventorySearchReq{
+		PaginateReq: models.PaginateReq{Limit: limit},
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &inventoryPb.FindPlayerInventoryRes{
+		Items: make([]*inventoryPb.PlayerInventoryItem, 0),
+		Total: result.Total,
+	}
+	for _, v := range result.Data.([]*inventory.ItemInInventory) {
+		res.Items = append(res.Items, &inventoryPb.PlayerInventoryItem{
+			InventoryId: v.InventoryId,
+			ItemId:      v.ItemId,
+			Title:       v.Title,
+			Price:       v.Price,
+			ImageUrl:    v.ImageUrl,
+			Damage:      int32(v.Damage),
+		})
+	}
+
+	return res, nil
 }
@@ -0,0 +1,244 @@
+package item
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Categories
+const (
+	CategoryWeapon     = "weapon"
+	CategoryArmor      = "armor"
+	CategoryConsumable = "consumable"
+)
+
+// Rarities, ordered from the lowest tier
+const (
+	RarityCommon    = "common"
+	RarityUncommon  = "uncommon"
+	RarityRare      = "rare"
+	RarityEpic      = "epic"
+	RarityLegendary = "legendary"
+)
+
+// Search sorts
+const (
+	SortRelevance  = "relevance"
+	SortNewest     = "newest"
+	SortPriceAsc   = "price_asc"
+	SortPriceDesc  = "price_desc"
+	SortDamageAsc  = "damage_asc"
+	SortDamageDesc = "damage_desc"
+)
+
+// Attribute types
+const (
+	AttributeInt    = "int"
+	AttributeFloat  = "float"
+	AttributeString = "string"
+	AttributeBool   = "bool"
+)
+
+type (
+	AttributeSchema struct {
+		Type     string   `json:"type"`
+		Required bool     `json:"required"`
+		Min      *float64 `json:"min,omitempty"`
+		Max      *float64 `json:"max,omitempty"`
+		Enum     []string `json:"enum,omitempty"`
+	}
+
+	CategorySchema struct {
+		Category string `json:"category"`
+		// Note that: only a weapon has damage, it is kept out of the attributes as every service already reads it
+		HasDamage  bool                        `json:"has_damage"`
+		Attributes map[string]*AttributeSchema `json:"attributes"`
+	}
+)
+
+func bound(v float64) *float64 { return &v }
+
+var categorySchemas = map[string]*CategorySchema{
+	CategoryWeapon: {
+		Category:  CategoryWeapon,
+		HasDamage: true,
+		Attributes: map[string]*AttributeSchema{
+			"durability":   {Type: AttributeInt, Min: bound(1), Max: bound(10000)},
+			"attack_speed": {Type: AttributeFloat, Min: bound(0.1), Max: bound(10)},
+			"element":      {Type: AttributeString, Enum: []string{"none", "fire", "ice", "lightning", "holy", "dark"}},
+			"two_handed":   {Type: AttributeBool},
+		},
+	},
+	CategoryArmor: {
+		Category: CategoryArmor,
+		Attributes: map[string]*AttributeSchema{
+			"defense":    {Type: AttributeInt, Required: true, Min: bound(0), Max: bound(10000)},
+			"durability": {Type: AttributeInt, Min: bound(1), Max: bound(10000)},
+			"weight":     {Type: AttributeFloat, Min: bound(0), Max: bound(1000)},
+			"slot":       {Type: AttributeString, Required: true, Enum: []string{"head", "chest", "legs", "feet", "hands", "shield"}},
+		},
+	},
+	CategoryConsumable: {
+		Category: CategoryConsumable,
+		Attributes: map[string]*AttributeSchema{
+			"effect":           {Type: AttributeString, Required: true, Enum: []string{"heal", "mana", "buff"}},
+			"potency":          {Type: AttributeInt, Required: true, Min: bound(1), Max: bound(100000)},
+			"duration_seconds": {Type: AttributeInt, Min: bound(0), Max: bound(86400)},
+			"stackable":        {Type: AttributeBool},
+		},
+	},
+}
+
+func Categories() []string {
+	return []string{CategoryWeapon, CategoryArmor, CategoryConsumable}
+}
+
+func Rarities() []string {
+	return []string{RarityCommon, RarityUncommon, RarityRare, RarityEpic, RarityLegendary}
+}
+
+func FindCategorySchema(category string) (*CategorySchema, error) {
+	schema, ok := categorySchemas[category]
+	if !ok {
+		return nil, fmt.Errorf("error: category must be one of %s", strings.Join(Categories(), ", "))
+	}
+	return schema, nil
+}
+
+func IsValidRarity(rarity string) bool {
+	for _, r := range Rarities() {
+		if r == rarity {
+			return true
+		}
+	}
+	return false
+}
+
+// Note that: the items created before the categories were added are weapons of the common tier
+func NormalizeCategory(category string) string {
+	if category == "" {
+		return CategoryWeapon
+	}
+	return category
+}
+
+func NormalizeRarity(rarity string) string {
+	if rarity == "" {
+		return RarityCommon
+	}
+	return rarity
+}
+
+// ValidateAttributes checks the attributes against the schema of the category and returns them with the declared types,
+// a json number is decoded as float64 so an int attribute is converted back to int64 here
+func (s *CategorySchema) ValidateAttributes(attributes map[string]any) (map[string]any, error) {
+	results := make(map[string]any)
+
+	for name := range attributes {
+		if _, ok := s.Attributes[name]; !ok {
+			return nil, fmt.Errorf("error: attribute %s is not allowed for category %s", name, s.Category)
+		}
+	}
+
+	names := make([]string, 0, len(s.Attributes))
+	for name := range s.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attr := s.Attributes[name]
+
+		value, ok := attributes[name]
+		if !ok || value == nil {
+			if attr.Required {
+				return nil, fmt.Errorf("error: attribute %s is required for category %s", name, s.Category)
+			}
+			continue
+		}
+
+		result, err := attr.convert(value)
+		if err != nil {
+			return nil, fmt.Errorf("error: attribute %s %s", name, err.Error())
+		}
+		results[name] = result
+	}
+
+	return results, nil
+}
+
+func (a *AttributeSchema) convert(value any) (any, error) {
+	switch a.Type {
+	case AttributeInt, AttributeFloat:
+		number, ok := toFloat(value)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("must be a number")
+		}
+		if a.Type == AttributeInt && number != math.Trunc(number) {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		if a.Min != nil && number < *a.Min {
+			return nil, fmt.Errorf("must not be less than %v", *a.Min)
+		}
+		if a.Max != nil && number > *a.Max {
+			return nil, fmt.Errorf("must not be greater than %v", *a.Max)
+		}
+		if a.Type == AttributeInt {
+			return int64(number), nil
+		}
+		return number, nil
+	case AttributeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		text = strings.TrimSpace(text)
+		if len(text) > 64 {
+			return nil, fmt.Errorf("must not be longer than 64 characters")
+		}
+		if len(a.Enum) > 0 {
+			for _, e := range a.Enum {
+				if e == text {
+					return text, nil
+				}
+			}
+			return nil, fmt.Errorf("must be one of %s", strings.Join(a.Enum, ", "))
+		}
+		return text, nil
+	case AttributeBool:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return flag, nil
+	}
+	return nil, errors.New("has an unknown type")
+}
+
+// IsRangeAttribute reports whether the attribute can be filtered with a range
+func (s *CategorySchema) IsRangeAttribute(name string) bool {
+	if name == "damage" {
+		return s.HasDamage
+	}
+	attr, ok := s.Attributes[name]
+	return ok && (attr.Type == AttributeInt || attr.Type == AttributeFloat)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
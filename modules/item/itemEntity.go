@@ -1,20 +1,133 @@
 package item
 
 import (
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type (
+	// Note that: effective_price is the price with the campaigns and the schedules, it is written when they start or end
+	// and is what the lists and the search filter and sort by
 	Item struct {
-		Id          primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
-		Title       string             `json:"title" bson:"title"`
-		Price       float64            `json:"price" bson:"price"`
-		Damage      int                `json:"damage" bson:"damage"`
-		ImageUrl    string             `json:"image_url" bson:"image_url"`
-		UsageStatus bool               `json:"usage_status" bson:"usage_status"`
-		CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
-		UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+		Id             primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Title          string             `json:"title" bson:"title"`
+		Price          float64            `json:"price" bson:"price"`
+		EffectivePrice float64            `json:"effective_price" bson:"effective_price"`
+		Damage         int                `json:"damage" bson:"damage"`
+		ImageUrl       string             `json:"image_url" bson:"image_url"`
+		Category       string             `json:"category" bson:"category"`
+		Rarity         string             `json:"rarity" bson:"rarity"`
+		Attributes     map[string]any     `json:"attributes" bson:"attributes,omitempty"`
+		Image          *ItemImage         `json:"image" bson:"image,omitempty"`
+		UsageStatus    bool               `json:"usage_status" bson:"usage_status"`
+		Revision       int                `json:"revision" bson:"revision"`
+		CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt      time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	// Note that: a revision is written once for every change of an item and is never updated,
+	// before is empty when the item is created and the items before the history have revision 0
+	ItemRevision struct {
+		Id           primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		ItemId       string             `json:"item_id" bson:"item_id"`
+		Revision     int                `json:"revision" bson:"revision"`
+		Action       string             `json:"action" bson:"action"`
+		Before       *Item              `json:"before" bson:"before"`
+		After        *Item              `json:"after" bson:"after"`
+		PlayerId     string             `json:"player_id" bson:"player_id"`
+		RevertedFrom int                `json:"reverted_from,omitempty" bson:"reverted_from,omitempty"`
+		CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	}
+
+	// Note that: a campaign discounts the items in item_ids and the items of the category between start_at and end_at,
+	// ending a campaign early moves its end_at, so the campaigns of the past are kept
+	ItemCampaign struct {
+		Id            primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Title         string             `json:"title" bson:"title"`
+		DiscountType  string             `json:"discount_type" bson:"discount_type"`
+		DiscountValue float64            `json:"discount_value" bson:"discount_value"`
+		ItemIds       []string           `json:"item_ids" bson:"item_ids"`
+		Category      string             `json:"category" bson:"category"`
+		StartAt       time.Time          `json:"start_at" bson:"start_at"`
+		EndAt         time.Time          `json:"end_at" bson:"end_at"`
+		PlayerId      string             `json:"player_id" bson:"player_id"`
+		CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	}
+
+	// Note that: the price of a schedule is the price of the item from start_at, until the price is set again by an admin
+	ItemPriceSchedule struct {
+		Id           primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		ItemId       string             `json:"item_id" bson:"item_id"`
+		Price        float64            `json:"price" bson:"price"`
+		StartAt      time.Time          `json:"start_at" bson:"start_at"`
+		SupersededAt *time.Time         `json:"superseded_at,omitempty" bson:"superseded_at,omitempty"`
+		PlayerId     string             `json:"player_id" bson:"player_id"`
+		CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	}
+
+	// Note that: an alert is written with the change that caused it and is published to the queue later,
+	// so a change is never lost when the queue is down
+	ItemAlert struct {
+		Id            primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Type          string             `json:"type" bson:"type"`
+		ItemId        string             `json:"item_id" bson:"item_id"`
+		Title         string             `json:"title" bson:"title"`
+		Price         float64            `json:"price" bson:"price"`
+		PreviousPrice float64            `json:"previous_price,omitempty" bson:"previous_price,omitempty"`
+		CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+		PublishedAt   *time.Time         `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	}
+
+	// ItemPriceWatch is the last effective price of an item that was checked, a lower price is a price drop
+	ItemPriceWatch struct {
+		ItemId    string    `bson:"item_id"`
+		Price     float64   `bson:"price"`
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+
+	// Note that: an uploaded image, the url of the original is kept in image_url too
+	ItemImage struct {
+		Key         string           `json:"key" bson:"key"`
+		ContentType string           `json:"content_type" bson:"content_type"`
+		Width       int              `json:"width" bson:"width"`
+		Height      int              `json:"height" bson:"height"`
+		Thumbnails  []*ItemThumbnail `json:"thumbnails" bson:"thumbnails"`
+	}
+
+	ItemThumbnail struct {
+		Size int    `json:"size" bson:"size"`
+		Key  string `json:"key" bson:"key"`
+		Url  string `json:"url" bson:"url"`
 	}
 )
+
+// Revision actions
+const (
+	RevisionActionCreate  = "create"
+	RevisionActionEdit    = "edit"
+	RevisionActionEnable  = "enable"
+	RevisionActionDisable = "disable"
+	RevisionActionImport  = "import"
+	RevisionActionImage   = "image"
+	RevisionActionRevert  = "revert"
+)
+
+// Alert types, they are the keys of the messages too
+const (
+	AlertPriceDrop = "price_drop"
+	AlertRestock   = "restock"
+)
+
+func (i *ItemImage) ThumbnailUrls() map[string]string {
+	if i == nil {
+		return nil
+	}
+
+	results := make(map[string]string)
+	for _, v := range i.Thumbnails {
+		results[strconv.Itoa(v.Size)] = v.Url
+	}
+	return results
+}
@@ -1,9 +1,12 @@
 package itemHandler
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
@@ -21,6 +24,19 @@ type (
 		FindManyItems(c echo.Context) error
 		EditItem(c echo.Context) error
 		EnableOrDisableItem(c echo.Context) error
+		FindItemCategories(c echo.Context) error
+		SearchItems(c echo.Context) error
+		ImportItems(c echo.Context) error
+		ExportItems(c echo.Context) error
+		UploadItemImage(c echo.Context) error
+		FindItemRevisions(c echo.Context) error
+		RevertItem(c echo.Context) error
+		CreateItemCampaign(c echo.Context) error
+		FindItemCampaigns(c echo.Context) error
+		EndItemCampaign(c echo.Context) error
+		CreateItemPriceSchedule(c echo.Context) error
+		FindItemPriceSchedules(c echo.Context) error
+		CancelItemPriceSchedule(c echo.Context) error
 	}
 
 	itemHttpHandler struct {
@@ -47,7 +63,7 @@ func (h *itemHttpHandler) CreateItem(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	res, err := h.itemUsecase.CreateItem(ctx, req)
+	res, err := h.itemUsecase.CreateItem(ctx, c.Get("player_id").(string), req)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
@@ -79,7 +95,7 @@ func (h *itemHttpHandler) FindManyItems(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	res, err := h.itemUsecase.FindManyItems(ctx, h.cfg.Paginate.ItemNextPageBasedUrl, req)
+	res, err := h.itemUsecase.FindManyItems(ctx, h.cfg, req)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
@@ -100,7 +116,7 @@ func (h *itemHttpHandler) EditItem(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	res, err := h.itemUsecase.EditItem(ctx, itemId, req)
+	res, err := h.itemUsecase.EditItem(ctx, c.Get("player_id").(string), itemId, req)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
@@ -113,7 +129,7 @@ func (h *itemHttpHandler) EnableOrDisableItem(c echo.Context) error {
 
 	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
 
-	res, err := h.itemUsecase.EnableOrDisableItem(ctx, itemId)
+	res, err := h.itemUsecase.EnableOrDisableItem(ctx, c.Get("player_id").(string), itemId)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
@@ -122,3 +138,251 @@ func (h *itemHttpHandler) EnableOrDisableItem(c echo.Context) error {
 		"message": fmt.Sprintf("item_id: %s is successfully is activated to: %v", itemId, res),
 	})
 }
+
+func (h *itemHttpHandler) FindItemCategories(c echo.Context) error {
+	ctx := context.Background()
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"categories": h.itemUsecase.FindItemCategories(ctx),
+		"rarities":   item.Rarities(),
+	})
+}
+
+func (h *itemHttpHandler) SearchItems(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.ItemFullTextSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.SearchItems(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+// Note that: the file is the multipart field "file", the format is taken from the format query or the file extension
+func (h *itemHttpHandler) ImportItems(c echo.Context) error {
+	ctx := context.Background()
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, 10<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: file is required")
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: read file failed")
+	}
+	defer file.Close()
+
+	res, err := h.itemUsecase.ImportItems(ctx, c.Get("player_id").(string), format, file, dryRun)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) ExportItems(c echo.Context) error {
+	ctx := context.Background()
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = itemUsecase.ImportFormatJson
+	}
+
+	// The export is written to a buffer first, so an error can still be sent as a json response
+	buf := new(bytes.Buffer)
+	if err := h.itemUsecase.ExportItems(ctx, format, buf); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	contentType := echo.MIMEApplicationJSONCharsetUTF8
+	if format == itemUsecase.ImportFormatCsv {
+		contentType = "text/csv; charset=utf-8"
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=items.%s", format))
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+func (h *itemHttpHandler) UploadItemImage(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
+
+	// Note that: the body is limited before it is parsed, the usecase checks the size of the file itself
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, h.cfg.Blob.MaxItemImageSize+(1<<20))
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: image is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: read image failed")
+	}
+	defer file.Close()
+
+	res, err := h.itemUsecase.UploadItemImage(ctx, h.cfg, c.Get("player_id").(string), itemId, file)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) FindItemRevisions(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.ItemRevisionSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.FindItemRevisions(ctx, h.cfg, itemId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) RevertItem(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: revision is invalid")
+	}
+
+	res, err := h.itemUsecase.RevertItem(ctx, c.Get("player_id").(string), itemId, revision)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) CreateItemCampaign(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.CreateItemCampaignReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.CreateItemCampaign(ctx, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *itemHttpHandler) FindItemCampaigns(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.ItemCampaignSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.FindItemCampaigns(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) EndItemCampaign(c echo.Context) error {
+	ctx := context.Background()
+
+	campaignId := strings.TrimPrefix(c.Param("campaign_id"), "campaign:")
+
+	res, err := h.itemUsecase.EndItemCampaign(ctx, campaignId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) CreateItemPriceSchedule(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(item.CreateItemPriceScheduleReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.itemUsecase.CreateItemPriceSchedule(ctx, c.Get("player_id").(string), itemId, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *itemHttpHandler) FindItemPriceSchedules(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
+
+	res, err := h.itemUsecase.FindItemPriceSchedules(ctx, itemId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *itemHttpHandler) CancelItemPriceSchedule(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := strings.TrimPrefix(c.Param("item_id"), "item:")
+
+	if err := h.itemUsecase.CancelItemPriceSchedule(ctx, itemId, c.Param("schedule_id")); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"message": fmt.Sprintf("schedule_id: %s is cancelled", c.Param("schedule_id")),
+	})
+}
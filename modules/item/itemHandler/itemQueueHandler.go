@@ -0,0 +1,63 @@
+package itemHandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+)
+
+type (
+	ItemQueueHandlerService interface {
+		PublishItemAlerts()
+	}
+
+	itemQueueHandler struct {
+		cfg         *config.Config
+		itemUsecase itemUsecase.ItemUsecaseService
+	}
+)
+
+func NewItemQueueHandler(cfg *config.Config, itemUsecase itemUsecase.ItemUsecaseService) ItemQueueHandlerService {
+	return &itemQueueHandler{
+		cfg:         cfg,
+		itemUsecase: itemUsecase,
+	}
+}
+
+// Note that: the item service does not consume a topic, it checks the prices and publishes the alerts every interval
+func (h *itemQueueHandler) PublishItemAlerts() {
+	ctx := context.Background()
+
+	interval := h.cfg.ItemAlert.Interval
+	if interval <= 0 {
+		interval = 30
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	log.Println("Start PublishItemAlerts ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.itemUsecase.CheckItemPrices(ctx); err != nil {
+				log.Println("Error: CheckItemPrices failed: ", err.Error())
+			}
+			if err := h.itemUsecase.PublishItemAlerts(ctx, h.cfg); err != nil {
+				log.Println("Error: PublishItemAlerts failed: ", err.Error())
+			}
+		case <-sigchan:
+			log.Println("Stop PublishItemAlerts...")
+			return
+		}
+	}
+}
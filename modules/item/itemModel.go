@@ -1,36 +1,181 @@
 package item
 
-import "github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+import (
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+)
 
 type (
 	CreateItemReq struct {
-		Title    string  `json:"title" validate:"required,max=64"`
-		Price    float64 `json:"price" validate:"required"`
-		Damage   int     `json:"damage" validate:"required"`
-		ImageUrl string  `json:"image_url" validate:"required,max=255"`
+		Title      string         `json:"title" validate:"required,max=64"`
+		Price      float64        `json:"price" validate:"required"`
+		Damage     int            `json:"damage"`
+		ImageUrl   string         `json:"image_url" validate:"required,max=255"`
+		Category   string         `json:"category" validate:"required"`
+		Rarity     string         `json:"rarity"`
+		Attributes map[string]any `json:"attributes"`
 	}
 
 	ItemShowCase struct {
-		ItemId   string  `json:"item_id"`
-		Title    string  `json:"title"`
-		Price    float64 `json:"price"`
-		Damage   int     `json:"damage"`
-		ImageUrl string  `json:"image_url"`
+		ItemId     string         `json:"item_id"`
+		Title      string         `json:"title"`
+		Price      float64        `json:"price"`
+		Damage     int            `json:"damage"`
+		ImageUrl   string         `json:"image_url"`
+		Category   string         `json:"category,omitempty"`
+		Rarity     string         `json:"rarity,omitempty"`
+		Attributes map[string]any `json:"attributes,omitempty"`
+		// Note that: the key is the size of the thumbnail in pixel, e.g. "128"
+		Thumbnails map[string]string `json:"thumbnails,omitempty"`
+		Revision   int               `json:"revision"`
+		// Note that: price is the effective price, base_price and the sale are only set when a campaign discounts the item
+		BasePrice float64   `json:"base_price,omitempty"`
+		Sale      *ItemSale `json:"sale,omitempty"`
+		// Note that: the effective price that is stored, it is the key of the price cursor
+		EffectivePrice float64 `json:"-"`
+	}
+
+	ItemSale struct {
+		CampaignId string    `json:"campaign_id"`
+		Title      string    `json:"title"`
+		EndAt      time.Time `json:"end_at"`
 	}
 
+	// Note that: attr is repeated as name:min..max, e.g. attr=defense:10..50, one side of the range can be left out
 	ItemSearchReq struct {
-		Title string `query:"title" validate:"max=64"`
+		Title    string   `query:"title" validate:"max=64"`
+		Category string   `query:"category" validate:"max=32"`
+		Rarity   []string `query:"rarity"`
+		Attr     []string `query:"attr"`
 		models.PaginateReq
 	}
 
+	// Note that: attributes replace all of the attributes of the item when they are set
 	ItemUpdateReq struct {
-		Title    string  `json:"title" validate:"required,max=64"`
-		Price    float64 `json:"price" validate:"required"`
-		ImageUrl string  `json:"image_url" validate:"required,max=255"`
-		Damage   int     `json:"damage" validate:"required"`
+		Title      string         `json:"title" validate:"required,max=64"`
+		Price      float64        `json:"price" validate:"required"`
+		ImageUrl   string         `json:"image_url" validate:"required,max=255"`
+		Damage     int            `json:"damage" validate:"required"`
+		Category   string         `json:"category"`
+		Rarity     string         `json:"rarity"`
+		Attributes map[string]any `json:"attributes"`
+	}
+
+	// Note that: sort is relevance, newest, price_asc, price_desc, damage_asc or damage_desc, relevance needs q
+	ItemFullTextSearchReq struct {
+		Q         string   `query:"q" validate:"max=128"`
+		Category  string   `query:"category" validate:"max=32"`
+		Rarity    []string `query:"rarity"`
+		MinPrice  string   `query:"min_price"`
+		MaxPrice  string   `query:"max_price"`
+		MinDamage string   `query:"min_damage"`
+		MaxDamage string   `query:"max_damage"`
+		Sort      string   `query:"sort" validate:"max=32"`
+		Offset    int      `query:"offset" validate:"min=0"`
+		Limit     int      `query:"limit" validate:"max=50"`
+	}
+
+	ItemFullTextSearchRes struct {
+		Data   []*ItemShowCase  `json:"data"`
+		Total  int64            `json:"total"`
+		Offset int              `json:"offset"`
+		Limit  int              `json:"limit"`
+		Facets *ItemSearchFacet `json:"facets"`
+	}
+
+	// Note that: the category counts ignore the category filter, so every category of the result can be picked
+	ItemSearchFacet struct {
+		Category map[string]int64 `json:"category"`
+	}
+
+	// The parsed search that is given to the search backend
+	ItemSearchQuery struct {
+		Text      string
+		Category  string
+		Rarities  []string
+		MinPrice  *float64
+		MaxPrice  *float64
+		MinDamage *float64
+		MaxDamage *float64
+		Sort      string
+		Offset    int
+		Limit     int
+	}
+
+	ItemSearchResult struct {
+		Items          []*ItemShowCase
+		Total          int64
+		CategoryFacets map[string]int64
+	}
+
+	// Note that: a row of the import and the export, in csv the attributes column is a json object
+	ItemImportRow struct {
+		Title       string         `json:"title"`
+		Price       float64        `json:"price"`
+		Damage      int            `json:"damage"`
+		ImageUrl    string         `json:"image_url"`
+		Category    string         `json:"category"`
+		Rarity      string         `json:"rarity"`
+		Attributes  map[string]any `json:"attributes,omitempty"`
+		UsageStatus *bool          `json:"usage_status,omitempty"`
+	}
+
+	ItemImportRes struct {
+		DryRun    bool               `json:"dry_run"`
+		Total     int                `json:"total"`
+		Created   int                `json:"created"`
+		Updated   int                `json:"updated"`
+		Unchanged int                `json:"unchanged"`
+		Failed    int                `json:"failed"`
+		Errors    []*ItemImportError `json:"errors"`
+	}
+
+	// Note that: row is the line of the csv without the header or the index of the json array, both start from 1
+	ItemImportError struct {
+		Row   int    `json:"row"`
+		Title string `json:"title"`
+		Error string `json:"error"`
+	}
+
+	ItemRevisionSearchReq struct {
+		models.PaginateReq
+	}
+
+	// Note that: a campaign has item_ids, a category or both, discount_value is 1 to 100 for percent
+	CreateItemCampaignReq struct {
+		Title         string    `json:"title" validate:"required,max=64"`
+		DiscountType  string    `json:"discount_type" validate:"required"`
+		DiscountValue float64   `json:"discount_value" validate:"required"`
+		ItemIds       []string  `json:"item_ids"`
+		Category      string    `json:"category"`
+		StartAt       time.Time `json:"start_at" validate:"required"`
+		EndAt         time.Time `json:"end_at" validate:"required"`
+	}
+
+	ItemCampaignSearchReq struct {
+		// Note that: active, scheduled, ended or empty for every campaign
+		Status string `query:"status"`
+		models.PaginateReq
+	}
+
+	CreateItemPriceScheduleReq struct {
+		Price   float64   `json:"price" validate:"required"`
+		StartAt time.Time `json:"start_at" validate:"required"`
 	}
 
 	EnableOrDisableItemReq struct {
 		UsageStatus bool `json:"usage_status"`
 	}
+
+	// Note that: the message of an alert, the player service notifies the players who wishlisted the item
+	ItemAlertEvent struct {
+		AlertId       string    `json:"alert_id" validate:"required"`
+		Type          string    `json:"type" validate:"required"`
+		ItemId        string    `json:"item_id" validate:"required"`
+		Title         string    `json:"title"`
+		Price         float64   `json:"price"`
+		PreviousPrice float64   `json:"previous_price"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
 )
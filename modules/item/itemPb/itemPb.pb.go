@@ -122,11 +122,16 @@ type Item struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title    string  `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Price    float64 `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
-	ImageUrl string  `protobuf:"bytes,4,opt,name=imageUrl,proto3" json:"imageUrl,omitempty"`
-	Damage   int32   `protobuf:"varint,5,opt,name=damage,proto3" json:"damage,omitempty"`
+	Id         string                         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title      string                         `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Price      float64                        `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	ImageUrl   string                         `protobuf:"bytes,4,opt,name=imageUrl,proto3" json:"imageUrl,omitempty"`
+	Damage     int32                          `protobuf:"varint,5,opt,name=damage,proto3" json:"damage,omitempty"`
+	Category   string                         `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	Rarity     string                         `protobuf:"bytes,7,opt,name=rarity,proto3" json:"rarity,omitempty"`
+	Attributes map[string]*ItemAttributeValue `protobuf:"bytes,8,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Thumbnails map[string]string              `protobuf:"bytes,9,rep,name=thumbnails,proto3" json:"thumbnails,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Revision   int64                          `protobuf:"varint,10,opt,name=revision,proto3" json:"revision,omitempty"`
 }
 
 func (x *Item) Reset() {
@@ -196,6 +201,149 @@ func (x *Item) GetDamage() int32 {
 	return 0
 }
 
+func (x *Item) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Item) GetRarity() string {
+	if x != nil {
+		return x.Rarity
+	}
+	return ""
+}
+
+func (x *Item) GetAttributes() map[string]*ItemAttributeValue {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Item) GetThumbnails() map[string]string {
+	if x != nil {
+		return x.Thumbnails
+	}
+	return nil
+}
+
+func (x *Item) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type ItemAttributeValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Value:
+	//	*ItemAttributeValue_IntValue
+	//	*ItemAttributeValue_FloatValue
+	//	*ItemAttributeValue_StringValue
+	//	*ItemAttributeValue_BoolValue
+	Value isItemAttributeValue_Value `protobuf_oneof:"value"`
+}
+
+func (x *ItemAttributeValue) Reset() {
+	*x = ItemAttributeValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ItemAttributeValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemAttributeValue) ProtoMessage() {}
+
+func (x *ItemAttributeValue) ProtoReflect() protoreflect.Message {
+	mi := &file_modules_item_itemPb_itemPb_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemAttributeValue.ProtoReflect.Descriptor instead.
+func (*ItemAttributeValue) Descriptor() ([]byte, []int) {
+	return file_modules_item_itemPb_itemPb_proto_rawDescGZIP(), []int{3}
+}
+
+func (m *ItemAttributeValue) GetValue() isItemAttributeValue_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *ItemAttributeValue) GetIntValue() int64 {
+	if x, ok := x.GetValue().(*ItemAttributeValue_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (x *ItemAttributeValue) GetFloatValue() float64 {
+	if x, ok := x.GetValue().(*ItemAttributeValue_FloatValue); ok {
+		return x.FloatValue
+	}
+	return 0
+}
+
+func (x *ItemAttributeValue) GetStringValue() string {
+	if x, ok := x.GetValue().(*ItemAttributeValue_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (x *ItemAttributeValue) GetBoolValue() bool {
+	if x, ok := x.GetValue().(*ItemAttributeValue_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+type isItemAttributeValue_Value interface {
+	isItemAttributeValue_Value()
+}
+
+type ItemAttributeValue_IntValue struct {
+	IntValue int64 `protobuf:"varint,1,opt,name=intValue,proto3,oneof"`
+}
+
+type ItemAttributeValue_FloatValue struct {
+	FloatValue float64 `protobuf:"fixed64,2,opt,name=floatValue,proto3,oneof"`
+}
+
+type ItemAttributeValue_StringValue struct {
+	StringValue string `protobuf:"bytes,3,opt,name=stringValue,proto3,oneof"`
+}
+
+type ItemAttributeValue_BoolValue struct {
+	BoolValue bool `protobuf:"varint,4,opt,name=boolValue,proto3,oneof"`
+}
+
+func (*ItemAttributeValue_IntValue) isItemAttributeValue_Value() {}
+
+func (*ItemAttributeValue_FloatValue) isItemAttributeValue_Value() {}
+
+func (*ItemAttributeValue_StringValue) isItemAttributeValue_Value() {}
+
+func (*ItemAttributeValue_BoolValue) isItemAttributeValue_Value() {}
+
 var File_modules_item_itemPb_itemPb_proto protoreflect.FileDescriptor
 
 var file_modules_item_itemPb_itemPb_proto_rawDesc = []byte{
@@ -206,23 +354,54 @@ var file_modules_item_itemPb_itemPb_proto_rawDesc = []byte{
 	0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0x30, 0x0a, 0x11, 0x46, 0x69, 0x6e,
 	0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x49, 0x64, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1b,
 	0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e,
-	0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x76, 0x0a, 0x04, 0x49,
-	0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69,
-	0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12,
-	0x1a, 0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x64,
-	0x61, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x61, 0x6d,
-	0x61, 0x67, 0x65, 0x32, 0x4b, 0x0a, 0x0f, 0x49, 0x74, 0x65, 0x6d, 0x47, 0x72, 0x70, 0x63, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x64, 0x49, 0x74,
-	0x65, 0x6d, 0x73, 0x49, 0x6e, 0x49, 0x64, 0x73, 0x12, 0x12, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x49,
-	0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x12, 0x2e, 0x46,
-	0x69, 0x6e, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x49, 0x64, 0x73, 0x52, 0x65, 0x73,
-	0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x52,
-	0x61, 0x79, 0x61, 0x74, 0x6f, 0x31, 0x35, 0x39, 0x2f, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x2d, 0x73,
-	0x65, 0x6b, 0x61, 0x69, 0x2d, 0x73, 0x68, 0x6f, 0x70, 0x2d, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69,
-	0x61, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xc7, 0x03, 0x0a, 0x04,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x61,
+	0x6d, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x35, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x49,
+	0x74, 0x65, 0x6d, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12,
+	0x35, 0x0a, 0x0a, 0x74, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x54, 0x68, 0x75, 0x6d, 0x62,
+	0x6e, 0x61, 0x69, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x68, 0x75, 0x6d,
+	0x62, 0x6e, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x1a, 0x52, 0x0a, 0x0f, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x41, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3d, 0x0a, 0x0f, 0x54, 0x68, 0x75, 0x6d, 0x62, 0x6e,
+	0x61, 0x69, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa1, 0x01, 0x0a, 0x12, 0x49, 0x74, 0x65, 0x6d, 0x41, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c, 0x0a, 0x08,
+	0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0a, 0x66, 0x6c,
+	0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00,
+	0x52, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x22, 0x0a, 0x0b,
+	0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x1e, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x32, 0x4b, 0x0a, 0x0f, 0x49, 0x74, 0x65,
+	0x6d, 0x47, 0x72, 0x70, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x0e,
+	0x46, 0x69, 0x6e, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x49, 0x64, 0x73, 0x12, 0x12,
+	0x2e, 0x46, 0x69, 0x6e, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e, 0x49, 0x64, 0x73, 0x52,
+	0x65, 0x71, 0x1a, 0x12, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x49, 0x6e,
+	0x49, 0x64, 0x73, 0x52, 0x65, 0x73, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x52, 0x61, 0x79, 0x61, 0x74, 0x6f, 0x31, 0x35, 0x39, 0x2f, 0x68,
+	0x65, 0x6c, 0x6c, 0x6f, 0x2d, 0x73, 0x65, 0x6b, 0x61, 0x69, 0x2d, 0x73, 0x68, 0x6f, 0x70, 0x2d,
+	0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -237,21 +416,27 @@ func file_modules_item_itemPb_itemPb_proto_rawDescGZIP() []byte {
 	return file_modules_item_itemPb_itemPb_proto_rawDescData
 }
 
-var file_modules_item_itemPb_itemPb_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_modules_item_itemPb_itemPb_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_modules_item_itemPb_itemPb_proto_goTypes = []interface{}{
-	(*FindItemsInIdsReq)(nil), // 0: FindItemsInIdsReq
-	(*FindItemsInIdsRes)(nil), // 1: FindItemsInIdsRes
-	(*Item)(nil),              // 2: Item
+	(*FindItemsInIdsReq)(nil),  // 0: FindItemsInIdsReq
+	(*FindItemsInIdsRes)(nil),  // 1: FindItemsInIdsRes
+	(*Item)(nil),               // 2: Item
+	(*ItemAttributeValue)(nil), // 3: ItemAttributeValue
+	nil,                        // 4: Item.AttributesEntry
+	nil,                        // 5: Item.ThumbnailsEntry
 }
 var file_modules_item_itemPb_itemPb_proto_depIdxs = []int32{
 	2, // 0: FindItemsInIdsRes.items:type_name -> Item
-	0, // 1: ItemGrpcService.FindItemsInIds:input_type -> FindItemsInIdsReq
-	1, // 2: ItemGrpcService.FindItemsInIds:output_type -> FindItemsInIdsRes
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 1: Item.attributes:type_name -> Item.AttributesEntry
+	5, // 2: Item.thumbnails:type_name -> Item.ThumbnailsEntry
+	3, // 3: Item.AttributesEntry.value:type_name -> ItemAttributeValue
+	0, // 4: ItemGrpcService.FindItemsInIds:input_type -> FindItemsInIdsReq
+	1, // 5: ItemGrpcService.FindItemsInIds:output_type -> FindItemsInIdsRes
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_modules_item_itemPb_itemPb_proto_init() }
@@ -296,6 +481,24 @@ func file_modules_item_itemPb_itemPb_proto_init() {
 				return nil
 			}
 		}
+		file_modules_item_itemPb_itemPb_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ItemAttributeValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_modules_item_itemPb_itemPb_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*ItemAttributeValue_IntValue)(nil),
+		(*ItemAttributeValue_FloatValue)(nil),
+		(*ItemAttributeValue_StringValue)(nil),
+		(*ItemAttributeValue_BoolValue)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -303,7 +506,7 @@ func file_modules_item_itemPb_itemPb_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_modules_item_itemPb_itemPb_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
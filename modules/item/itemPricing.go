@@ -0,0 +1,79 @@
+package item
+
+import (
+	"math"
+	"time"
+)
+
+// Discount types
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// ItemPrice is the price of an item at a time, BasePrice is the price before the discount of the campaign
+type ItemPrice struct {
+	Price     float64
+	BasePrice float64
+	Campaign  *ItemCampaign
+}
+
+func (c *ItemCampaign) IsActive(now time.Time) bool {
+	return !now.Before(c.StartAt) && now.Before(c.EndAt)
+}
+
+// Applies reports if the campaign discounts the item, itemId is item:<id>
+func (c *ItemCampaign) Applies(itemId, category string) bool {
+	if c.Category != "" && c.Category == NormalizeCategory(category) {
+		return true
+	}
+	for _, v := range c.ItemIds {
+		if v == itemId {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns the price after the discount, it is never below 0
+func (c *ItemCampaign) Discount(price float64) float64 {
+	switch c.DiscountType {
+	case DiscountPercent:
+		price = price * (100 - c.DiscountValue) / 100
+	case DiscountFixed:
+		price = price - c.DiscountValue
+	}
+	return math.Max(0, math.Round(price*100)/100)
+}
+
+// ResolvePrice is the one place where the price of an item is decided: the latest schedule that has started replaces the price
+// of the item, then the active campaign with the lowest price is applied, the campaigns do not stack
+func ResolvePrice(itemId, category string, price float64, schedules []*ItemPriceSchedule, campaigns []*ItemCampaign, now time.Time) *ItemPrice {
+	var latest *ItemPriceSchedule
+	for _, s := range schedules {
+		if s.ItemId != itemId || s.SupersededAt != nil || s.StartAt.After(now) {
+			continue
+		}
+		if latest == nil || s.StartAt.After(latest.StartAt) {
+			latest = s
+		}
+	}
+	if latest != nil {
+		price = latest.Price
+	}
+
+	result := &ItemPrice{
+		Price:     price,
+		BasePrice: price,
+	}
+	for _, c := range campaigns {
+		if !c.IsActive(now) || !c.Applies(itemId, category) {
+			continue
+		}
+		if discounted := c.Discount(price); discounted < result.Price {
+			result.Price = discounted
+			result.Campaign = c
+		}
+	}
+	return result
+}
@@ -2,11 +2,17 @@ package itemRepository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/blobstore"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,6 +20,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Note that: the title of an item is unique, see the index of the migration
+var ErrItemTitleExists = errors.New("error: this title is already exist")
+
 type (
 	ItemRepositoryService interface {
 		IsUniqueItem(pctx context.Context, title string) bool
@@ -21,8 +30,33 @@ type (
 		FindOneItem(pctx context.Context, itemId string) (*item.Item, error)
 		FindManyItems(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemShowCase, error)
 		CountItems(pctx context.Context, filter primitive.D) (int64, error)
-		UpdateOneItem(pctx context.Context, itemId string, req primitive.M) error
-		EnableOrDisableItem(pctx context.Context, itemId string, isActive bool) error
+		FindOneItemByTitle(pctx context.Context, title string) (*item.Item, error)
+		UpdateOneItemRevision(pctx context.Context, itemId string, revision int, req primitive.M) (*item.Item, error)
+		RestoreOneItemRevision(pctx context.Context, req *item.Item, revision int) error
+		FindAllItems(pctx context.Context) ([]*item.Item, error)
+		InsertOneItemRevision(pctx context.Context, req *item.ItemRevision) error
+		FindOneItemRevision(pctx context.Context, itemId string, revision int) (*item.ItemRevision, error)
+		FindManyItemRevisions(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemRevision, error)
+		CountItemRevisions(pctx context.Context, filter primitive.D) (int64, error)
+		InsertOneItemCampaign(pctx context.Context, req *item.ItemCampaign) (primitive.ObjectID, error)
+		FindOneItemCampaign(pctx context.Context, campaignId string) (*item.ItemCampaign, error)
+		FindManyItemCampaigns(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemCampaign, error)
+		CountItemCampaigns(pctx context.Context, filter primitive.D) (int64, error)
+		UpdateOneItemCampaignEndAt(pctx context.Context, campaignId string, endAt time.Time) error
+		InsertOneItemPriceSchedule(pctx context.Context, req *item.ItemPriceSchedule) (primitive.ObjectID, error)
+		FindManyItemPriceSchedules(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemPriceSchedule, error)
+		DeleteOneItemPriceSchedule(pctx context.Context, itemId, scheduleId string, now time.Time) error
+		SupersedeItemPriceSchedules(pctx context.Context, itemId string, now time.Time) error
+		UpdateOneItemEffectivePrice(pctx context.Context, itemId string, price float64) error
+		SwapItemPriceWatch(pctx context.Context, itemId string, price float64, now time.Time) (float64, bool, error)
+		InsertOneItemAlert(pctx context.Context, req *item.ItemAlert) error
+		FindManyItemAlerts(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemAlert, error)
+		UpdateOneItemAlertPublished(pctx context.Context, alertId primitive.ObjectID, now time.Time) error
+		PushItemAlert(pctx context.Context, cfg *config.Config, req *item.ItemAlertEvent) error
+		GetItemPriceCheckedAt(pctx context.Context) (time.Time, error)
+		UpsertItemPriceCheckedAt(pctx context.Context, checkedAt time.Time) error
+		PutOneBlob(pctx context.Context, cfg *config.Config, key, contentType string, body io.Reader) (string, error)
+		DeleteOneBlob(pctx context.Context, cfg *config.Config, key string) error
 	}
 
 	itemRepository struct {
@@ -63,6 +97,9 @@ func (r *itemRepository) InsertOneItem(pctx context.Context, req *item.Item) (pr
 	itemId, err := col.InsertOne(ctx, req)
 	if err != nil {
 		log.Printf("Error: InsertOneItem: %s", err.Error())
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.NilObjectID, ErrItemTitleExists
+		}
 		return primitive.NilObjectID, errors.New("error: insert one item failed")
 	}
 
@@ -106,13 +143,7 @@ func (r *itemRepository) FindManyItems(pctx context.Context, filter primitive.D,
 			return make([]*item.ItemShowCase, 0), errors.New("error: find many items failed")
 		}
 
-		results = append(results, &item.ItemShowCase{
-			ItemId:   "item:" + result.Id.Hex(),
-			Title:    result.Title,
-			Price:    result.Price,
-			Damage:   result.Damage,
-			ImageUrl: result.ImageUrl,
-		})
+		results = append(results, itemToShowCase(result))
 	}
 
 	return results, nil
@@ -134,36 +165,529 @@ func (r *itemRepository) CountItems(pctx context.Context, filter primitive.D) (i
 	return count, nil
 }
 
-func (r *itemRepository) UpdateOneItem(pctx context.Context, itemId string, req primitive.M) error {
+func (r *itemRepository) FindOneItemByTitle(pctx context.Context, title string) (*item.Item, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	// Note that: an item that is not found is not an error, the import creates it
+	result := new(item.Item)
+	if err := col.FindOne(ctx, bson.M{"title": title}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("Error: FindOneItemByTitle failed: %s", err.Error())
+		return nil, errors.New("error: find item by title failed")
+	}
+
+	return result, nil
+}
+
+// Note that: the update only applies when the item is still at the revision that was read, the item after the update is returned
+func (r *itemRepository) UpdateOneItemRevision(pctx context.Context, itemId string, revision int, req primitive.M) (*item.Item, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	// The items before the history do not have a revision field
+	var revisionFilter any = revision
+	if revision == 0 {
+		revisionFilter = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	set := bson.M{}
+	for k, v := range req {
+		set[k] = v
+	}
+	set["revision"] = revision + 1
+
+	result := new(item.Item)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(itemId), "revision": revisionFilter},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("error: item has been changed by another request, try again")
+		}
+		log.Printf("Error: UpdateOneItemRevision failed: %s", err.Error())
+		return nil, errors.New("error: update one item failed")
+	}
+
+	return result, nil
+}
+
+func (r *itemRepository) FindAllItems(pctx context.Context) ([]*item.Item, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("items")
+
+	cursors, err := col.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		log.Printf("Error: FindAllItems failed: %s", err.Error())
+		return nil, errors.New("error: find all items failed")
+	}
+
+	results := make([]*item.Item, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindAllItems failed: %s", err.Error())
+		return nil, errors.New("error: find all items failed")
+	}
+
+	return results, nil
+}
+
+// Note that: the item is only put back when it is still at the revision of the update that is undone
+func (r *itemRepository) RestoreOneItemRevision(pctx context.Context, req *item.Item, revision int) error {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
 
 	db := r.itemDbConn(ctx)
 	col := db.Collection("items")
 
-	result, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(itemId)}, bson.M{"$set": req})
+	result, err := col.ReplaceOne(ctx, bson.M{"_id": req.Id, "revision": revision}, req)
 	if err != nil {
-		log.Printf("Error: UpdateOneItem failed: %s", err.Error())
-		return errors.New("error: update one item failed")
+		log.Printf("Error: RestoreOneItemRevision failed: %s", err.Error())
+		return errors.New("error: restore one item failed")
+	}
+	if result.MatchedCount == 0 {
+		log.Printf("Error: RestoreOneItemRevision failed: item %s is not at revision %d", req.Id.Hex(), revision)
+		return errors.New("error: restore one item failed")
 	}
-	log.Printf("UpdateOneItem result: %v", result.ModifiedCount)
 
 	return nil
 }
 
-func (r *itemRepository) EnableOrDisableItem(pctx context.Context, itemId string, isActive bool) error {
+// Note that: the effective price is derived from the price, it is not a change of the item and has no revision
+func (r *itemRepository) UpdateOneItemEffectivePrice(pctx context.Context, itemId string, price float64) error {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
 
 	db := r.itemDbConn(ctx)
 	col := db.Collection("items")
 
-	result, err := col.UpdateOne(ctx, bson.M{"_id": utils.ConvertToObjectId(itemId)}, bson.M{"$set": bson.M{"usage_status": isActive}})
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(strings.TrimPrefix(itemId, "item:"))},
+		bson.M{"$set": bson.M{"effective_price": price}},
+	); err != nil {
+		log.Printf("Error: UpdateOneItemEffectivePrice failed: %s", err.Error())
+		return errors.New("error: update item effective price failed")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) InsertOneItemRevision(pctx context.Context, req *item.ItemRevision) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_revisions")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneItemRevision failed: %s", err.Error())
+		return errors.New("error: insert one item revision failed")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) FindOneItemRevision(pctx context.Context, itemId string, revision int) (*item.ItemRevision, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_revisions")
+
+	result := new(item.ItemRevision)
+	if err := col.FindOne(ctx, bson.M{"item_id": itemId, "revision": revision}).Decode(result); err != nil {
+		log.Printf("Error: FindOneItemRevision failed: %s", err.Error())
+		return nil, errors.New("error: item revision not found")
+	}
+
+	return result, nil
+}
+
+func (r *itemRepository) FindManyItemRevisions(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemRevision, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_revisions")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyItemRevisions failed: %s", err.Error())
+		return make([]*item.ItemRevision, 0), errors.New("error: find many item revisions failed")
+	}
+
+	results := make([]*item.ItemRevision, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyItemRevisions failed: %s", err.Error())
+		return make([]*item.ItemRevision, 0), errors.New("error: find many item revisions failed")
+	}
+
+	return results, nil
+}
+
+func (r *itemRepository) CountItemRevisions(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_revisions")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountItemRevisions failed: %s", err.Error())
+		return -1, errors.New("error: count item revisions failed")
+	}
+
+	return count, nil
+}
+
+func (r *itemRepository) InsertOneItemCampaign(pctx context.Context, req *item.ItemCampaign) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_campaigns")
+
+	campaignId, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("Error: InsertOneItemCampaign failed: %s", err.Error())
+		return primitive.NilObjectID, errors.New("error: insert one item campaign failed")
+	}
+
+	return campaignId.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *itemRepository) FindOneItemCampaign(pctx context.Context, campaignId string) (*item.ItemCampaign, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_campaigns")
+
+	result := new(item.ItemCampaign)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(campaignId)}).Decode(result); err != nil {
+		log.Printf("Error: FindOneItemCampaign failed: %s", err.Error())
+		return nil, errors.New("error: item campaign not found")
+	}
+
+	return result, nil
+}
+
+func (r *itemRepository) FindManyItemCampaigns(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemCampaign, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_campaigns")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyItemCampaigns failed: %s", err.Error())
+		return make([]*item.ItemCampaign, 0), errors.New("error: find many item campaigns failed")
+	}
+
+	results := make([]*item.ItemCampaign, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyItemCampaigns failed: %s", err.Error())
+		return make([]*item.ItemCampaign, 0), errors.New("error: find many item campaigns failed")
+	}
+
+	return results, nil
+}
+
+func (r *itemRepository) CountItemCampaigns(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_campaigns")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountItemCampaigns failed: %s", err.Error())
+		return -1, errors.New("error: count item campaigns failed")
+	}
+
+	return count, nil
+}
+
+func (r *itemRepository) UpdateOneItemCampaignEndAt(pctx context.Context, campaignId string, endAt time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_campaigns")
+
+	// Note that: only a campaign that has not ended can be ended
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(campaignId), "end_at": bson.M{"$gt": endAt}},
+		bson.M{"$set": bson.M{"end_at": endAt}},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOneItemCampaignEndAt failed: %s", err.Error())
+		return errors.New("error: end item campaign failed")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("error: item campaign has already ended")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) InsertOneItemPriceSchedule(pctx context.Context, req *item.ItemPriceSchedule) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_schedules")
+
+	scheduleId, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("Error: InsertOneItemPriceSchedule failed: %s", err.Error())
+		return primitive.NilObjectID, errors.New("error: insert one item price schedule failed")
+	}
+
+	return scheduleId.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *itemRepository) FindManyItemPriceSchedules(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemPriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_schedules")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyItemPriceSchedules failed: %s", err.Error())
+		return make([]*item.ItemPriceSchedule, 0), errors.New("error: find many item price schedules failed")
+	}
+
+	results := make([]*item.ItemPriceSchedule, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyItemPriceSchedules failed: %s", err.Error())
+		return make([]*item.ItemPriceSchedule, 0), errors.New("error: find many item price schedules failed")
+	}
+
+	return results, nil
+}
+
+// Note that: a schedule that has started is part of the price history, it can not be deleted
+func (r *itemRepository) DeleteOneItemPriceSchedule(pctx context.Context, itemId, scheduleId string, now time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_schedules")
+
+	result, err := col.DeleteOne(ctx, bson.M{
+		"_id":      utils.ConvertToObjectId(scheduleId),
+		"item_id":  itemId,
+		"start_at": bson.M{"$gt": now},
+	})
 	if err != nil {
-		log.Printf("Error: EnableOrDisableItem failed: %s", err.Error())
-		return errors.New("error: enable or disable item failed")
+		log.Printf("Error: DeleteOneItemPriceSchedule failed: %s", err.Error())
+		return errors.New("error: delete item price schedule failed")
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("error: item price schedule not found or has already started")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) SupersedeItemPriceSchedules(pctx context.Context, itemId string, now time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_schedules")
+
+	if _, err := col.UpdateMany(
+		ctx,
+		bson.M{"item_id": itemId, "start_at": bson.M{"$lte": now}, "superseded_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"superseded_at": now}},
+	); err != nil {
+		log.Printf("Error: SupersedeItemPriceSchedules failed: %s", err.Error())
+		return errors.New("error: supersede item price schedules failed")
+	}
+
+	return nil
+}
+
+// SwapItemPriceWatch sets the price of the watch and returns the price before it, ok is false when the watch is new
+// or the price is the same, so only one request sees a change of the price
+func (r *itemRepository) SwapItemPriceWatch(pctx context.Context, itemId string, price float64, now time.Time) (float64, bool, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_watches")
+
+	// The upsert of a watch that already has the price hits the unique index of item_id
+	result := new(item.ItemPriceWatch)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{"item_id": itemId, "price": bson.M{"$ne": price}},
+		bson.M{"$set": bson.M{"price": price, "updated_at": now}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || mongo.IsDuplicateKeyError(err) {
+			return 0, false, nil
+		}
+		log.Printf("Error: SwapItemPriceWatch failed: %s", err.Error())
+		return 0, false, errors.New("error: swap item price watch failed")
+	}
+
+	return result.Price, true, nil
+}
+
+func (r *itemRepository) InsertOneItemAlert(pctx context.Context, req *item.ItemAlert) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_alerts")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneItemAlert failed: %s", err.Error())
+		return errors.New("error: insert one item alert failed")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) FindManyItemAlerts(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemAlert, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_alerts")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyItemAlerts failed: %s", err.Error())
+		return nil, errors.New("error: find many item alerts failed")
+	}
+
+	results := make([]*item.ItemAlert, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyItemAlerts failed: %s", err.Error())
+		return nil, errors.New("error: find many item alerts failed")
+	}
+
+	return results, nil
+}
+
+func (r *itemRepository) UpdateOneItemAlertPublished(pctx context.Context, alertId primitive.ObjectID, now time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_alerts")
+
+	if _, err := col.UpdateOne(ctx, bson.M{"_id": alertId}, bson.M{"$set": bson.M{"published_at": now}}); err != nil {
+		log.Printf("Error: UpdateOneItemAlertPublished failed: %s", err.Error())
+		return errors.New("error: update one item alert failed")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) PushItemAlert(pctx context.Context, cfg *config.Config, req *item.ItemAlertEvent) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: PushItemAlert failed: %s", err.Error())
+		return errors.New("error: push item alert failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue(
+		[]string{cfg.Kafka.Url},
+		cfg.Kafka.ApiKey,
+		cfg.Kafka.Secret,
+		"player",
+		req.Type,
+		reqInBytes,
+	); err != nil {
+		log.Printf("Error: PushItemAlert failed: %s", err.Error())
+		return errors.New("error: push item alert failed")
+	}
+
+	return nil
+}
+
+// Note that: the time of the last check of the campaigns and the schedules, it is zero before the first check
+func (r *itemRepository) GetItemPriceCheckedAt(pctx context.Context) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_checks")
+
+	result := new(struct {
+		CheckedAt time.Time `bson:"checked_at"`
+	})
+	if err := col.FindOne(ctx, bson.M{}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, nil
+		}
+		log.Printf("Error: GetItemPriceCheckedAt failed: %s", err.Error())
+		return time.Time{}, errors.New("error: get item price checked at failed")
+	}
+
+	return result.CheckedAt, nil
+}
+
+func (r *itemRepository) UpsertItemPriceCheckedAt(pctx context.Context, checkedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.itemDbConn(ctx)
+	col := db.Collection("item_price_checks")
+
+	if _, err := col.UpdateOne(ctx, bson.M{}, bson.M{"$set": bson.M{"checked_at": checkedAt}}, options.Update().SetUpsert(true)); err != nil {
+		log.Printf("Error: UpsertItemPriceCheckedAt failed: %s", err.Error())
+		return errors.New("error: upsert item price checked at failed")
+	}
+
+	return nil
+}
+
+func (r *itemRepository) PutOneBlob(pctx context.Context, cfg *config.Config, key, contentType string, body io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	url, err := blobstore.NewBlobStore(&cfg.Blob).Put(ctx, key, contentType, body)
+	if err != nil {
+		log.Printf("Error: PutOneBlob failed: %s", err.Error())
+		return "", errors.New("error: upload file failed")
+	}
+
+	return url, nil
+}
+
+func (r *itemRepository) DeleteOneBlob(pctx context.Context, cfg *config.Config, key string) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	if err := blobstore.NewBlobStore(&cfg.Blob).Delete(ctx, key); err != nil {
+		log.Printf("Error: DeleteOneBlob failed: %s", err.Error())
+		return errors.New("error: delete file failed")
 	}
-	log.Printf("EnableOrDisableItem result: %v", result.ModifiedCount)
 
 	return nil
 }
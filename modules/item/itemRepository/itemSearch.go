@@ -0,0 +1,177 @@
+package itemRepository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type (
+	// ItemSearchService is the search backend of the item catalog, the items that are not in use are never found
+	ItemSearchService interface {
+		SearchItems(pctx context.Context, query *item.ItemSearchQuery) (*item.ItemSearchResult, error)
+	}
+
+	itemMongoSearch struct {
+		db *mongo.Client
+	}
+)
+
+// Note that: the mongo search needs the text index on the title, see the item migration
+func NewItemSearch(db *mongo.Client) ItemSearchService {
+	return &itemMongoSearch{db}
+}
+
+func (s *itemMongoSearch) SearchItems(pctx context.Context, query *item.ItemSearchQuery) (*item.ItemSearchResult, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	col := s.db.Database("item_db").Collection("items")
+
+	cursor, err := col.Aggregate(ctx, SearchItemsPipeline(query))
+	if err != nil {
+		log.Printf("Error: SearchItems failed: %s", err.Error())
+		return nil, errors.New("error: search items failed")
+	}
+	defer cursor.Close(ctx)
+
+	facets := make([]struct {
+		Items []*item.Item `bson:"items"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Categories []struct {
+			Category *string `bson:"_id"`
+			Count    int64   `bson:"count"`
+		} `bson:"categories"`
+	}, 0)
+	if err := cursor.All(ctx, &facets); err != nil || len(facets) == 0 {
+		log.Printf("Error: SearchItems failed: %v", err)
+		return nil, errors.New("error: search items failed")
+	}
+
+	result := &item.ItemSearchResult{
+		Items:          make([]*item.ItemShowCase, 0),
+		CategoryFacets: make(map[string]int64),
+	}
+	for _, v := range facets[0].Items {
+		result.Items = append(result.Items, itemToShowCase(v))
+	}
+	if len(facets[0].Total) > 0 {
+		result.Total = facets[0].Total[0].Count
+	}
+	for _, v := range facets[0].Categories {
+		category := ""
+		if v.Category != nil {
+			category = *v.Category
+		}
+		result.CategoryFacets[item.NormalizeCategory(category)] += v.Count
+	}
+
+	return result, nil
+}
+
+// SearchItemsPipeline is the aggregation of SearchItems, the items, the total and the category counts are one facet
+func SearchItemsPipeline(query *item.ItemSearchQuery) bson.A {
+	// The text search has to be the first stage, the category is matched inside the facet so the category counts ignore it
+	match := bson.D{}
+	if query.Text != "" {
+		match = append(match, bson.E{"$text", bson.D{{"$search", query.Text}}})
+	}
+	match = append(match, bson.E{"usage_status", true})
+	if len(query.Rarities) > 0 {
+		values := bson.A{}
+		for _, rarity := range query.Rarities {
+			values = append(values, rarity)
+			if rarity == item.RarityCommon {
+				values = append(values, "", nil)
+			}
+		}
+		match = append(match, bson.E{"rarity", bson.D{{"$in", values}}})
+	}
+	if r := rangeFilter(query.MinPrice, query.MaxPrice); len(r) > 0 {
+		match = append(match, bson.E{"effective_price", r})
+	}
+	if r := rangeFilter(query.MinDamage, query.MaxDamage); len(r) > 0 {
+		match = append(match, bson.E{"damage", r})
+	}
+
+	categoryMatch := bson.D{}
+	if query.Category == item.CategoryWeapon {
+		categoryMatch = append(categoryMatch, bson.E{"category", bson.D{{"$in", bson.A{query.Category, "", nil}}}})
+	} else if query.Category != "" {
+		categoryMatch = append(categoryMatch, bson.E{"category", query.Category})
+	}
+
+	sort := bson.D{}
+	switch query.Sort {
+	case item.SortRelevance:
+		sort = append(sort, bson.E{"score", -1})
+	case item.SortPriceAsc:
+		sort = append(sort, bson.E{"effective_price", 1})
+	case item.SortPriceDesc:
+		sort = append(sort, bson.E{"effective_price", -1})
+	case item.SortDamageAsc:
+		sort = append(sort, bson.E{"damage", 1})
+	case item.SortDamageDesc:
+		sort = append(sort, bson.E{"damage", -1})
+	default:
+		sort = append(sort, bson.E{"created_at", -1})
+	}
+	sort = append(sort, bson.E{"_id", 1})
+
+	// The text score can not be sorted on inside the facet, it is added as a field before the facet and sorted as one
+	pipeline := bson.A{bson.D{{"$match", match}}}
+	if query.Text != "" {
+		pipeline = append(pipeline, bson.D{{"$addFields", bson.D{{"score", bson.D{{"$meta", "textScore"}}}}}})
+	}
+	pipeline = append(pipeline, bson.D{{"$facet", bson.D{
+		{"items", bson.A{
+			bson.D{{"$match", categoryMatch}},
+			bson.D{{"$sort", sort}},
+			bson.D{{"$skip", query.Offset}},
+			bson.D{{"$limit", query.Limit}},
+		}},
+		{"total", bson.A{
+			bson.D{{"$match", categoryMatch}},
+			bson.D{{"$count", "count"}},
+		}},
+		{"categories", bson.A{
+			bson.D{{"$group", bson.D{{"_id", "$category"}, {"count", bson.D{{"$sum", 1}}}}}},
+		}},
+	}}})
+
+	return pipeline
+}
+
+func rangeFilter(min, max *float64) bson.D {
+	r := bson.D{}
+	if min != nil {
+		r = append(r, bson.E{"$gte", *min})
+	}
+	if max != nil {
+		r = append(r, bson.E{"$lte", *max})
+	}
+	return r
+}
+
+func itemToShowCase(result *item.Item) *item.ItemShowCase {
+	return &item.ItemShowCase{
+		ItemId:         "item:" + result.Id.Hex(),
+		Title:          result.Title,
+		Price:          result.Price,
+		EffectivePrice: result.EffectivePrice,
+		Damage:         result.Damage,
+		ImageUrl:       result.ImageUrl,
+		Category:       item.NormalizeCategory(result.Category),
+		Rarity:         item.NormalizeRarity(result.Rarity),
+		Attributes:     result.Attributes,
+		Thumbnails:     result.Image.ThumbnailUrls(),
+		Revision:       result.Revision,
+	}
+}
@@ -0,0 +1,134 @@
+package itemRepository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+)
+
+type itemMemorySearch struct {
+	items []*item.Item
+}
+
+// Test only: search the given items in memory, a word of the text matches a whole word of the title
+func NewItemMemorySearch(items []*item.Item) ItemSearchService {
+	return &itemMemorySearch{items: items}
+}
+
+func (s *itemMemorySearch) SearchItems(pctx context.Context, query *item.ItemSearchQuery) (*item.ItemSearchResult, error) {
+	terms := searchTerms(query.Text)
+
+	type scoredItem struct {
+		item  *item.Item
+		score int
+	}
+
+	result := &item.ItemSearchResult{
+		Items:          make([]*item.ItemShowCase, 0),
+		CategoryFacets: make(map[string]int64),
+	}
+
+	matches := make([]*scoredItem, 0)
+	for _, v := range s.items {
+		if !v.UsageStatus {
+			continue
+		}
+
+		score := 0
+		if len(terms) > 0 {
+			titleTerms := make(map[string]bool)
+			for _, term := range searchTerms(v.Title) {
+				titleTerms[term] = true
+			}
+			for _, term := range terms {
+				if titleTerms[term] {
+					score++
+				}
+			}
+			if score == 0 {
+				continue
+			}
+		}
+
+		if len(query.Rarities) > 0 && !containsString(query.Rarities, item.NormalizeRarity(v.Rarity)) {
+			continue
+		}
+		if !inRange(v.EffectivePrice, query.MinPrice, query.MaxPrice) || !inRange(float64(v.Damage), query.MinDamage, query.MaxDamage) {
+			continue
+		}
+
+		category := item.NormalizeCategory(v.Category)
+		result.CategoryFacets[category]++
+
+		if query.Category != "" && query.Category != category {
+			continue
+		}
+		matches = append(matches, &scoredItem{item: v, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		switch query.Sort {
+		case item.SortRelevance:
+			if a.score != b.score {
+				return a.score > b.score
+			}
+		case item.SortPriceAsc:
+			if a.item.EffectivePrice != b.item.EffectivePrice {
+				return a.item.EffectivePrice < b.item.EffectivePrice
+			}
+		case item.SortPriceDesc:
+			if a.item.EffectivePrice != b.item.EffectivePrice {
+				return a.item.EffectivePrice > b.item.EffectivePrice
+			}
+		case item.SortDamageAsc:
+			if a.item.Damage != b.item.Damage {
+				return a.item.Damage < b.item.Damage
+			}
+		case item.SortDamageDesc:
+			if a.item.Damage != b.item.Damage {
+				return a.item.Damage > b.item.Damage
+			}
+		default:
+			if !a.item.CreatedAt.Equal(b.item.CreatedAt) {
+				return a.item.CreatedAt.After(b.item.CreatedAt)
+			}
+		}
+		return a.item.Id.Hex() < b.item.Id.Hex()
+	})
+
+	result.Total = int64(len(matches))
+	for i := query.Offset; i < len(matches) && i < query.Offset+query.Limit; i++ {
+		result.Items = append(result.Items, itemToShowCase(matches[i].item))
+	}
+
+	return result, nil
+}
+
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func inRange(value float64, min, max *float64) bool {
+	if min != nil && value < *min {
+		return false
+	}
+	if max != nil && value > *max {
+		return false
+	}
+	return true
+}
@@ -1,16 +1,29 @@
 package itemUsecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
 	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/thumbnail"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,42 +32,198 @@ import (
 
 type (
 	ItemUsecaseService interface {
-		CreateItem(pctx context.Context, req *item.CreateItemReq) (*item.ItemShowCase, error)
+		CreateItem(pctx context.Context, playerId string, req *item.CreateItemReq) (*item.ItemShowCase, error)
 		FindOneItem(pctx context.Context, itemId string) (*item.ItemShowCase, error)
-		FindManyItems(pctx context.Context, basePaginateUrl string, req *item.ItemSearchReq) (*models.PaginateRes, error)
-		EditItem(pctx context.Context, itemId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error)
-		EnableOrDisableItem(pctx context.Context, itemId string) (bool, error)
+		FindManyItems(pctx context.Context, cfg *config.Config, req *item.ItemSearchReq) (*models.PaginateRes, error)
+		EditItem(pctx context.Context, playerId, itemId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error)
+		EnableOrDisableItem(pctx context.Context, playerId, itemId string) (bool, error)
 		FindItemInIds(pctx context.Context, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
+		FindItemCategories(pctx context.Context) []*item.CategorySchema
+		SearchItems(pctx context.Context, req *item.ItemFullTextSearchReq) (*item.ItemFullTextSearchRes, error)
+		ImportItems(pctx context.Context, playerId, format string, body io.Reader, dryRun bool) (*item.ItemImportRes, error)
+		ExportItems(pctx context.Context, format string, w io.Writer) error
+		UploadItemImage(pctx context.Context, cfg *config.Config, playerId, itemId string, body io.Reader) (*item.ItemShowCase, error)
+		FindItemRevisions(pctx context.Context, cfg *config.Config, itemId string, req *item.ItemRevisionSearchReq) (*models.PaginateRes, error)
+		RevertItem(pctx context.Context, playerId, itemId string, revision int) (*item.ItemShowCase, error)
+		CreateItemCampaign(pctx context.Context, playerId string, req *item.CreateItemCampaignReq) (*item.ItemCampaign, error)
+		FindItemCampaigns(pctx context.Context, cfg *config.Config, req *item.ItemCampaignSearchReq) (*models.PaginateRes, error)
+		EndItemCampaign(pctx context.Context, campaignId string) (*item.ItemCampaign, error)
+		CreateItemPriceSchedule(pctx context.Context, playerId, itemId string, req *item.CreateItemPriceScheduleReq) (*item.ItemPriceSchedule, error)
+		FindItemPriceSchedules(pctx context.Context, itemId string) ([]*item.ItemPriceSchedule, error)
+		CancelItemPriceSchedule(pctx context.Context, itemId, scheduleId string) error
+		CheckItemPrices(pctx context.Context) error
+		RefreshEffectivePrices(pctx context.Context) error
+		PublishItemAlerts(pctx context.Context, cfg *config.Config) error
 	}
 
 	itemUsecase struct {
 		itemRepository itemRepository.ItemRepositoryService
+		itemSearch     itemRepository.ItemSearchService
 	}
 )
 
-func NewItemUsecase(itemRepository itemRepository.ItemRepositoryService) ItemUsecaseService {
-	return &itemUsecase{itemRepository}
+func NewItemUsecase(itemRepository itemRepository.ItemRepositoryService, itemSearch itemRepository.ItemSearchService) ItemUsecaseService {
+	return &itemUsecase{itemRepository, itemSearch}
 }
 
-func (u *itemUsecase) CreateItem(pctx context.Context, req *item.CreateItemReq) (*item.ItemShowCase, error) {
-	if !u.itemRepository.IsUniqueItem(pctx, req.Title) {
+func (u *itemUsecase) CreateItem(pctx context.Context, playerId string, req *item.CreateItemReq) (*item.ItemShowCase, error) {
+	result, err := newItem(&item.ItemImportRow{
+		Title:      req.Title,
+		Price:      req.Price,
+		Damage:     req.Damage,
+		ImageUrl:   req.ImageUrl,
+		Category:   req.Category,
+		Rarity:     req.Rarity,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.itemRepository.IsUniqueItem(pctx, result.Title) {
 		return nil, errors.New("error: this title is already exist")
 	}
 
-	itemId, err := u.itemRepository.InsertOneItem(pctx, &item.Item{
-		Title:       req.Title,
-		Price:       req.Price,
-		Damage:      req.Damage,
-		UsageStatus: true,
-		ImageUrl:    req.ImageUrl,
-		CreatedAt:   utils.LocalTime(),
-		UpdatedAt:   utils.LocalTime(),
-	})
+	if err := u.insertItem(pctx, result, &item.ItemRevision{Action: item.RevisionActionCreate, PlayerId: playerId}); err != nil {
+		return nil, err
+	}
+
+	return u.FindOneItem(pctx, result.Id.Hex())
+}
+
+// A new item starts at revision 1, its first revision has no before
+func (u *itemUsecase) insertItem(pctx context.Context, req *item.Item, revision *item.ItemRevision) error {
+	req.Revision = 1
+
+	itemId, err := u.itemRepository.InsertOneItem(pctx, req)
+	if err != nil {
+		return err
+	}
+	req.Id = itemId
+
+	if err := u.insertItemRevision(pctx, nil, req, revision); err != nil {
+		return err
+	}
+
+	// The first price is watched, so the first drop of the price is an alert
+	u.watchPrices(pctx, []string{"item:" + itemId.Hex()}, false)
+
+	return nil
+}
+
+// Every change of an item goes through updateItem, the update only applies to the revision that was read,
+// so two admins can not overwrite each other, and the change is written as a new revision
+func (u *itemUsecase) updateItem(pctx context.Context, current *item.Item, updateReq bson.M, revision *item.ItemRevision) (*item.Item, error) {
+	updateReq["updated_at"] = utils.LocalTime()
+	// The new price is the effective price until watchPrices applies the campaigns and the schedules
+	if price, ok := updateReq["price"]; ok {
+		updateReq["effective_price"] = price
+	}
+
+	result, err := u.itemRepository.UpdateOneItemRevision(pctx, current.Id.Hex(), current.Revision, updateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that: mongo has no transaction on a standalone server, a change without its revision is put back instead
+	if err := u.insertItemRevision(pctx, current, result, revision); err != nil {
+		if err := u.itemRepository.RestoreOneItemRevision(pctx, current, result.Revision); err != nil {
+			log.Printf("Error: item %s revision %d is changed without a history and is not put back: %s", revision.ItemId, result.Revision, err.Error())
+		}
+		return nil, errors.New("error: item is not changed, its revision is not saved, try again")
+	}
+
+	// The price that an admin sets wins over the schedules that have already started
+	if _, ok := updateReq["price"]; ok {
+		if err := u.itemRepository.SupersedeItemPriceSchedules(pctx, "item:"+result.Id.Hex(), utils.LocalTime()); err != nil {
+			return nil, err
+		}
+	}
+
+	_, isPrice := updateReq["price"]
+	_, isUsageStatus := updateReq["usage_status"]
+	if isPrice || isUsageStatus {
+		u.watchPrices(pctx, []string{"item:" + result.Id.Hex()}, !current.UsageStatus && result.UsageStatus)
+	}
+
+	return result, nil
+}
+
+func (u *itemUsecase) insertItemRevision(pctx context.Context, before, after *item.Item, revision *item.ItemRevision) error {
+	revision.ItemId = "item:" + after.Id.Hex()
+	revision.Revision = after.Revision
+	revision.Before = before
+	revision.After = after
+	revision.CreatedAt = utils.LocalTime()
+
+	if err := u.itemRepository.InsertOneItemRevision(pctx, revision); err != nil {
+		log.Printf("Error: item %s revision %d is not saved: %s", revision.ItemId, revision.Revision, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Validate an item for the create and the import, the category, damage and attributes are checked against the schema
+func newItem(req *item.ItemImportRow) (*item.Item, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > 64 {
+		return nil, errors.New("error: title is required and must not be longer than 64 characters")
+	}
+	if req.Price < 0 || math.IsNaN(req.Price) || math.IsInf(req.Price, 0) {
+		return nil, errors.New("error: price must not be negative")
+	}
+	if len(req.ImageUrl) > 255 {
+		return nil, errors.New("error: image_url must not be longer than 255 characters")
+	}
+
+	schema, err := item.FindCategorySchema(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	rarity := item.NormalizeRarity(req.Rarity)
+	if !item.IsValidRarity(rarity) {
+		return nil, fmt.Errorf("error: rarity must be one of %s", strings.Join(item.Rarities(), ", "))
+	}
+
+	if err := validateDamage(schema, req.Damage); err != nil {
+		return nil, err
+	}
+
+	attributes, err := schema.ValidateAttributes(req.Attributes)
 	if err != nil {
 		return nil, err
 	}
 
-	return u.FindOneItem(pctx, itemId.Hex())
+	usageStatus := true
+	if req.UsageStatus != nil {
+		usageStatus = *req.UsageStatus
+	}
+
+	return &item.Item{
+		Title:          title,
+		Price:          req.Price,
+		EffectivePrice: req.Price,
+		Damage:         req.Damage,
+		UsageStatus:    usageStatus,
+		ImageUrl:       req.ImageUrl,
+		Category:       schema.Category,
+		Rarity:         rarity,
+		Attributes:     attributes,
+		CreatedAt:      utils.LocalTime(),
+		UpdatedAt:      utils.LocalTime(),
+	}, nil
+}
+
+func validateDamage(schema *item.CategorySchema, damage int) error {
+	if schema.HasDamage && damage <= 0 {
+		return fmt.Errorf("error: damage is required for category %s", schema.Category)
+	}
+	if !schema.HasDamage && damage != 0 {
+		return fmt.Errorf("error: damage is not allowed for category %s", schema.Category)
+	}
+	return nil
 }
 
 func (u *itemUsecase) FindOneItem(pctx context.Context, itemId string) (*item.ItemShowCase, error) {
@@ -63,84 +232,201 @@ func (u *itemUsecase) FindOneItem(pctx context.Context, itemId string) (*item.It
 		return nil, err
 	}
 
-	return &item.ItemShowCase{
-		ItemId:   "item:" + result.Id.Hex(),
-		Title:    result.Title,
-		Price:    result.Price,
-		Damage:   result.Damage,
-		ImageUrl: result.ImageUrl,
-	}, nil
+	res := &item.ItemShowCase{
+		ItemId:     "item:" + result.Id.Hex(),
+		Title:      result.Title,
+		Price:      result.Price,
+		Damage:     result.Damage,
+		ImageUrl:   result.ImageUrl,
+		Category:   item.NormalizeCategory(result.Category),
+		Rarity:     item.NormalizeRarity(result.Rarity),
+		Attributes: result.Attributes,
+		Thumbnails: result.Image.ThumbnailUrls(),
+		Revision:   result.Revision,
+	}
+	if err := u.resolvePrices(pctx, []*item.ItemShowCase{res}); err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
-func (u *itemUsecase) FindManyItems(pctx context.Context, basePaginateUrl string, req *item.ItemSearchReq) (*models.PaginateRes, error) {
+func (u *itemUsecase) FindManyItems(pctx context.Context, cfg *config.Config, req *item.ItemSearchReq) (*models.PaginateRes, error) {
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "item", &req.PaginateReq, itemSorts)
+	if err != nil {
+		return nil, err
+	}
+
 	findItemsFilter := bson.D{}
-	findItemsOpts := make([]*options.FindOptions, 0)
 
-	countItemsFilter := bson.D{}
+	query := paginate.Query()
 
 	// Filter
-	if req.Start != "" {
-		req.Start = strings.TrimPrefix(req.Start, "item:")
-		findItemsFilter = append(findItemsFilter, bson.E{"_id", bson.D{{"$gt", utils.ConvertToObjectId(req.Start)}}})
+	if len(req.Title) > 64 {
+		return nil, errors.New("error: title must not be longer than 64 characters")
 	}
-
 	if req.Title != "" {
-		findItemsFilter = append(findItemsFilter, bson.E{"title", primitive.Regex{Pattern: req.Title, Options: "i"}})
-		countItemsFilter = append(countItemsFilter, bson.E{"title", primitive.Regex{Pattern: req.Title, Options: "i"}})
+		// Note that: the title is matched as a plain text, it must never be used as a pattern
+		findItemsFilter = append(findItemsFilter, bson.E{"title", primitive.Regex{Pattern: regexp.QuoteMeta(req.Title), Options: "i"}})
+		query.Set("title", req.Title)
+	}
+
+	var schema *item.CategorySchema
+	if req.Category != "" {
+		schema, err = item.FindCategorySchema(req.Category)
+		if err != nil {
+			return nil, err
+		}
+		findItemsFilter = append(findItemsFilter, categoryFilter(schema.Category))
+		query.Set("category", schema.Category)
+	}
+
+	rarities := make([]string, 0)
+	for _, v := range req.Rarity {
+		for _, rarity := range strings.Split(v, ",") {
+			rarity = strings.TrimSpace(rarity)
+			if rarity == "" {
+				continue
+			}
+			if !item.IsValidRarity(rarity) {
+				return nil, fmt.Errorf("error: rarity must be one of %s", strings.Join(item.Rarities(), ", "))
+			}
+			rarities = append(rarities, rarity)
+			query.Add("rarity", rarity)
+		}
+	}
+	if len(rarities) > 0 {
+		findItemsFilter = append(findItemsFilter, rarityFilter(rarities))
+	}
+
+	for _, v := range req.Attr {
+		if schema == nil {
+			return nil, errors.New("error: category is required to filter by attributes")
+		}
+
+		filter, err := attributeRangeFilter(schema, v)
+		if err != nil {
+			return nil, err
+		}
+		findItemsFilter = append(findItemsFilter, filter)
+		query.Add("attr", v)
 	}
 
 	findItemsFilter = append(findItemsFilter, bson.E{"usage_status", true})
-	countItemsFilter = append(countItemsFilter, bson.E{"usage_status", true})
 
-	// Options
-	findItemsOpts = append(findItemsOpts, options.Find().SetSort(bson.D{{"_id", 1}}))
-	findItemsOpts = append(findItemsOpts, options.Find().SetLimit(int64(req.Limit)))
+	countItemsFilter := append(bson.D{}, findItemsFilter...)
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		findItemsFilter = append(findItemsFilter, start)
+	}
 
 	// Find
-	results, err := u.itemRepository.FindManyItems(pctx, findItemsFilter, findItemsOpts)
+	results, err := u.itemRepository.FindManyItems(pctx, findItemsFilter, paginate.FindOptions())
 	if err != nil {
 		return nil, err
 	}
 
+	results, next, prev := models.Page(paginate, results, func(v *item.ItemShowCase) (any, primitive.ObjectID) {
+		id := utils.ConvertToObjectId(strings.TrimPrefix(v.ItemId, "item:"))
+		switch paginate.Sort.Name {
+		case "title":
+			return v.Title, id
+		case "price":
+			return v.EffectivePrice, id
+		case "damage":
+			return v.Damage, id
+		}
+		return id, id
+	})
+
+	if err := u.resolvePrices(pctx, results); err != nil {
+		return nil, err
+	}
+
 	// Count
 	total, err := u.itemRepository.CountItems(pctx, countItemsFilter)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(results) == 0 {
-		return &models.PaginateRes{
-			Data:  make([]*item.ItemShowCase, 0),
-			Total: total,
-			Limit: req.Limit,
-			First: models.FirstPaginate{
-				Href: fmt.Sprintf("%s?limit=%d&title=%s", basePaginateUrl, req.Limit, req.Title),
-			},
-			Next: models.NextPaginate{
-				Start: "",
-				Href:  "",
-			},
-		}, nil
-	}
-
-	return &models.PaginateRes{
-		Data:  results,
-		Total: total,
-		Limit: req.Limit,
-		First: models.FirstPaginate{
-			Href: fmt.Sprintf("%s?limit=%d&title=%s", basePaginateUrl, req.Limit, req.Title),
-		},
-		Next: models.NextPaginate{
-			Start: results[len(results)-1].ItemId,
-			Href:  fmt.Sprintf("%s?limit=%d&title=%s&start=%s", basePaginateUrl, req.Limit, req.Title, results[len(results)-1].ItemId),
-		},
-	}, nil
+	return paginate.NewPaginateRes(results, total, cfg.Paginate.ItemNextPageBasedUrl, query, next, prev), nil
+}
+
+var itemSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+	{Name: "title", Field: "title", Kind: models.SortKindString},
+	{Name: "price", Field: "effective_price", Kind: models.SortKindNumber},
+	{Name: "damage", Field: "damage", Kind: models.SortKindNumber},
+}
+
+// Note that: the items without a category or rarity are weapons of the common tier
+func categoryFilter(category string) bson.E {
+	if category == item.CategoryWeapon {
+		return bson.E{"category", bson.D{{"$in", bson.A{category, "", nil}}}}
+	}
+	return bson.E{"category", category}
+}
+
+func rarityFilter(rarities []string) bson.E {
+	values := bson.A{}
+	for _, rarity := range rarities {
+		values = append(values, rarity)
+		if rarity == item.RarityCommon {
+			values = append(values, "", nil)
+		}
+	}
+	return bson.E{"rarity", bson.D{{"$in", values}}}
+}
+
+// Parse name:min..max, e.g. defense:10..50, defense:10.. or damage:..100
+func attributeRangeFilter(schema *item.CategorySchema, value string) (bson.E, error) {
+	name, bounds, ok := strings.Cut(value, ":")
+	if !ok {
+		return bson.E{}, errors.New("error: attr must be name:min..max")
+	}
+	if !schema.IsRangeAttribute(name) {
+		return bson.E{}, fmt.Errorf("error: attribute %s can not be filtered by range for category %s", name, schema.Category)
+	}
+
+	minText, maxText, ok := strings.Cut(bounds, "..")
+	if !ok || (minText == "" && maxText == "") {
+		return bson.E{}, errors.New("error: attr must be name:min..max")
+	}
+
+	condition := bson.D{}
+	if minText != "" {
+		min, err := strconv.ParseFloat(minText, 64)
+		if err != nil {
+			return bson.E{}, fmt.Errorf("error: attribute %s min is invalid", name)
+		}
+		condition = append(condition, bson.E{"$gte", min})
+	}
+	if maxText != "" {
+		max, err := strconv.ParseFloat(maxText, 64)
+		if err != nil {
+			return bson.E{}, fmt.Errorf("error: attribute %s max is invalid", name)
+		}
+		condition = append(condition, bson.E{"$lte", max})
+	}
+
+	field := "attributes." + name
+	if name == "damage" {
+		field = "damage"
+	}
+	return bson.E{field, condition}, nil
 }
 
-func (u *itemUsecase) EditItem(pctx context.Context, itemId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error) {
+func (u *itemUsecase) EditItem(pctx context.Context, playerId, itemId string, req *item.ItemUpdateReq) (*item.ItemShowCase, error) {
+	current, err := u.itemRepository.FindOneItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update logical
 	updateReq := bson.M{}
-	if req.Title != "" {
+	if req.Title != "" && req.Title != current.Title {
 		if !u.itemRepository.IsUniqueItem(pctx, req.Title) {
 			log.Println("Error: EditItem failed: this title is already exist")
 			return nil, errors.New("error: this title is already exist")
@@ -151,32 +437,76 @@ func (u *itemUsecase) EditItem(pctx context.Context, itemId string, req *item.It
 	if req.ImageUrl != "" {
 		updateReq["image_url"] = req.ImageUrl
 	}
-	if req.Damage > 0 {
-		updateReq["damage"] = req.Damage
-	}
 	if req.Price >= 0 {
 		updateReq["price"] = req.Price
 	}
-	updateReq["updated_at"] = utils.LocalTime()
 
-	if err := u.itemRepository.UpdateOneItem(pctx, itemId, updateReq); err != nil {
+	// The category, damage and attributes are checked together against the schema of the category after the update
+	category := item.NormalizeCategory(current.Category)
+	if req.Category != "" {
+		category = req.Category
+	}
+	schema, err := item.FindCategorySchema(category)
+	if err != nil {
+		return nil, err
+	}
+
+	damage := current.Damage
+	if req.Damage > 0 {
+		damage = req.Damage
+	} else if !schema.HasDamage {
+		damage = 0
+	}
+	if err := validateDamage(schema, damage); err != nil {
+		return nil, err
+	}
+
+	attributes := current.Attributes
+	if req.Attributes != nil {
+		attributes = req.Attributes
+	}
+	attributes, err = schema.ValidateAttributes(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	rarity := item.NormalizeRarity(current.Rarity)
+	if req.Rarity != "" {
+		if !item.IsValidRarity(req.Rarity) {
+			return nil, fmt.Errorf("error: rarity must be one of %s", strings.Join(item.Rarities(), ", "))
+		}
+		rarity = req.Rarity
+	}
+
+	updateReq["category"] = schema.Category
+	updateReq["rarity"] = rarity
+	updateReq["damage"] = damage
+	updateReq["attributes"] = attributes
+
+	if _, err := u.updateItem(pctx, current, updateReq, &item.ItemRevision{Action: item.RevisionActionEdit, PlayerId: playerId}); err != nil {
 		return nil, err
 	}
 
 	return u.FindOneItem(pctx, itemId)
 }
 
-func (u *itemUsecase) EnableOrDisableItem(pctx context.Context, itemId string) (bool, error) {
-	result, err := u.itemRepository.FindOneItem(pctx, itemId)
+func (u *itemUsecase) EnableOrDisableItem(pctx context.Context, playerId, itemId string) (bool, error) {
+	current, err := u.itemRepository.FindOneItem(pctx, itemId)
 	if err != nil {
 		return false, err
 	}
 
-	if err := u.itemRepository.EnableOrDisableItem(pctx, itemId, !result.UsageStatus); err != nil {
+	action := item.RevisionActionEnable
+	if current.UsageStatus {
+		action = item.RevisionActionDisable
+	}
+
+	result, err := u.updateItem(pctx, current, bson.M{"usage_status": !current.UsageStatus}, &item.ItemRevision{Action: action, PlayerId: playerId})
+	if err != nil {
 		return false, err
 	}
 
-	return !result.UsageStatus, nil
+	return result.UsageStatus, nil
 }
 
 func (u *itemUsecase) FindItemInIds(pctx context.Context, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
@@ -195,14 +525,24 @@ func (u *itemUsecase) FindItemInIds(pctx context.Context, req *itemPb.FindItemsI
 		return nil, err
 	}
 
+	// Note that: the payment charges this price, so it must be the same price as the one that is shown
+	if err := u.resolvePrices(pctx, results); err != nil {
+		return nil, err
+	}
+
 	resultsToRes := make([]*itemPb.Item, 0)
 	for _, result := range results {
 		resultsToRes = append(resultsToRes, &itemPb.Item{
-			Id:       result.ItemId,
-			Title:    result.Title,
-			Price:    result.Price,
-			Damage:   int32(result.Damage),
-			ImageUrl: result.ImageUrl,
+			Id:         result.ItemId,
+			Title:      result.Title,
+			Price:      result.Price,
+			Damage:     int32(result.Damage),
+			ImageUrl:   result.ImageUrl,
+			Category:   result.Category,
+			Rarity:     result.Rarity,
+			Attributes: attributesToPb(result.Attributes),
+			Thumbnails: result.Thumbnails,
+			Revision:   int64(result.Revision),
 		})
 	}
 
@@ -210,3 +550,1082 @@ func (u *itemUsecase) FindItemInIds(pctx context.Context, req *itemPb.FindItemsI
 		Items: resultsToRes,
 	}, nil
 }
+
+func attributesToPb(attributes map[string]any) map[string]*itemPb.ItemAttributeValue {
+	results := make(map[string]*itemPb.ItemAttributeValue)
+	for name, value := range attributes {
+		switch v := value.(type) {
+		case int32:
+			results[name] = &itemPb.ItemAttributeValue{Value: &itemPb.ItemAttributeValue_IntValue{IntValue: int64(v)}}
+		case int64:
+			results[name] = &itemPb.ItemAttributeValue{Value: &itemPb.ItemAttributeValue_IntValue{IntValue: v}}
+		case float64:
+			results[name] = &itemPb.ItemAttributeValue{Value: &itemPb.ItemAttributeValue_FloatValue{FloatValue: v}}
+		case string:
+			results[name] = &itemPb.ItemAttributeValue{Value: &itemPb.ItemAttributeValue_StringValue{StringValue: v}}
+		case bool:
+			results[name] = &itemPb.ItemAttributeValue{Value: &itemPb.ItemAttributeValue_BoolValue{BoolValue: v}}
+		}
+	}
+	return results
+}
+
+func (u *itemUsecase) FindItemCategories(pctx context.Context) []*item.CategorySchema {
+	results := make([]*item.CategorySchema, 0)
+	for _, category := range item.Categories() {
+		schema, _ := item.FindCategorySchema(category)
+		results = append(results, schema)
+	}
+	return results
+}
+
+func (u *itemUsecase) SearchItems(pctx context.Context, req *item.ItemFullTextSearchReq) (*item.ItemFullTextSearchRes, error) {
+	query := &item.ItemSearchQuery{
+		Text:   strings.TrimSpace(req.Q),
+		Sort:   req.Sort,
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	}
+
+	if len(query.Text) > 128 {
+		return nil, errors.New("error: q must not be longer than 128 characters")
+	}
+	if query.Offset < 0 || query.Offset > 10000 {
+		return nil, errors.New("error: offset must be between 0 and 10000")
+	}
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+
+	switch query.Sort {
+	case "":
+		query.Sort = item.SortNewest
+		if query.Text != "" {
+			query.Sort = item.SortRelevance
+		}
+	case item.SortRelevance:
+		if query.Text == "" {
+			return nil, errors.New("error: q is required to sort by relevance")
+		}
+	case item.SortNewest, item.SortPriceAsc, item.SortPriceDesc, item.SortDamageAsc, item.SortDamageDesc:
+	default:
+		return nil, errors.New("error: sort must be relevance, newest, price_asc, price_desc, damage_asc or damage_desc")
+	}
+
+	if req.Category != "" {
+		schema, err := item.FindCategorySchema(req.Category)
+		if err != nil {
+			return nil, err
+		}
+		query.Category = schema.Category
+	}
+
+	for _, v := range req.Rarity {
+		for _, rarity := range strings.Split(v, ",") {
+			rarity = strings.TrimSpace(rarity)
+			if rarity == "" {
+				continue
+			}
+			if !item.IsValidRarity(rarity) {
+				return nil, fmt.Errorf("error: rarity must be one of %s", strings.Join(item.Rarities(), ", "))
+			}
+			query.Rarities = append(query.Rarities, rarity)
+		}
+	}
+
+	var err error
+	if query.MinPrice, err = parseBound("min_price", req.MinPrice); err != nil {
+		return nil, err
+	}
+	if query.MaxPrice, err = parseBound("max_price", req.MaxPrice); err != nil {
+		return nil, err
+	}
+	if query.MinDamage, err = parseBound("min_damage", req.MinDamage); err != nil {
+		return nil, err
+	}
+	if query.MaxDamage, err = parseBound("max_damage", req.MaxDamage); err != nil {
+		return nil, err
+	}
+
+	result, err := u.itemSearch.SearchItems(pctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.resolvePrices(pctx, result.Items); err != nil {
+		return nil, err
+	}
+
+	return &item.ItemFullTextSearchRes{
+		Data:   result.Items,
+		Total:  result.Total,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+		Facets: &item.ItemSearchFacet{
+			Category: result.CategoryFacets,
+		},
+	}, nil
+}
+
+func parseBound(name, value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(result) || math.IsInf(result, 0) {
+		return nil, fmt.Errorf("error: %s must be a number", name)
+	}
+	return &result, nil
+}
+
+// Import formats
+const (
+	ImportFormatCsv  = "csv"
+	ImportFormatJson = "json"
+
+	maxImportRows = 5000
+)
+
+var importCsvColumns = []string{"title", "price", "damage", "image_url", "category", "rarity", "attributes", "usage_status"}
+
+// Note that: the items are upserted by title one by one, a row that fails is reported and does not stop the others
+func (u *itemUsecase) ImportItems(pctx context.Context, playerId, format string, body io.Reader, dryRun bool) (*item.ItemImportRes, error) {
+	var rows []*item.ItemImportRow
+	var rowErrors map[int]error
+	var err error
+
+	switch format {
+	case ImportFormatCsv:
+		rows, rowErrors, err = parseImportCsv(body)
+	case ImportFormatJson:
+		rows, rowErrors, err = parseImportJson(body)
+	default:
+		return nil, errors.New("error: format must be csv or json")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &item.ItemImportRes{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: make([]*item.ItemImportError, 0),
+	}
+	fail := func(row int, title string, err error) {
+		res.Failed++
+		res.Errors = append(res.Errors, &item.ItemImportError{Row: row, Title: title, Error: err.Error()})
+	}
+
+	titles := make(map[string]int)
+	for i, row := range rows {
+		rowNumber := i + 1
+
+		if err, ok := rowErrors[rowNumber]; ok {
+			fail(rowNumber, "", err)
+			continue
+		}
+
+		result, err := newItem(row)
+		if err != nil {
+			fail(rowNumber, row.Title, err)
+			continue
+		}
+
+		if first, ok := titles[result.Title]; ok {
+			fail(rowNumber, result.Title, fmt.Errorf("error: title is duplicated with row %d", first))
+			continue
+		}
+		titles[result.Title] = rowNumber
+
+		revision := &item.ItemRevision{Action: item.RevisionActionImport, PlayerId: playerId}
+
+		current, err := u.itemRepository.FindOneItemByTitle(pctx, result.Title)
+		if err != nil {
+			fail(rowNumber, result.Title, err)
+			continue
+		}
+		if current == nil {
+			if dryRun {
+				res.Created++
+				continue
+			}
+			err := u.insertItem(pctx, result, revision)
+			if err == nil {
+				res.Created++
+				continue
+			}
+			if !errors.Is(err, itemRepository.ErrItemTitleExists) {
+				fail(rowNumber, result.Title, err)
+				continue
+			}
+
+			// Note that: another import created the title meanwhile, the row updates that item instead
+			current, err = u.itemRepository.FindOneItemByTitle(pctx, result.Title)
+			if err == nil && current == nil {
+				err = errors.New("error: item not found")
+			}
+			if err != nil {
+				fail(rowNumber, result.Title, err)
+				continue
+			}
+		}
+
+		// A row that is the same as the item is skipped, so a seed that runs again writes no revision
+		if isSameImportItem(current, result) {
+			res.Unchanged++
+			continue
+		}
+
+		if dryRun {
+			res.Updated++
+			continue
+		}
+
+		if _, err := u.updateItem(pctx, current, bson.M{
+			"price":        result.Price,
+			"damage":       result.Damage,
+			"image_url":    result.ImageUrl,
+			"category":     result.Category,
+			"rarity":       result.Rarity,
+			"attributes":   result.Attributes,
+			"usage_status": result.UsageStatus,
+		}, revision); err != nil {
+			fail(rowNumber, result.Title, err)
+			continue
+		}
+		res.Updated++
+	}
+
+	return res, nil
+}
+
+// Note that: the attributes of the item are converted by the schema first, so a number that is read back from mongo
+// has the same go type as the one of the import
+func isSameImportItem(current, req *item.Item) bool {
+	if current.Price != req.Price ||
+		current.Damage != req.Damage ||
+		current.ImageUrl != req.ImageUrl ||
+		item.NormalizeCategory(current.Category) != req.Category ||
+		item.NormalizeRarity(current.Rarity) != req.Rarity ||
+		current.UsageStatus != req.UsageStatus {
+		return false
+	}
+
+	schema, err := item.FindCategorySchema(req.Category)
+	if err != nil {
+		return false
+	}
+	attributes, err := schema.ValidateAttributes(current.Attributes)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(attributes, req.Attributes)
+}
+
+func parseImportCsv(body io.Reader) ([]*item.ItemImportRow, map[int]error, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("error: csv header is required")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		known := false
+		for _, c := range importCsvColumns {
+			if c == name {
+				known = true
+			}
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("error: csv column %s is unknown, the columns are %s", name, strings.Join(importCsvColumns, ", "))
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"title", "price", "category"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("error: csv column %s is required", name)
+		}
+	}
+
+	rows := make([]*item.ItemImportRow, 0)
+	rowErrors := make(map[int]error)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if len(rows) >= maxImportRows {
+			return nil, nil, fmt.Errorf("error: an import must not have more than %d rows", maxImportRows)
+		}
+
+		row := new(item.ItemImportRow)
+		rows = append(rows, row)
+		if err != nil {
+			rowErrors[len(rows)] = errors.New("error: csv row is invalid")
+			continue
+		}
+
+		value := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row.Title = value("title")
+		row.ImageUrl = value("image_url")
+		row.Category = value("category")
+		row.Rarity = value("rarity")
+
+		if row.Price, err = strconv.ParseFloat(value("price"), 64); err != nil {
+			rowErrors[len(rows)] = errors.New("error: price must be a number")
+			continue
+		}
+		if v := value("damage"); v != "" {
+			if row.Damage, err = strconv.Atoi(v); err != nil {
+				rowErrors[len(rows)] = errors.New("error: damage must be an integer")
+				continue
+			}
+		}
+		if v := value("attributes"); v != "" {
+			if err := json.Unmarshal([]byte(v), &row.Attributes); err != nil {
+				rowErrors[len(rows)] = errors.New("error: attributes must be a json object")
+				continue
+			}
+		}
+		if v := value("usage_status"); v != "" {
+			usageStatus, err := strconv.ParseBool(v)
+			if err != nil {
+				rowErrors[len(rows)] = errors.New("error: usage_status must be true or false")
+				continue
+			}
+			row.UsageStatus = &usageStatus
+		}
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseImportJson(body io.Reader) ([]*item.ItemImportRow, map[int]error, error) {
+	raws := make([]json.RawMessage, 0)
+	if err := json.NewDecoder(body).Decode(&raws); err != nil {
+		return nil, nil, errors.New("error: json must be an array of items")
+	}
+	if len(raws) > maxImportRows {
+		return nil, nil, fmt.Errorf("error: an import must not have more than %d rows", maxImportRows)
+	}
+
+	rows := make([]*item.ItemImportRow, 0)
+	rowErrors := make(map[int]error)
+	for _, raw := range raws {
+		row := new(item.ItemImportRow)
+		rows = append(rows, row)
+
+		if err := json.Unmarshal(raw, row); err != nil {
+			rowErrors[len(rows)] = errors.New("error: item is invalid")
+		}
+	}
+
+	return rows, rowErrors, nil
+}
+
+// Note that: the export has every item, the disabled ones too, so it can be imported back as it is
+func (u *itemUsecase) ExportItems(pctx context.Context, format string, w io.Writer) error {
+	if format != ImportFormatCsv && format != ImportFormatJson {
+		return errors.New("error: format must be csv or json")
+	}
+
+	results, err := u.itemRepository.FindAllItems(pctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]*item.ItemImportRow, 0)
+	for _, v := range results {
+		usageStatus := v.UsageStatus
+		rows = append(rows, &item.ItemImportRow{
+			Title:       v.Title,
+			Price:       v.Price,
+			Damage:      v.Damage,
+			ImageUrl:    v.ImageUrl,
+			Category:    item.NormalizeCategory(v.Category),
+			Rarity:      item.NormalizeRarity(v.Rarity),
+			Attributes:  v.Attributes,
+			UsageStatus: &usageStatus,
+		})
+	}
+
+	if format == ImportFormatJson {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(rows)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(importCsvColumns)
+	for _, row := range rows {
+		attributes := ""
+		if len(row.Attributes) > 0 {
+			body, _ := json.Marshal(row.Attributes)
+			attributes = string(body)
+		}
+
+		writer.Write([]string{
+			row.Title,
+			strconv.FormatFloat(row.Price, 'f', -1, 64),
+			strconv.Itoa(row.Damage),
+			row.ImageUrl,
+			row.Category,
+			row.Rarity,
+			attributes,
+			strconv.FormatBool(*row.UsageStatus),
+		})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+var (
+	itemImageExtensions = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+		"image/gif":  ".gif",
+	}
+
+	// Note that: the sizes of the thumbnails in pixel, a thumbnail fits in a square of the size
+	itemThumbnailSizes = []int{128, 512}
+)
+
+// Note that: the old image is kept in the blob store, the revisions of the item still point at it
+func (u *itemUsecase) UploadItemImage(pctx context.Context, cfg *config.Config, playerId, itemId string, body io.Reader) (*item.ItemShowCase, error) {
+	content, err := io.ReadAll(io.LimitReader(body, cfg.Blob.MaxItemImageSize+1))
+	if err != nil {
+		log.Printf("Error: UploadItemImage: %s", err.Error())
+		return nil, errors.New("error: read image failed")
+	}
+	if len(content) == 0 {
+		return nil, errors.New("error: image is required")
+	}
+	if int64(len(content)) > cfg.Blob.MaxItemImageSize {
+		return nil, fmt.Errorf("error: image must not be larger than %d bytes", cfg.Blob.MaxItemImageSize)
+	}
+
+	contentType := http.DetectContentType(content)
+	extension, ok := itemImageExtensions[contentType]
+	if !ok {
+		return nil, errors.New("error: image must be a png, jpeg or gif image")
+	}
+
+	img, err := thumbnail.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := u.itemRepository.FindOneItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that: a new name for every upload, so a cached old image is never served as the new one
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, errors.New("error: generate image name failed")
+	}
+	name := fmt.Sprintf("items/%s/%s", current.Id.Hex(), hex.EncodeToString(suffix))
+
+	image := &item.ItemImage{
+		Key:         name + extension,
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Thumbnails:  make([]*item.ItemThumbnail, 0),
+	}
+
+	// The uploaded keys are removed again when a later step fails
+	uploaded := make([]string, 0)
+	cleanup := func() {
+		for _, key := range uploaded {
+			u.itemRepository.DeleteOneBlob(pctx, cfg, key)
+		}
+	}
+
+	imageUrl, err := u.itemRepository.PutOneBlob(pctx, cfg, image.Key, contentType, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	uploaded = append(uploaded, image.Key)
+
+	for _, size := range itemThumbnailSizes {
+		thumb, err := thumbnail.Generate(img, size)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s_%d.png", name, size)
+		url, err := u.itemRepository.PutOneBlob(pctx, cfg, key, "image/png", bytes.NewReader(thumb))
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		uploaded = append(uploaded, key)
+
+		image.Thumbnails = append(image.Thumbnails, &item.ItemThumbnail{Size: size, Key: key, Url: url})
+	}
+
+	if _, err := u.updateItem(pctx, current, bson.M{
+		"image_url": imageUrl,
+		"image":     image,
+	}, &item.ItemRevision{Action: item.RevisionActionImage, PlayerId: playerId}); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return u.FindOneItem(pctx, itemId)
+}
+
+var itemRevisionSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+}
+
+func (u *itemUsecase) FindItemRevisions(pctx context.Context, cfg *config.Config, itemId string, req *item.ItemRevisionSearchReq) (*models.PaginateRes, error) {
+	// The history of an item is read from the newest revision unless the direction is set
+	if req.Direction == "" {
+		req.Direction = "desc"
+	}
+
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "item_revisions:"+itemId, &req.PaginateReq, itemRevisionSorts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter
+	filter := bson.D{}
+	filter = append(filter, bson.E{"item_id", "item:" + itemId})
+
+	countFilter := append(bson.D{}, filter...)
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
+	}
+
+	// Find
+	results, err := u.itemRepository.FindManyItemRevisions(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, prev := models.Page(paginate, results, func(v *item.ItemRevision) (any, primitive.ObjectID) {
+		return v.Id, v.Id
+	})
+
+	// Count
+	total, err := u.itemRepository.CountItemRevisions(pctx, countFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate.NewPaginateRes(results, total, fmt.Sprintf("%s/item:%s/revisions", cfg.Paginate.ItemNextPageBasedUrl, itemId), paginate.Query(), next, prev), nil
+}
+
+// Note that: a revert is a new revision with the item as it was after the reverted revision, the history is never rewritten
+func (u *itemUsecase) RevertItem(pctx context.Context, playerId, itemId string, revision int) (*item.ItemShowCase, error) {
+	current, err := u.itemRepository.FindOneItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := u.itemRepository.FindOneItemRevision(pctx, "item:"+current.Id.Hex(), revision)
+	if err != nil {
+		return nil, err
+	}
+	if target.Revision == current.Revision {
+		return nil, errors.New("error: item is already at this revision")
+	}
+
+	snapshot := target.After
+	if snapshot.Title != current.Title && !u.itemRepository.IsUniqueItem(pctx, snapshot.Title) {
+		return nil, errors.New("error: this title is already exist")
+	}
+
+	// The schema of the category may have changed since the revision was written
+	schema, err := item.FindCategorySchema(item.NormalizeCategory(snapshot.Category))
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDamage(schema, snapshot.Damage); err != nil {
+		return nil, err
+	}
+	attributes, err := schema.ValidateAttributes(snapshot.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.updateItem(pctx, current, bson.M{
+		"title":        snapshot.Title,
+		"price":        snapshot.Price,
+		"damage":       snapshot.Damage,
+		"image_url":    snapshot.ImageUrl,
+		"image":        snapshot.Image,
+		"category":     schema.Category,
+		"rarity":       item.NormalizeRarity(snapshot.Rarity),
+		"attributes":   attributes,
+		"usage_status": snapshot.UsageStatus,
+	}, &item.ItemRevision{Action: item.RevisionActionRevert, PlayerId: playerId, RevertedFrom: target.Revision}); err != nil {
+		return nil, err
+	}
+
+	return u.FindOneItem(pctx, itemId)
+}
+
+// resolvePrices sets the effective price of the items, the campaigns and the schedules are read once for all of them
+func (u *itemUsecase) resolvePrices(pctx context.Context, results []*item.ItemShowCase) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	now := utils.LocalTime()
+
+	campaigns, err := u.itemRepository.FindManyItemCampaigns(pctx, bson.D{
+		{"start_at", bson.D{{"$lte", now}}},
+		{"end_at", bson.D{{"$gt", now}}},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	itemIds := make([]string, 0)
+	for _, v := range results {
+		itemIds = append(itemIds, v.ItemId)
+	}
+	schedules, err := u.itemRepository.FindManyItemPriceSchedules(pctx, bson.D{
+		{"item_id", bson.D{{"$in", itemIds}}},
+		{"start_at", bson.D{{"$lte", now}}},
+		{"superseded_at", bson.D{{"$exists", false}}},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range results {
+		price := item.ResolvePrice(v.ItemId, v.Category, v.Price, schedules, campaigns, now)
+
+		v.Price = price.Price
+		v.BasePrice = 0
+		v.Sale = nil
+		if price.Campaign != nil {
+			v.BasePrice = price.BasePrice
+			v.Sale = &item.ItemSale{
+				CampaignId: "campaign:" + price.Campaign.Id.Hex(),
+				Title:      price.Campaign.Title,
+				EndAt:      price.Campaign.EndAt,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *itemUsecase) CreateItemCampaign(pctx context.Context, playerId string, req *item.CreateItemCampaignReq) (*item.ItemCampaign, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > 64 {
+		return nil, errors.New("error: title is required and must not be longer than 64 characters")
+	}
+
+	switch req.DiscountType {
+	case item.DiscountPercent:
+		if req.DiscountValue <= 0 || req.DiscountValue > 100 {
+			return nil, errors.New("error: discount_value of a percent discount must be between 1 and 100")
+		}
+	case item.DiscountFixed:
+		if req.DiscountValue <= 0 || math.IsInf(req.DiscountValue, 0) {
+			return nil, errors.New("error: discount_value of a fixed discount must be more than 0")
+		}
+	default:
+		return nil, errors.New("error: discount_type must be percent or fixed")
+	}
+
+	category := ""
+	if req.Category != "" {
+		schema, err := item.FindCategorySchema(req.Category)
+		if err != nil {
+			return nil, err
+		}
+		category = schema.Category
+	}
+
+	if len(req.ItemIds) > 500 {
+		return nil, errors.New("error: a campaign must not have more than 500 items")
+	}
+	itemIds := make([]string, 0)
+	for _, v := range req.ItemIds {
+		itemId := strings.TrimPrefix(v, "item:")
+		if _, err := primitive.ObjectIDFromHex(itemId); err != nil {
+			return nil, fmt.Errorf("error: item_id %s is invalid", v)
+		}
+		itemIds = append(itemIds, "item:"+itemId)
+	}
+	if category == "" && len(itemIds) == 0 {
+		return nil, errors.New("error: item_ids or category is required")
+	}
+
+	if !req.EndAt.After(req.StartAt) {
+		return nil, errors.New("error: end_at must be after start_at")
+	}
+	if !req.EndAt.After(utils.LocalTime()) {
+		return nil, errors.New("error: end_at must be in the future")
+	}
+
+	result := &item.ItemCampaign{
+		Title:         title,
+		DiscountType:  req.DiscountType,
+		DiscountValue: req.DiscountValue,
+		ItemIds:       itemIds,
+		Category:      category,
+		StartAt:       req.StartAt,
+		EndAt:         req.EndAt,
+		PlayerId:      playerId,
+		CreatedAt:     utils.LocalTime(),
+	}
+
+	campaignId, err := u.itemRepository.InsertOneItemCampaign(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = campaignId
+
+	// A campaign that starts later is found by CheckItemPrices
+	if !result.StartAt.After(utils.LocalTime()) {
+		u.watchCampaignPrices(pctx, []*item.ItemCampaign{result})
+	}
+
+	return result, nil
+}
+
+var itemCampaignSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+	{Name: "start_at", Field: "start_at", Kind: models.SortKindTime},
+}
+
+func (u *itemUsecase) FindItemCampaigns(pctx context.Context, cfg *config.Config, req *item.ItemCampaignSearchReq) (*models.PaginateRes, error) {
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "item_campaigns:"+req.Status, &req.PaginateReq, itemCampaignSorts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := paginate.Query()
+
+	// Filter
+	filter := bson.D{}
+
+	now := utils.LocalTime()
+	switch req.Status {
+	case "":
+	case "active":
+		filter = append(filter, bson.E{"start_at", bson.D{{"$lte", now}}}, bson.E{"end_at", bson.D{{"$gt", now}}})
+	case "scheduled":
+		filter = append(filter, bson.E{"start_at", bson.D{{"$gt", now}}})
+	case "ended":
+		filter = append(filter, bson.E{"end_at", bson.D{{"$lte", now}}})
+	default:
+		return nil, errors.New("error: status must be active, scheduled or ended")
+	}
+	if req.Status != "" {
+		query.Set("status", req.Status)
+	}
+
+	countFilter := append(bson.D{}, filter...)
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
+	}
+
+	// Find
+	results, err := u.itemRepository.FindManyItemCampaigns(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, prev := models.Page(paginate, results, func(v *item.ItemCampaign) (any, primitive.ObjectID) {
+		if paginate.Sort.Name == "start_at" {
+			return v.StartAt, v.Id
+		}
+		return v.Id, v.Id
+	})
+
+	// Count
+	total, err := u.itemRepository.CountItemCampaigns(pctx, countFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate.NewPaginateRes(results, total, cfg.Paginate.ItemNextPageBasedUrl+"/campaigns", query, next, prev), nil
+}
+
+// Note that: a campaign that has not started ends at its start, so it is never active
+func (u *itemUsecase) EndItemCampaign(pctx context.Context, campaignId string) (*item.ItemCampaign, error) {
+	result, err := u.itemRepository.FindOneItemCampaign(pctx, campaignId)
+	if err != nil {
+		return nil, err
+	}
+
+	endAt := utils.LocalTime()
+	if result.StartAt.After(endAt) {
+		endAt = result.StartAt
+	}
+
+	if err := u.itemRepository.UpdateOneItemCampaignEndAt(pctx, campaignId, endAt); err != nil {
+		return nil, err
+	}
+	result.EndAt = endAt
+
+	u.watchCampaignPrices(pctx, []*item.ItemCampaign{result})
+
+	return result, nil
+}
+
+func (u *itemUsecase) CreateItemPriceSchedule(pctx context.Context, playerId, itemId string, req *item.CreateItemPriceScheduleReq) (*item.ItemPriceSchedule, error) {
+	if req.Price < 0 || math.IsNaN(req.Price) || math.IsInf(req.Price, 0) {
+		return nil, errors.New("error: price must not be negative")
+	}
+	if !req.StartAt.After(utils.LocalTime()) {
+		return nil, errors.New("error: start_at must be in the future")
+	}
+
+	current, err := u.itemRepository.FindOneItem(pctx, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &item.ItemPriceSchedule{
+		ItemId:    "item:" + current.Id.Hex(),
+		Price:     req.Price,
+		StartAt:   req.StartAt,
+		PlayerId:  playerId,
+		CreatedAt: utils.LocalTime(),
+	}
+
+	scheduleId, err := u.itemRepository.InsertOneItemPriceSchedule(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = scheduleId
+
+	return result, nil
+}
+
+func (u *itemUsecase) FindItemPriceSchedules(pctx context.Context, itemId string) ([]*item.ItemPriceSchedule, error) {
+	return u.itemRepository.FindManyItemPriceSchedules(
+		pctx,
+		bson.D{{"item_id", "item:" + itemId}},
+		[]*options.FindOptions{options.Find().SetSort(bson.D{{"start_at", -1}}).SetLimit(100)},
+	)
+}
+
+func (u *itemUsecase) CancelItemPriceSchedule(pctx context.Context, itemId, scheduleId string) error {
+	return u.itemRepository.DeleteOneItemPriceSchedule(pctx, "item:"+itemId, scheduleId, utils.LocalTime())
+}
+
+// watchPrices compares the effective prices of the enabled items with the prices that were watched, a lower price
+// is a price_drop alert and an item that is enabled again is a restock alert. A failed alert does not fail the change
+// of the item, it is logged
+func (u *itemUsecase) watchPrices(pctx context.Context, itemIds []string, isRestock bool) {
+	if len(itemIds) == 0 {
+		return
+	}
+
+	objectIds := make([]primitive.ObjectID, 0)
+	for _, itemId := range itemIds {
+		objectIds = append(objectIds, utils.ConvertToObjectId(strings.TrimPrefix(itemId, "item:")))
+	}
+
+	results, err := u.itemRepository.FindManyItems(pctx, bson.D{
+		{"_id", bson.D{{"$in", objectIds}}},
+		{"usage_status", true},
+	}, nil)
+	if err != nil {
+		log.Printf("Error: watchPrices failed: %s", err.Error())
+		return
+	}
+	if err := u.resolvePrices(pctx, results); err != nil {
+		log.Printf("Error: watchPrices failed: %s", err.Error())
+		return
+	}
+	u.saveEffectivePrices(pctx, results)
+
+	now := utils.LocalTime()
+	for _, v := range results {
+		previous, ok, err := u.itemRepository.SwapItemPriceWatch(pctx, v.ItemId, v.Price, now)
+		if err != nil {
+			continue
+		}
+
+		alert := &item.ItemAlert{
+			ItemId:    v.ItemId,
+			Title:     v.Title,
+			Price:     v.Price,
+			CreatedAt: now,
+		}
+		if ok && v.Price < previous {
+			alert.Type = item.AlertPriceDrop
+			alert.PreviousPrice = previous
+		}
+		if isRestock {
+			alert.Type = item.AlertRestock
+		}
+		if alert.Type == "" {
+			continue
+		}
+
+		if err := u.itemRepository.InsertOneItemAlert(pctx, alert); err != nil {
+			log.Printf("Error: watchPrices failed: %s alert of %s is lost", alert.Type, alert.ItemId)
+		}
+	}
+}
+
+// saveEffectivePrices writes the resolved prices that are not the stored ones, so the lists filter and sort by them
+func (u *itemUsecase) saveEffectivePrices(pctx context.Context, results []*item.ItemShowCase) {
+	for _, v := range results {
+		if v.Price == v.EffectivePrice {
+			continue
+		}
+		if err := u.itemRepository.UpdateOneItemEffectivePrice(pctx, v.ItemId, v.Price); err != nil {
+			log.Printf("Error: saveEffectivePrices failed: effective price of %s is not saved", v.ItemId)
+			continue
+		}
+		v.EffectivePrice = v.Price
+	}
+}
+
+// RefreshEffectivePrices resolves and writes the effective price of every enabled item, it sends no alert
+func (u *itemUsecase) RefreshEffectivePrices(pctx context.Context) error {
+	results, err := u.itemRepository.FindManyItems(pctx, bson.D{{"usage_status", true}}, nil)
+	if err != nil {
+		return err
+	}
+	if err := u.resolvePrices(pctx, results); err != nil {
+		return err
+	}
+	u.saveEffectivePrices(pctx, results)
+
+	return nil
+}
+
+// The items of a campaign with a category are every item of the category
+func (u *itemUsecase) watchCampaignPrices(pctx context.Context, campaigns []*item.ItemCampaign) {
+	setIds := make(map[string]bool)
+	categories := make([]string, 0)
+	for _, c := range campaigns {
+		for _, itemId := range c.ItemIds {
+			setIds[itemId] = true
+		}
+		if c.Category != "" {
+			categories = append(categories, c.Category)
+		}
+	}
+
+	for _, category := range categories {
+		results, err := u.itemRepository.FindManyItems(pctx, bson.D{categoryFilter(category), {"usage_status", true}}, nil)
+		if err != nil {
+			log.Printf("Error: watchCampaignPrices failed: %s", err.Error())
+			continue
+		}
+		for _, v := range results {
+			setIds[v.ItemId] = true
+		}
+	}
+
+	itemIds := make([]string, 0)
+	for k := range setIds {
+		itemIds = append(itemIds, k)
+	}
+	u.watchPrices(pctx, itemIds, false)
+}
+
+// CheckItemPrices watches the prices of the items whose campaigns or schedules have started or ended since the last check,
+// the other changes of the prices are watched when they are made
+func (u *itemUsecase) CheckItemPrices(pctx context.Context) error {
+	now := utils.LocalTime()
+
+	since, err := u.itemRepository.GetItemPriceCheckedAt(pctx)
+	if err != nil {
+		return err
+	}
+
+	if !since.IsZero() {
+		between := bson.D{{"$gt", since}, {"$lte", now}}
+
+		campaigns, err := u.itemRepository.FindManyItemCampaigns(pctx, bson.D{
+			{"$or", bson.A{
+				bson.D{{"start_at", between}},
+				bson.D{{"end_at", between}},
+			}},
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		schedules, err := u.itemRepository.FindManyItemPriceSchedules(pctx, bson.D{
+			{"start_at", between},
+			{"superseded_at", bson.D{{"$exists", false}}},
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		// A schedule is a campaign of one item for the watch
+		for _, v := range schedules {
+			campaigns = append(campaigns, &item.ItemCampaign{ItemIds: []string{v.ItemId}})
+		}
+		u.watchCampaignPrices(pctx, campaigns)
+	}
+
+	return u.itemRepository.UpsertItemPriceCheckedAt(pctx, now)
+}
+
+// PublishItemAlerts pushes the alerts that are not published yet in the order they were written,
+// an alert is pushed again when it is not marked as published, so the consumer must skip the alerts that it has seen
+func (u *itemUsecase) PublishItemAlerts(pctx context.Context, cfg *config.Config) error {
+	results, err := u.itemRepository.FindManyItemAlerts(
+		pctx,
+		bson.D{{"published_at", bson.D{{"$exists", false}}}},
+		[]*options.FindOptions{options.Find().SetSort(bson.D{{"_id", 1}}).SetLimit(100)},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range results {
+		if err := u.itemRepository.PushItemAlert(pctx, cfg, &item.ItemAlertEvent{
+			AlertId:       "item_alert:" + v.Id.Hex(),
+			Type:          v.Type,
+			ItemId:        v.ItemId,
+			Title:         v.Title,
+			Price:         v.Price,
+			PreviousPrice: v.PreviousPrice,
+			CreatedAt:     v.CreatedAt,
+		}); err != nil {
+			return err
+		}
+
+		if err := u.itemRepository.UpdateOneItemAlertPublished(pctx, v.Id, utils.LocalTime()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
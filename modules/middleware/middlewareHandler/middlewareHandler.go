@@ -13,8 +13,9 @@ import (
 type (
 	MiddlewareHandlerService interface {
 		JwtAuthorization(next echo.HandlerFunc) echo.HandlerFunc
-		RbacAuthorization(next echo.HandlerFunc, expected []int) echo.HandlerFunc
+		PermissionAuthorization(next echo.HandlerFunc, permission string) echo.HandlerFunc
 		PlayerIdParamValidation(next echo.HandlerFunc) echo.HandlerFunc
+		PlayerIdParamOrPermissionAuthorization(next echo.HandlerFunc, permission string) echo.HandlerFunc
 	}
 
 	middlewareHandler struct {
@@ -43,11 +44,11 @@ func (h *middlewareHandler) JwtAuthorization(next echo.HandlerFunc) echo.Handler
 	}
 }
 
-func (h *middlewareHandler) RbacAuthorization(next echo.HandlerFunc, expected []int) echo.HandlerFunc {
+func (h *middlewareHandler) PermissionAuthorization(next echo.HandlerFunc, permission string) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		newCtx, err := h.middlewareUsecase.RbacAuthorization(c, h.cfg, expected)
+		newCtx, err := h.middlewareUsecase.PermissionAuthorization(c, h.cfg, permission)
 		if err != nil {
-			return response.ErrResponse(c, http.StatusUnauthorized, err.Error())
+			return response.ErrResponse(c, http.StatusForbidden, err.Error())
 		}
 
 		return next(newCtx)
@@ -64,3 +65,19 @@ func (h *middlewareHandler) PlayerIdParamValidation(next echo.HandlerFunc) echo.
 		return next(newCtx)
 	}
 }
+
+// PlayerIdParamOrPermissionAuthorization lets the player of the player_id param in, any other player needs the permission
+func (h *middlewareHandler) PlayerIdParamOrPermissionAuthorization(next echo.HandlerFunc, permission string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if h.middlewareUsecase.IsPlayerIdParamOwner(c) {
+			return next(c)
+		}
+
+		newCtx, err := h.middlewareUsecase.PermissionAuthorization(c, h.cfg, permission)
+		if err != nil {
+			return response.ErrResponse(c, http.StatusForbidden, err.Error())
+		}
+
+		return next(newCtx)
+	}
+}
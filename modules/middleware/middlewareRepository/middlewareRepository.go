@@ -14,7 +14,7 @@ import (
 type (
 	MiddlewareRepositoryService interface {
 		AccessTokenSearch(pctx context.Context, grpcUrl, accessToken string) error
-		RolesCount(pctx context.Context, grpcUrl string) (int64, error)
+		CheckPermission(pctx context.Context, grpcUrl string, roleCode int, permission string) error
 	}
 
 	middlewareRepository struct{}
@@ -48,6 +48,11 @@ func (r *middlewareRepository) AccessTokenSearch(pctx context.Context, grpcUrl,
 		return errors.New("error: access token is invalid")
 	}
 
+	if result.IsBanned {
+		log.Printf("Error: player of the access token is banned")
+		return errors.New("error: player is banned")
+	}
+
 	if !result.IsValid {
 		log.Printf("Error: access token is invalid")
 		return errors.New("error: access token is invalid")
@@ -56,27 +61,30 @@ func (r *middlewareRepository) AccessTokenSearch(pctx context.Context, grpcUrl,
 	return nil
 }
 
-func (r *middlewareRepository) RolesCount(pctx context.Context, grpcUrl string) (int64, error) {
+func (r *middlewareRepository) CheckPermission(pctx context.Context, grpcUrl string, roleCode int, permission string) error {
 	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
 	defer cancel()
 
 	conn, err := grpccon.NewGrpcClient(grpcUrl)
 	if err != nil {
 		log.Printf("Error: gRPC connection failed: %s", err.Error())
-		return -1, errors.New("error: gRPC connection failed")
+		return errors.New("error: gRPC connection failed")
 	}
 
 	jwtauth.SetApiKeyInContext(&ctx)
-	result, err := conn.Auth().RolesCount(ctx, &authPb.RolesCountReq{})
+	result, err := conn.Auth().CheckPermission(ctx, &authPb.CheckPermissionReq{
+		RoleCode:   int64(roleCode),
+		Permission: permission,
+	})
 	if err != nil {
-		log.Printf("Error: CredentialSearch failed: %s", err.Error())
-		return -1, errors.New("error: email or password is incorrect")
+		log.Printf("Error: CheckPermission failed: %s", err.Error())
+		return errors.New("error: check permission failed")
 	}
 
-	if result == nil {
-		log.Printf("Error: roles count failed")
-		return -1, errors.New("error: roles count failed")
+	if result == nil || !result.IsAllowed {
+		log.Printf("Error: permission %s denied for role_code %d", permission, roleCode)
+		return errors.New("error: permission denied")
 	}
 
-	return result.Count, nil
+	return nil
 }
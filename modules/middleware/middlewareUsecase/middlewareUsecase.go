@@ -3,19 +3,20 @@ package middlewareUsecase
 import (
 	"errors"
 	"log"
+	"strings"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/middleware/middlewareRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
-	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
 	"github.com/labstack/echo/v4"
 )
 
 type (
 	MiddlewareUsecaseService interface {
 		JwtAuthorization(c echo.Context, cfg *config.Config, accessToken string) (echo.Context, error)
-		RbacAuthorization(c echo.Context, cfg *config.Config, expected []int) (echo.Context, error)
+		PermissionAuthorization(c echo.Context, cfg *config.Config, permission string) (echo.Context, error)
 		PlayerIdParamValidation(c echo.Context) (echo.Context, error)
+		IsPlayerIdParamOwner(c echo.Context) bool
 	}
 
 	middlewareUsecase struct {
@@ -41,29 +42,33 @@ func (u *middlewareUsecase) JwtAuthorization(c echo.Context, cfg *config.Config,
 
 	c.Set("player_id", claims.PlayerId)
 	c.Set("role_code", claims.RoleCode)
+	c.Set("mfa", claims.Mfa)
 
 	return c, nil
 }
 
-func (u *middlewareUsecase) RbacAuthorization(c echo.Context, cfg *config.Config, expected []int) (echo.Context, error) {
+func (u *middlewareUsecase) PermissionAuthorization(c echo.Context, cfg *config.Config, permission string) (echo.Context, error) {
 	ctx := c.Request().Context()
 
-	playerRoleCode := c.Get("role_code").(int)
+	playerRoleCode, ok := c.Get("role_code").(int)
+	if !ok {
+		log.Printf("Error: role_code not found")
+		return nil, errors.New("error: permission denied")
+	}
 
-	rolesCount, err := u.middlewareRepository.RolesCount(ctx, cfg.Grpc.AuthUrl)
-	if err != nil {
+	if err := u.middlewareRepository.CheckPermission(ctx, cfg.Grpc.AuthUrl, playerRoleCode, permission); err != nil {
 		return nil, err
 	}
 
-	playerRoleBinary := rbac.IntToBinary(playerRoleCode, int(rolesCount))
-
-	for i := 0; i < int(rolesCount); i++ {
-		if playerRoleBinary[i]&expected[i] == 1 {
-			return c, nil
+	// Note that: every permission belongs to an admin role, the base role has none
+	if cfg.TwoFactor.RequireForAdmin {
+		if mfa, _ := c.Get("mfa").(bool); !mfa {
+			log.Printf("Error: two-factor authentication is required for permission: %s", permission)
+			return nil, errors.New("error: two-factor authentication is required")
 		}
 	}
 
-	return nil, errors.New("error: permission denied")
+	return c, nil
 }
 
 func (u *middlewareUsecase) PlayerIdParamValidation(c echo.Context) (echo.Context, error) {
@@ -82,3 +87,13 @@ func (u *middlewareUsecase) PlayerIdParamValidation(c echo.Context) (echo.Contex
 
 	return c, nil
 }
+
+// Note that: the player_id param may come with or without the "player:" prefix
+func (u *middlewareUsecase) IsPlayerIdParamOwner(c echo.Context) bool {
+	playerIdToken, _ := c.Get("player_id").(string)
+	if playerIdToken == "" {
+		return false
+	}
+
+	return strings.TrimPrefix(c.Param("player_id"), "player:") == strings.TrimPrefix(playerIdToken, "player:")
+}
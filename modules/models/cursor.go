@@ -0,0 +1,323 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Sort key kinds
+const (
+	SortKindString = "string"
+	SortKindNumber = "number"
+	SortKindTime   = "time"
+	SortKindId     = "id"
+)
+
+type (
+	// SortField is a sort that a list allows, Name is the value of the sort query param and Field is the bson field
+	SortField struct {
+		Name  string
+		Field string
+		Kind  string
+	}
+
+	// Note that: the cursor is signed so a client can not build one that skips the filter of the list
+	cursorPayload struct {
+		Scope     string `json:"sc"`
+		Sort      string `json:"s"`
+		Direction string `json:"d"`
+		Prev      bool   `json:"p,omitempty"`
+		Key       string `json:"k"`
+		Id        string `json:"i"`
+	}
+
+	// PaginateQuery is a parsed PaginateReq, it builds the mongo filter and options of one page
+	PaginateQuery struct {
+		Sort      *SortField
+		Direction string
+		Limit     int
+		secret    []byte
+		scope     string
+		cursor    *cursorPayload
+	}
+)
+
+// ParsePaginate checks the sort, the direction and the limit of the request and verifies its cursor,
+// the first sort is the default one and the scope binds the cursor to one list
+func ParsePaginate(secret, scope string, req *PaginateReq, sorts []*SortField) (*PaginateQuery, error) {
+	if secret == "" {
+		return nil, errors.New("error: paginate cursor secret is required")
+	}
+
+	q := &PaginateQuery{
+		Sort:      sorts[0],
+		Direction: "asc",
+		Limit:     req.Limit,
+		secret:    []byte(secret),
+		scope:     scope,
+	}
+
+	if req.Sort != "" {
+		q.Sort = nil
+		names := make([]string, 0)
+		for _, s := range sorts {
+			names = append(names, s.Name)
+			if s.Name == req.Sort {
+				q.Sort = s
+			}
+		}
+		if q.Sort == nil {
+			return nil, fmt.Errorf("error: sort must be one of %s", strings.Join(names, ", "))
+		}
+	}
+
+	switch req.Direction {
+	case "", "asc":
+	case "desc":
+		q.Direction = "desc"
+	default:
+		return nil, errors.New("error: direction must be asc or desc")
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	if q.Limit > 100 {
+		q.Limit = 100
+	}
+
+	if req.Start != "" {
+		cursor, err := q.decode(req.Start)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Scope != q.scope || cursor.Sort != q.Sort.Name || cursor.Direction != q.Direction {
+			return nil, errors.New("error: start does not match the sort of the list")
+		}
+		q.cursor = cursor
+	}
+
+	return q, nil
+}
+
+// Filter returns the condition that starts the page after (or before) the cursor, it is empty on the first page
+func (q *PaginateQuery) Filter() (bson.E, bool, error) {
+	if q.cursor == nil {
+		return bson.E{}, false, nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(q.cursor.Id)
+	if err != nil {
+		return bson.E{}, false, errors.New("error: start is invalid")
+	}
+
+	op := "$gt"
+	if (q.Direction == "desc") != q.cursor.Prev {
+		op = "$lt"
+	}
+
+	if q.Sort.Kind == SortKindId {
+		return bson.E{"_id", bson.D{{op, id}}}, true, nil
+	}
+
+	key, err := q.decodeKey(q.cursor.Key)
+	if err != nil {
+		return bson.E{}, false, err
+	}
+
+	// The _id is the tie-breaker of the same sort key, it is always in the same direction as the sort
+	return bson.E{"$or", bson.A{
+		bson.D{{q.Sort.Field, bson.D{{op, key}}}},
+		bson.D{{q.Sort.Field, key}, {"_id", bson.D{{op, id}}}},
+	}}, true, nil
+}
+
+// FindOptions sorts by the sort key and the _id, one more document than the limit is read to know if there is a next page
+func (q *PaginateQuery) FindOptions() []*options.FindOptions {
+	order := 1
+	if (q.Direction == "desc") != (q.cursor != nil && q.cursor.Prev) {
+		order = -1
+	}
+
+	sort := bson.D{}
+	if q.Sort.Kind != SortKindId {
+		sort = append(sort, bson.E{q.Sort.Field, order})
+	}
+	sort = append(sort, bson.E{"_id", order})
+
+	return []*options.FindOptions{
+		options.Find().SetSort(sort),
+		options.Find().SetLimit(int64(q.Limit + 1)),
+	}
+}
+
+// Query returns the query params of the list, the filters of the list are added by the caller
+func (q *PaginateQuery) Query() url.Values {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(q.Limit))
+	query.Set("sort", q.Sort.Name)
+	query.Set("direction", q.Direction)
+	return query
+}
+
+// Page trims the documents that were read with FindOptions to one page in the order of the sort and returns it with
+// the next and the previous cursors, key gives the sort key and the _id of a document
+func Page[T any](q *PaginateQuery, results []T, key func(T) (any, primitive.ObjectID)) ([]T, string, string) {
+	isPrev := q.cursor != nil && q.cursor.Prev
+
+	hasMore := len(results) > q.Limit
+	if hasMore {
+		results = results[:q.Limit]
+	}
+	if isPrev {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	if len(results) == 0 {
+		return results, "", ""
+	}
+
+	next, prev := "", ""
+	if hasMore || isPrev {
+		k, id := key(results[len(results)-1])
+		next = q.encode(k, id, false)
+	}
+	if (q.cursor != nil && !isPrev) || (isPrev && hasMore) {
+		k, id := key(results[0])
+		prev = q.encode(k, id, true)
+	}
+	return results, next, prev
+}
+
+// NewPaginateRes builds the response with the links of the first, the next and the previous pages
+func (q *PaginateQuery) NewPaginateRes(data any, total int64, baseUrl string, query url.Values, next, prev string) *PaginateRes {
+	href := func(start string) string {
+		values := url.Values{}
+		for k, v := range query {
+			values[k] = v
+		}
+		if start != "" {
+			values.Set("start", start)
+		}
+		return fmt.Sprintf("%s?%s", baseUrl, values.Encode())
+	}
+
+	res := &PaginateRes{
+		Data:  data,
+		Total: total,
+		Limit: q.Limit,
+		First: FirstPaginate{
+			Href: href(""),
+		},
+		Next: NextPaginate{
+			Start: "",
+			Href:  "",
+		},
+		Prev: PrevPaginate{
+			Start: "",
+			Href:  "",
+		},
+	}
+	if next != "" {
+		res.Next = NextPaginate{Start: next, Href: href(next)}
+	}
+	if prev != "" {
+		res.Prev = PrevPaginate{Start: prev, Href: href(prev)}
+	}
+	return res
+}
+
+func (q *PaginateQuery) encode(key any, id primitive.ObjectID, isPrev bool) string {
+	payload := &cursorPayload{
+		Scope:     q.scope,
+		Sort:      q.Sort.Name,
+		Direction: q.Direction,
+		Prev:      isPrev,
+		Key:       encodeKey(key),
+		Id:        id.Hex(),
+	}
+
+	body, _ := json.Marshal(payload)
+
+	mac := hmac.New(sha256.New, q.secret)
+	mac.Write(body)
+
+	return base64.RawURLEncoding.EncodeToString(body) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (q *PaginateQuery) decode(cursor string) (*cursorPayload, error) {
+	bodyText, sigText, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, errors.New("error: start is invalid")
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(bodyText)
+	if err != nil {
+		return nil, errors.New("error: start is invalid")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigText)
+	if err != nil {
+		return nil, errors.New("error: start is invalid")
+	}
+
+	mac := hmac.New(sha256.New, q.secret)
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, errors.New("error: start is invalid")
+	}
+
+	payload := new(cursorPayload)
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, errors.New("error: start is invalid")
+	}
+	return payload, nil
+}
+
+func encodeKey(key any) string {
+	switch v := key.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case primitive.ObjectID:
+		return v.Hex()
+	}
+	return fmt.Sprint(key)
+}
+
+func (q *PaginateQuery) decodeKey(key string) (any, error) {
+	switch q.Sort.Kind {
+	case SortKindNumber:
+		v, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, errors.New("error: start is invalid")
+		}
+		return v, nil
+	case SortKindTime:
+		v, err := time.Parse(time.RFC3339Nano, key)
+		if err != nil {
+			return nil, errors.New("error: start is invalid")
+		}
+		return v, nil
+	}
+	return key, nil
+}
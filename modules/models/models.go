@@ -1,9 +1,12 @@
 package models
 
 type (
+	// Note that: start is the opaque cursor from next or prev of the last response
 	PaginateReq struct {
-		Start string `query:"start" validate:"max=64"`
-		Limit int    `query:"limit" validate:"required,min=2,max=10"`
+		Start     string `query:"start" validate:"max=512"`
+		Limit     int    `query:"limit" validate:"required,min=2,max=10"`
+		Sort      string `query:"sort" validate:"max=32"`
+		Direction string `query:"direction" validate:"omitempty,oneof=asc desc"`
 	}
 
 	PaginateRes struct {
@@ -12,6 +15,7 @@ type (
 		Total int64         `json:"total"`
 		First FirstPaginate `json:"first"`
 		Next  NextPaginate  `json:"next"`
+		Prev  PrevPaginate  `json:"prev"`
 	}
 
 	FirstPaginate struct {
@@ -23,6 +27,11 @@ type (
 		Href  string `json:"href"`
 	}
 
+	PrevPaginate struct {
+		Start string `json:"start"`
+		Href  string `json:"href"`
+	}
+
 	KafkaOffset struct {
 		Offset int64 `json:"offset" bson:"offset"`
 	}
@@ -0,0 +1,113 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Coupon benefits
+const (
+	BenefitPercent  = "percent"
+	BenefitFixed    = "fixed"
+	BenefitFreeItem = "free_item"
+)
+
+// Coupon redemption status
+const (
+	RedemptionReserved = "reserved"
+	RedemptionRedeemed = "redeemed"
+	RedemptionReleased = "released"
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// NormalizeCouponCode makes the code case insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func IsValidCouponCode(code string) bool {
+	return couponCodePattern.MatchString(code)
+}
+
+// Check checks the coupon against the items of the buy, the prices of the items must be filled already,
+// the limits of the uses are checked when the coupon is reserved
+func (c *Coupon) Check(now time.Time, items []*ItemServiceReqDatum) error {
+	if !c.IsActive {
+		return errors.New("error: coupon is not active")
+	}
+	if !now.Before(c.ExpiresAt) {
+		return errors.New("error: coupon has expired")
+	}
+	if spend := c.spend(items); spend < c.MinSpend {
+		return fmt.Errorf("error: coupon needs a minimum spend of %v", c.MinSpend)
+	}
+	return nil
+}
+
+// The spend does not count the item that the coupon gives, it is the last one of the items
+func (c *Coupon) spend(items []*ItemServiceReqDatum) float64 {
+	spend := 0.0
+	isFreeFound := false
+	for i := len(items) - 1; i >= 0; i-- {
+		if c.BenefitType == BenefitFreeItem && !isFreeFound && items[i].ItemId == c.ItemId {
+			isFreeFound = true
+			continue
+		}
+		spend += items[i].Price
+	}
+	return spend
+}
+
+// Apply takes the discount of the coupon off the prices of the items and returns the discount,
+// the discount of percent and fixed is shared by the items by their price
+func (c *Coupon) Apply(items []*ItemServiceReqDatum) float64 {
+	if c.BenefitType == BenefitFreeItem {
+		for i := len(items) - 1; i >= 0; i-- {
+			if items[i].ItemId == c.ItemId {
+				items[i].Discount = items[i].Price
+				items[i].Price = 0
+				return items[i].Discount
+			}
+		}
+		return 0
+	}
+
+	subtotal := 0.0
+	for _, v := range items {
+		subtotal += v.Price
+	}
+	if subtotal <= 0 {
+		return 0
+	}
+
+	discount := 0.0
+	switch c.BenefitType {
+	case BenefitPercent:
+		discount = roundMoney(subtotal * c.Value / 100)
+	case BenefitFixed:
+		discount = math.Min(c.Value, subtotal)
+	}
+
+	remaining := discount
+	for i, v := range items {
+		share := roundMoney(v.Price * discount / subtotal)
+		if i == len(items)-1 {
+			share = roundMoney(remaining)
+		}
+		share = math.Min(share, v.Price)
+
+		v.Price = roundMoney(v.Price - share)
+		v.Discount = share
+		remaining -= share
+	}
+	return discount
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,133 @@
+package payment
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Note that: uses counts the reserved and the redeemed uses, a released use is given back
+	Coupon struct {
+		Id               primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Code             string             `json:"code" bson:"code"`
+		BenefitType      string             `json:"benefit_type" bson:"benefit_type"`
+		Value            float64            `json:"value" bson:"value"`
+		ItemId           string             `json:"item_id,omitempty" bson:"item_id,omitempty"`
+		MinSpend         float64            `json:"min_spend" bson:"min_spend"`
+		MaxUses          int                `json:"max_uses" bson:"max_uses"`
+		MaxUsesPerPlayer int                `json:"max_uses_per_player" bson:"max_uses_per_player"`
+		Uses             int                `json:"uses" bson:"uses"`
+		ExpiresAt        time.Time          `json:"expires_at" bson:"expires_at"`
+		IsActive         bool               `json:"is_active" bson:"is_active"`
+		PlayerId         string             `json:"player_id" bson:"player_id"`
+		CreatedAt        time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt        time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	// CouponPlayerUse counts the uses of a coupon by one player, it is unique by the coupon and the player
+	CouponPlayerUse struct {
+		CouponId string `bson:"coupon_id"`
+		PlayerId string `bson:"player_id"`
+		Uses     int    `bson:"uses"`
+	}
+
+	// Note that: a redemption is reserved before the first step of the buy, it is redeemed when the buy completes
+	// and released when the buy is compensated
+	CouponRedemption struct {
+		Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		CouponId  string             `json:"coupon_id" bson:"coupon_id"`
+		Code      string             `json:"code" bson:"code"`
+		PlayerId  string             `json:"player_id" bson:"player_id"`
+		Status    string             `json:"status" bson:"status"`
+		Discount  float64            `json:"discount" bson:"discount"`
+		CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	// Note that: a player has one cart, version is checked on every save so two requests can not overwrite each other
+	// Note that: checking_out is the claim of the checkout that is running, a claim that is older than the checkout
+	// timeout is left by a checkout that has stopped and can be taken
+	Cart struct {
+		Id            primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId      string             `json:"player_id" bson:"player_id"`
+		Items         []*CartItem        `json:"items" bson:"items"`
+		Version       int                `json:"version" bson:"version"`
+		CheckingOut   string             `json:"-" bson:"checking_out,omitempty"`
+		CheckingOutAt *time.Time         `json:"-" bson:"checking_out_at,omitempty"`
+		UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	CartItem struct {
+		ItemId   string    `json:"item_id" bson:"item_id"`
+		Quantity int       `json:"quantity" bson:"quantity"`
+		AddedAt  time.Time `json:"added_at" bson:"added_at"`
+	}
+
+	// Note that: an order is the record of one buy, sell or refund saga, step is the last step that was started
+	// and steps is the progress of every step, transaction_id is the debit or the credit of the order,
+	// refund_id is the refund of a buy and refund_of is the buy of a refund, redemption_id is the coupon of a buy
+	Order struct {
+		Id            primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId      string             `json:"player_id" bson:"player_id"`
+		Type          string             `json:"type" bson:"type"`
+		Status        string             `json:"status" bson:"status"`
+		Step          string             `json:"step" bson:"step"`
+		Steps         []*OrderStep       `json:"steps" bson:"steps"`
+		Items         []*OrderItem       `json:"items" bson:"items"`
+		Total         float64            `json:"total" bson:"total"`
+		Code          string             `json:"code,omitempty" bson:"code,omitempty"`
+		RedemptionId  string             `json:"-" bson:"redemption_id,omitempty"`
+		TransactionId string             `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+		RefundId      string             `json:"refund_id,omitempty" bson:"refund_id,omitempty"`
+		RefundOf      string             `json:"refund_of,omitempty" bson:"refund_of,omitempty"`
+		RefundedBy    string             `json:"refunded_by,omitempty" bson:"refunded_by,omitempty"`
+		Reason        string             `json:"reason,omitempty" bson:"reason,omitempty"`
+		Error         string             `json:"error,omitempty" bson:"error,omitempty"`
+		CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	// Note that: is_consumed is an item of a refund that was not in the inventory anymore
+	OrderItem struct {
+		ItemId       string  `json:"item_id" bson:"item_id"`
+		ItemRevision int     `json:"item_revision,omitempty" bson:"item_revision,omitempty"`
+		Price        float64 `json:"price" bson:"price"`
+		Discount     float64 `json:"discount,omitempty" bson:"discount,omitempty"`
+		InventoryId  string  `json:"inventory_id,omitempty" bson:"inventory_id,omitempty"`
+		IsConsumed   bool    `json:"is_consumed,omitempty" bson:"is_consumed,omitempty"`
+	}
+
+	OrderStep struct {
+		Name   string    `json:"name" bson:"name"`
+		Status string    `json:"status" bson:"status"`
+		At     time.Time `json:"at" bson:"at"`
+	}
+
+	// Note that: a player has one risk rule, a limit of 0 uses the limit of the config,
+	// allow_new_account lets the player buy before the account is old enough
+	RiskRule struct {
+		Id                 primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId           string             `json:"player_id" bson:"player_id"`
+		DailySpendCap      float64            `json:"daily_spend_cap" bson:"daily_spend_cap"`
+		MaxOrderValue      float64            `json:"max_order_value" bson:"max_order_value"`
+		MaxOrdersPerMinute int64              `json:"max_orders_per_minute" bson:"max_orders_per_minute"`
+		AllowNewAccount    bool               `json:"allow_new_account" bson:"allow_new_account"`
+		UpdatedBy          string             `json:"updated_by" bson:"updated_by"`
+		UpdatedAt          time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	// Note that: a decision is kept for every buy that is checked, rule is the rule that rejected the buy
+	// and reason is why, both are empty when the buy is allowed
+	RiskDecision struct {
+		Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId  string             `json:"player_id" bson:"player_id"`
+		OrderId   string             `json:"order_id" bson:"order_id"`
+		Total     float64            `json:"total" bson:"total"`
+		IsAllowed bool               `json:"is_allowed" bson:"is_allowed"`
+		Rule      string             `json:"rule,omitempty" bson:"rule,omitempty"`
+		Reason    string             `json:"reason,omitempty" bson:"reason,omitempty"`
+		Limits    *RiskLimits        `json:"limits" bson:"limits"`
+		CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	}
+)
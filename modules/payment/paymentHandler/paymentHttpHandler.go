@@ -2,7 +2,11 @@ package paymentHandler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
@@ -16,6 +20,20 @@ type (
 	PaymentHttpHandlerService interface {
 		BuyItem(c echo.Context) error
 		SellItem(c echo.Context) error
+		CreateCoupon(c echo.Context) error
+		FindCoupons(c echo.Context) error
+		EnableOrDisableCoupon(c echo.Context) error
+		FindCart(c echo.Context) error
+		AddCartItem(c echo.Context) error
+		RemoveCartItem(c echo.Context) error
+		ClearCart(c echo.Context) error
+		CheckoutCart(c echo.Context) error
+		FindOrder(c echo.Context) error
+		OrderEvents(c echo.Context) error
+		RefundOrder(c echo.Context) error
+		FindRiskRule(c echo.Context) error
+		UpsertRiskRule(c echo.Context) error
+		FindRiskDecisions(c echo.Context) error
 	}
 
 	paymentHttpHandler struct {
@@ -46,9 +64,17 @@ func (h *paymentHttpHandler) BuyItem(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
+	if c.QueryParam("async") == "true" {
+		res, err := h.paymentUsecase.BuyItemAsync(ctx, h.cfg, playerId, req)
+		if err != nil {
+			return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+		}
+		return response.SuccessResponse(c, http.StatusAccepted, res)
+	}
+
 	res, err := h.paymentUsecase.BuyItem(ctx, h.cfg, playerId, req)
 	if err != nil {
-		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+		return orderErrResponse(c, err)
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, res)
@@ -69,10 +95,292 @@ func (h *paymentHttpHandler) SellItem(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
+	if c.QueryParam("async") == "true" {
+		res, err := h.paymentUsecase.SellItemAsync(ctx, h.cfg, playerId, req)
+		if err != nil {
+			return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+		}
+		return response.SuccessResponse(c, http.StatusAccepted, res)
+	}
+
 	res, err := h.paymentUsecase.SellItem(ctx, h.cfg, playerId, req)
+	if err != nil {
+		return orderErrResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) CreateCoupon(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.CreateCouponReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.CreateCoupon(ctx, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *paymentHttpHandler) FindCoupons(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.CouponSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.FindCoupons(ctx, h.cfg, req)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, res)
 }
+
+func (h *paymentHttpHandler) EnableOrDisableCoupon(c echo.Context) error {
+	ctx := context.Background()
+
+	couponId := strings.TrimPrefix(c.Param("coupon_id"), "coupon:")
+
+	res, err := h.paymentUsecase.EnableOrDisableCoupon(ctx, couponId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"message": fmt.Sprintf("coupon_id: %s is successfully is activated to: %v", couponId, res),
+	})
+}
+
+func (h *paymentHttpHandler) FindCart(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.paymentUsecase.FindCart(ctx, h.cfg, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) AddCartItem(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.AddCartItemReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.AddCartItem(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) RemoveCartItem(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.paymentUsecase.RemoveCartItem(ctx, h.cfg, c.Get("player_id").(string), c.Param("item_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) ClearCart(c echo.Context) error {
+	ctx := context.Background()
+
+	if err := h.paymentUsecase.ClearCart(ctx, c.Get("player_id").(string)); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"message": "cart is cleared",
+	})
+}
+
+func (h *paymentHttpHandler) CheckoutCart(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.CartCheckoutReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if c.QueryParam("async") == "true" {
+		res, err := h.paymentUsecase.CheckoutCartAsync(ctx, h.cfg, c.Get("player_id").(string), req)
+		if err != nil {
+			return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+		}
+		return response.SuccessResponse(c, http.StatusAccepted, res)
+	}
+
+	res, err := h.paymentUsecase.CheckoutCart(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return orderErrResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) FindOrder(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.paymentUsecase.FindOrder(ctx, c.Get("player_id").(string), c.Param("order_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+// Note that: the stream sends a step event on every change of a step and an order event when the order is done
+func (h *paymentHttpHandler) OrderEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	playerId := c.Get("player_id").(string)
+	orderId := c.Param("order_id")
+
+	// Note that: the watch starts before the first read so a save between them is not missed, the order is still read
+	// every while because the saga of another instance does not notify this one
+	changed, stop := h.paymentUsecase.WatchOrder(orderId)
+	defer stop()
+
+	order, err := h.paymentUsecase.FindOrder(ctx, playerId, orderId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	deadline := time.After(5 * time.Minute)
+
+	sent := make(map[int]string)
+	for {
+		for i, v := range order.Steps {
+			if sent[i] == v.Status {
+				continue
+			}
+			if err := response.EventResponse(c, "step", v); err != nil {
+				return nil
+			}
+			sent[i] = v.Status
+		}
+
+		if order.IsDone() {
+			return response.EventResponse(c, "order", order)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline:
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+
+		order, err = h.paymentUsecase.FindOrder(ctx, playerId, orderId)
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func (h *paymentHttpHandler) RefundOrder(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.RefundOrderReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.RefundOrder(ctx, h.cfg, c.Get("player_id").(string), c.Param("order_id"), req)
+	if err != nil {
+		return orderErrResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) FindRiskRule(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.paymentUsecase.FindRiskRule(ctx, h.cfg, c.Param("player_id"))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) UpsertRiskRule(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.UpsertRiskRuleReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.UpsertRiskRule(ctx, h.cfg, c.Get("player_id").(string), c.Param("player_id"), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *paymentHttpHandler) FindRiskDecisions(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(payment.RiskDecisionSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.paymentUsecase.FindRiskDecisions(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+// Note that: a step that did not reply in time is a 504 with the order, the order can be polled by its id
+func orderErrResponse(c echo.Context, err error) error {
+	var timeoutErr *payment.OrderTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return response.SuccessResponse(c, http.StatusGatewayTimeout, timeoutErr.Order)
+	}
+	return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+}
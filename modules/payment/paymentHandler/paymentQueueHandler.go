@@ -0,0 +1,65 @@
+package paymentHandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentUsecase"
+)
+
+type (
+	PaymentQueueHandlerService interface {
+		RecoverStaleOrders()
+	}
+
+	paymentQueueHandler struct {
+		cfg            *config.Config
+		paymentUsecase paymentUsecase.PaymentUsecaseService
+	}
+)
+
+func NewPaymentQueueHandler(cfg *config.Config, paymentUsecase paymentUsecase.PaymentUsecaseService) PaymentQueueHandlerService {
+	return &paymentQueueHandler{
+		cfg:            cfg,
+		paymentUsecase: paymentUsecase,
+	}
+}
+
+// Note that: the stale orders are recovered at the start, the orders of a saga that stopped with the last run
+// are compensated first, then every step timeout
+func (h *paymentQueueHandler) RecoverStaleOrders() {
+	ctx := context.Background()
+
+	interval := h.cfg.Saga.StepTimeout
+	if interval <= 0 {
+		interval = 10
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	log.Println("Start RecoverStaleOrders ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	if err := h.paymentUsecase.RecoverStaleOrders(ctx, h.cfg); err != nil {
+		log.Println("Error: RecoverStaleOrders failed: ", err.Error())
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.paymentUsecase.RecoverStaleOrders(ctx, h.cfg); err != nil {
+				log.Println("Error: RecoverStaleOrders failed: ", err.Error())
+			}
+		case <-sigchan:
+			log.Println("Stop RecoverStaleOrders...")
+			return
+		}
+	}
+}
@@ -1,13 +1,24 @@
 package payment
 
+import (
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+)
+
 type (
+	// Note that: code is an optional coupon, it is only used by the buy
 	ItemServiceReq struct {
 		Items []*ItemServiceReqDatum `json:"items" validate:"required"`
+		Code  string                 `json:"code" validate:"max=32"`
 	}
 
+	// Note that: the price and the revision are filled from the item service, the ones of the request are ignored
 	ItemServiceReqDatum struct {
-		ItemId string  `json:"item_id" validate:"required,max=64"`
-		Price  float64 `json:"price"`
+		ItemId   string  `json:"item_id" validate:"required,max=64"`
+		Price    float64 `json:"price"`
+		Revision int     `json:"-"`
+		Discount float64 `json:"-"`
 	}
 
 	PaymentTransferReq struct {
@@ -16,12 +27,117 @@ type (
 		Amout    float64 `json:"amount"`
 	}
 
+	// Note that: inventory_ids are the items of a batch that were added, in the order of the request,
+	// order_id matches the reply to the step of the order that is waiting for it
 	PaymentTransferRes struct {
-		InventoryId   string  `json:"inventory_id"`
-		TransactionId string  `json:"transaction_id"`
-		PlayerId      string  `json:"player_id"`
-		ItemId        string  `json:"item_id"`
-		Amount        float64 `json:"amount"`
-		Error         string  `json:"error"`
+		OrderId       string   `json:"order_id"`
+		InventoryId   string   `json:"inventory_id"`
+		InventoryIds  []string `json:"inventory_ids,omitempty"`
+		TransactionId string   `json:"transaction_id"`
+		PlayerId      string   `json:"player_id"`
+		ItemId        string   `json:"item_id"`
+		ItemRevision  int      `json:"item_revision,omitempty"`
+		Amount        float64  `json:"amount"`
+		Discount      float64  `json:"discount,omitempty"`
+		Error         string   `json:"error"`
+	}
+
+	// Note that: value is the percent (1 to 100) or the amount of the discount, item_id is the item of free_item,
+	// a max of 0 is unlimited
+	CreateCouponReq struct {
+		Code             string    `json:"code" validate:"required,max=32"`
+		BenefitType      string    `json:"benefit_type" validate:"required"`
+		Value            float64   `json:"value"`
+		ItemId           string    `json:"item_id" validate:"max=64"`
+		MinSpend         float64   `json:"min_spend"`
+		MaxUses          int       `json:"max_uses"`
+		MaxUsesPerPlayer int       `json:"max_uses_per_player"`
+		ExpiresAt        time.Time `json:"expires_at" validate:"required"`
+	}
+
+	CouponSearchReq struct {
+		models.PaginateReq
+	}
+
+	// Note that: quantity is added to the quantity of the item in the cart, it is 1 when it is empty
+	AddCartItemReq struct {
+		ItemId   string `json:"item_id" validate:"required,max=64"`
+		Quantity int    `json:"quantity" validate:"min=0,max=99"`
+	}
+
+	CartCheckoutReq struct {
+		Code string `json:"code" validate:"max=32"`
+	}
+
+	// Note that: the prices are the current prices of the items, removed has the items that are not available anymore
+	// and were taken out of the cart by this read
+	CartRes struct {
+		PlayerId string         `json:"player_id"`
+		Items    []*CartItemRes `json:"items"`
+		Total    float64        `json:"total"`
+		Removed  []string       `json:"removed"`
+	}
+
+	CartItemRes struct {
+		ItemId   string  `json:"item_id"`
+		Title    string  `json:"title"`
+		Price    float64 `json:"price"`
+		ImageUrl string  `json:"image_url"`
+		Quantity int     `json:"quantity"`
+		Subtotal float64 `json:"subtotal"`
+	}
+
+	OrderRes struct {
+		OrderId       string       `json:"order_id"`
+		PlayerId      string       `json:"player_id"`
+		Type          string       `json:"type"`
+		Status        string       `json:"status"`
+		Step          string       `json:"step"`
+		Steps         []*OrderStep `json:"steps"`
+		Items         []*OrderItem `json:"items"`
+		Total         float64      `json:"total"`
+		TransactionId string       `json:"transaction_id,omitempty"`
+		RefundId      string       `json:"refund_id,omitempty"`
+		RefundOf      string       `json:"refund_of,omitempty"`
+		RefundedBy    string       `json:"refunded_by,omitempty"`
+		Reason        string       `json:"reason,omitempty"`
+		Error         string       `json:"error,omitempty"`
+		CreatedAt     time.Time    `json:"created_at"`
+		UpdatedAt     time.Time    `json:"updated_at"`
+	}
+
+	// Note that: an amount of 0 refunds the whole total of the order
+	RefundOrderReq struct {
+		Amount float64 `json:"amount" validate:"min=0"`
+		Reason string  `json:"reason" validate:"required,max=256"`
+	}
+
+	// Note that: a limit of 0 uses the limit of the config
+	UpsertRiskRuleReq struct {
+		DailySpendCap      float64 `json:"daily_spend_cap" validate:"min=0"`
+		MaxOrderValue      float64 `json:"max_order_value" validate:"min=0"`
+		MaxOrdersPerMinute int64   `json:"max_orders_per_minute" validate:"min=0"`
+		AllowNewAccount    bool    `json:"allow_new_account"`
+	}
+
+	// Note that: a limit of 0 is off, new_account_age is in second unit
+	RiskLimits struct {
+		DailySpendCap      float64 `json:"daily_spend_cap" bson:"daily_spend_cap"`
+		MaxOrderValue      float64 `json:"max_order_value" bson:"max_order_value"`
+		MaxOrdersPerMinute int64   `json:"max_orders_per_minute" bson:"max_orders_per_minute"`
+		NewAccountAge      int64   `json:"new_account_age" bson:"new_account_age"`
+	}
+
+	// Note that: rule is the risk rule of the player, limits are the limits that are checked on the next buy
+	RiskRuleRes struct {
+		PlayerId string      `json:"player_id"`
+		Rule     *RiskRule   `json:"rule"`
+		Limits   *RiskLimits `json:"limits"`
+	}
+
+	// Note that: player_id is optional, the decisions of every player are found when it is empty
+	RiskDecisionSearchReq struct {
+		PlayerId string `query:"player_id" validate:"max=64"`
+		models.PaginateReq
 	}
 )
@@ -0,0 +1,81 @@
+package payment
+
+// Order types
+const (
+	OrderBuy    = "buy"
+	OrderSell   = "sell"
+	OrderRefund = "refund"
+)
+
+// Order status
+const (
+	OrderPending   = "pending"
+	OrderCompleted = "completed"
+	OrderFailed    = "failed"
+	OrderTimedOut  = "timed_out"
+)
+
+// Order steps, a buy debits then adds the items, a sell and a refund remove the items then credit
+const (
+	StepDebit       = "debit"
+	StepAddItems    = "add_items"
+	StepRemoveItems = "remove_items"
+	StepCredit      = "credit"
+	StepCompensate  = "compensate"
+)
+
+// Order step status
+const (
+	StepStarted  = "started"
+	StepDone     = "done"
+	StepFailed   = "failed"
+	StepTimedOut = "timed_out"
+)
+
+// OrderTimeoutError is returned when a step of the order did not reply in time,
+// the steps that were done are compensated and the order can be polled by its id
+type OrderTimeoutError struct {
+	Order *OrderRes
+}
+
+func (e *OrderTimeoutError) Error() string {
+	return "error: " + e.Order.Step + " step timed out"
+}
+
+// Note that: the items and the steps are copied, the saga can still change the order after it
+func (o *Order) ToRes() *OrderRes {
+	items := make([]*OrderItem, 0, len(o.Items))
+	for _, v := range o.Items {
+		item := *v
+		items = append(items, &item)
+	}
+	steps := make([]*OrderStep, 0, len(o.Steps))
+	for _, v := range o.Steps {
+		step := *v
+		steps = append(steps, &step)
+	}
+
+	return &OrderRes{
+		OrderId:       o.Id.Hex(),
+		PlayerId:      o.PlayerId,
+		Type:          o.Type,
+		Status:        o.Status,
+		Step:          o.Step,
+		Steps:         steps,
+		Items:         items,
+		Total:         o.Total,
+		TransactionId: o.TransactionId,
+		RefundId:      o.RefundId,
+		RefundOf:      o.RefundOf,
+		RefundedBy:    o.RefundedBy,
+		Reason:        o.Reason,
+		Error:         o.Error,
+		CreatedAt:     o.CreatedAt,
+		UpdatedAt:     o.UpdatedAt,
+	}
+}
+
+// IsDone is true when the saga of the order does not run anymore
+func (o *OrderRes) IsDone() bool {
+	return o.Status != OrderPending
+}
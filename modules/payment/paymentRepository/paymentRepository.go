@@ -11,27 +11,67 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory"
 	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Note that: the order is not pending anymore, it was completed or failed by another saga or by the recover of the stale orders
+var ErrOrderSettled = errors.New("error: order is settled already")
+
 type (
 	PaymentRepositoryService interface {
 		GetOffset(pctx context.Context) (int64, error)
 		UpserOffset(pctx context.Context, offset int64) error
 		FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error)
+		FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error)
 		DockedPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error
 		RollbackTransaction(pctx context.Context, cfg *config.Config, req *player.RollbackPlayerTransactionReq) error
 		AddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error
 		RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq) error
 		RemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error
 		RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq) error
+		RefundPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error
 		AddPlayerMoney(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) error
+		InsertOneCoupon(pctx context.Context, req *payment.Coupon) (primitive.ObjectID, error)
+		FindOneCoupon(pctx context.Context, couponId string) (*payment.Coupon, error)
+		FindOneCouponByCode(pctx context.Context, code string) (*payment.Coupon, error)
+		FindManyCoupons(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*payment.Coupon, error)
+		CountCoupons(pctx context.Context, filter primitive.D) (int64, error)
+		UpdateOneCouponIsActive(pctx context.Context, couponId string, isActive bool) error
+		ReserveCouponUse(pctx context.Context, couponId string, now time.Time) error
+		ReleaseCouponUse(pctx context.Context, couponId string) error
+		ReserveCouponPlayerUse(pctx context.Context, couponId, playerId string, maxUses int) error
+		ReleaseCouponPlayerUse(pctx context.Context, couponId, playerId string) error
+		InsertOneCouponRedemption(pctx context.Context, req *payment.CouponRedemption) (primitive.ObjectID, error)
+		UpdateOneCouponRedemptionStatus(pctx context.Context, redemptionId primitive.ObjectID, from, to string) (bool, error)
+		FindOneCouponRedemption(pctx context.Context, redemptionId string) (*payment.CouponRedemption, error)
+		FindOneCart(pctx context.Context, playerId string) (*payment.Cart, error)
+		SaveOneCart(pctx context.Context, req *payment.Cart) error
+		ClaimOneCart(pctx context.Context, playerId string, version int, claim string, now, staleBefore time.Time) (*payment.Cart, error)
+		ReleaseOneCart(pctx context.Context, playerId, claim string) error
+		InsertOneOrder(pctx context.Context, req *payment.Order) (primitive.ObjectID, error)
+		UpdateOneOrder(pctx context.Context, req *payment.Order) error
+		FindOneOrder(pctx context.Context, orderId string) (*payment.Order, error)
+		FindManyStaleOrders(pctx context.Context, before time.Time) ([]*payment.Order, error)
+		ClaimOneOrderRefund(pctx context.Context, orderId, refundId string) (bool, error)
+		FindManySoldInventoryIds(pctx context.Context, playerId string, inventoryIds []string) ([]string, error)
+		ReleaseOneOrderRefund(pctx context.Context, orderId, refundId string) error
+		SumPlayerSpend(pctx context.Context, playerId string, since time.Time) (float64, error)
+		CountPlayerOrders(pctx context.Context, playerId string, since time.Time) (int64, error)
+		FindOneRiskRule(pctx context.Context, playerId string) (*payment.RiskRule, error)
+		UpsertOneRiskRule(pctx context.Context, req *payment.RiskRule) error
+		InsertOneRiskDecision(pctx context.Context, req *payment.RiskDecision) error
+		FindManyRiskDecisions(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*payment.RiskDecision, error)
+		CountRiskDecisions(pctx context.Context, filter primitive.D) (int64, error)
 	}
 
 	paymentRepository struct {
@@ -97,14 +137,29 @@ func (r *paymentRepository) FindItemsInIds(pctx context.Context, grpcUrl string,
 		return nil, errors.New("error: items not found")
 	}
 
+	// Note that: an empty result is not an error, the caller checks the items that it needs
 	if result == nil {
-		log.Printf("Error: FindItemsInIds failed: %s", err.Error())
-		return nil, errors.New("error: items not found")
+		return &itemPb.FindItemsInIdsRes{Items: make([]*itemPb.Item, 0)}, nil
 	}
 
-	if len(result.Items) == 0 {
-		log.Printf("Error: FindItemsInIds failed: %s", err.Error())
-		return nil, errors.New("error: items not found")
+	return result, nil
+}
+
+func (r *paymentRepository) FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Player().FindOnePlayerProfileToRefresh(ctx, req)
+	if err != nil {
+		log.Printf("Error: FindOnePlayerProfile failed: %s", err.Error())
+		return nil, errors.New("error: player profile not found")
 	}
 
 	return result, nil
@@ -263,3 +318,743 @@ func (r *paymentRepository) RollbackRemovePlayerItem(pctx context.Context, cfg *
 
 	return nil
 }
+
+func (r *paymentRepository) RefundPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.UpdateInventoryReq) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: RefundPlayerItem failed: %s", err.Error())
+		return errors.New("error: refund player item failed")
+	}
+
+	if err := queue.PushMessageWithKeyToQueue(
+		[]string{cfg.Kafka.Url},
+		cfg.Kafka.ApiKey,
+		cfg.Kafka.Secret,
+		"inventory",
+		"refund",
+		reqInBytes,
+	); err != nil {
+		log.Printf("Error: RefundPlayerItem failed: %s", err.Error())
+		return errors.New("error: refund player item failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) InsertOneCoupon(pctx context.Context, req *payment.Coupon) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	couponId, err := col.InsertOne(ctx, req)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.NilObjectID, errors.New("error: this code is already exist")
+		}
+		log.Printf("Error: InsertOneCoupon failed: %s", err.Error())
+		return primitive.NilObjectID, errors.New("error: insert one coupon failed")
+	}
+
+	return couponId.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *paymentRepository) FindOneCoupon(pctx context.Context, couponId string) (*payment.Coupon, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	result := new(payment.Coupon)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(couponId)}).Decode(result); err != nil {
+		log.Printf("Error: FindOneCoupon failed: %s", err.Error())
+		return nil, errors.New("error: coupon not found")
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) FindOneCouponByCode(pctx context.Context, code string) (*payment.Coupon, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	result := new(payment.Coupon)
+	if err := col.FindOne(ctx, bson.M{"code": code}).Decode(result); err != nil {
+		log.Printf("Error: FindOneCouponByCode failed: %s", err.Error())
+		return nil, errors.New("error: coupon not found")
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) FindManyCoupons(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*payment.Coupon, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyCoupons failed: %s", err.Error())
+		return make([]*payment.Coupon, 0), errors.New("error: find many coupons failed")
+	}
+
+	results := make([]*payment.Coupon, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyCoupons failed: %s", err.Error())
+		return make([]*payment.Coupon, 0), errors.New("error: find many coupons failed")
+	}
+
+	return results, nil
+}
+
+func (r *paymentRepository) CountCoupons(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountCoupons failed: %s", err.Error())
+		return -1, errors.New("error: count coupons failed")
+	}
+
+	return count, nil
+}
+
+func (r *paymentRepository) UpdateOneCouponIsActive(pctx context.Context, couponId string, isActive bool) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(couponId)},
+		bson.M{"$set": bson.M{"is_active": isActive, "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOneCouponIsActive failed: %s", err.Error())
+		return errors.New("error: update coupon failed")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("error: coupon not found")
+	}
+
+	return nil
+}
+
+// Note that: the use is only taken when the coupon is active, not expired and has a use left, in one update
+func (r *paymentRepository) ReserveCouponUse(pctx context.Context, couponId string, now time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":        utils.ConvertToObjectId(couponId),
+			"is_active":  true,
+			"expires_at": bson.M{"$gt": now},
+			"$expr": bson.M{"$or": bson.A{
+				bson.M{"$lte": bson.A{"$max_uses", 0}},
+				bson.M{"$lt": bson.A{"$uses", "$max_uses"}},
+			}},
+		},
+		bson.M{"$inc": bson.M{"uses": 1}},
+	)
+	if err != nil {
+		log.Printf("Error: ReserveCouponUse failed: %s", err.Error())
+		return errors.New("error: reserve coupon failed")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("error: coupon has been used up")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) ReleaseCouponUse(pctx context.Context, couponId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupons")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(couponId), "uses": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"uses": -1}},
+	); err != nil {
+		log.Printf("Error: ReleaseCouponUse failed: %s", err.Error())
+		return errors.New("error: release coupon failed")
+	}
+
+	return nil
+}
+
+// Note that: the uses of a player are one document, unique by the coupon and the player, so the upsert of a player
+// who has no use left fails with a duplicate key instead of making a second document
+func (r *paymentRepository) ReserveCouponPlayerUse(pctx context.Context, couponId, playerId string, maxUses int) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupon_player_uses")
+
+	filter := bson.M{"coupon_id": couponId, "player_id": playerId}
+	if maxUses > 0 {
+		filter["uses"] = bson.M{"$lt": maxUses}
+	}
+
+	if _, err := col.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"uses": 1}}, options.Update().SetUpsert(true)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("error: coupon has been used up by this player")
+		}
+		log.Printf("Error: ReserveCouponPlayerUse failed: %s", err.Error())
+		return errors.New("error: reserve coupon failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) ReleaseCouponPlayerUse(pctx context.Context, couponId, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupon_player_uses")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"coupon_id": couponId, "player_id": playerId, "uses": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"uses": -1}},
+	); err != nil {
+		log.Printf("Error: ReleaseCouponPlayerUse failed: %s", err.Error())
+		return errors.New("error: release coupon failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) InsertOneCouponRedemption(pctx context.Context, req *payment.CouponRedemption) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupon_redemptions")
+
+	redemptionId, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("Error: InsertOneCouponRedemption failed: %s", err.Error())
+		return primitive.NilObjectID, errors.New("error: insert one coupon redemption failed")
+	}
+
+	return redemptionId.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *paymentRepository) FindOneCouponRedemption(pctx context.Context, redemptionId string) (*payment.CouponRedemption, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupon_redemptions")
+
+	result := new(payment.CouponRedemption)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(redemptionId)}).Decode(result); err != nil {
+		log.Printf("Error: FindOneCouponRedemption failed: %s", err.Error())
+		return nil, errors.New("error: coupon redemption not found")
+	}
+
+	return result, nil
+}
+
+// Note that: returns false when the redemption is not in the from status, so a redemption is only released once
+func (r *paymentRepository) UpdateOneCouponRedemptionStatus(pctx context.Context, redemptionId primitive.ObjectID, from, to string) (bool, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("coupon_redemptions")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": redemptionId, "status": from},
+		bson.M{"$set": bson.M{"status": to, "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOneCouponRedemptionStatus failed: %s", err.Error())
+		return false, errors.New("error: update coupon redemption failed")
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// Note that: a player without a cart has an empty one, it is inserted by the first save
+func (r *paymentRepository) FindOneCart(pctx context.Context, playerId string) (*payment.Cart, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("carts")
+
+	result := new(payment.Cart)
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &payment.Cart{PlayerId: playerId, Items: make([]*payment.CartItem, 0)}, nil
+		}
+		log.Printf("Error: FindOneCart failed: %s", err.Error())
+		return nil, errors.New("error: find cart failed")
+	}
+
+	return result, nil
+}
+
+// SaveOneCart saves the items of the cart when the cart is still at the version that was read
+func (r *paymentRepository) SaveOneCart(pctx context.Context, req *payment.Cart) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("carts")
+
+	conflict := errors.New("error: cart has been changed by another request, try again")
+
+	if req.Version == 0 {
+		req.Version = 1
+		if _, err := col.InsertOne(ctx, req); err != nil {
+			req.Version = 0
+			if mongo.IsDuplicateKeyError(err) {
+				return conflict
+			}
+			log.Printf("Error: SaveOneCart failed: %s", err.Error())
+			return errors.New("error: save cart failed")
+		}
+		return nil
+	}
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": req.PlayerId, "version": req.Version},
+		bson.M{"$set": bson.M{
+			"items":      req.Items,
+			"version":    req.Version + 1,
+			"updated_at": req.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		log.Printf("Error: SaveOneCart failed: %s", err.Error())
+		return errors.New("error: save cart failed")
+	}
+	if result.MatchedCount == 0 {
+		return conflict
+	}
+	req.Version++
+
+	return nil
+}
+
+// ClaimOneCart marks the cart at the version that was read as checking out, only one checkout of a cart runs at a time
+func (r *paymentRepository) ClaimOneCart(pctx context.Context, playerId string, version int, claim string, now, staleBefore time.Time) (*payment.Cart, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("carts")
+
+	result := new(payment.Cart)
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"player_id": playerId,
+			"version":   version,
+			"$or": bson.A{
+				bson.M{"checking_out": bson.M{"$exists": false}},
+				bson.M{"checking_out_at": bson.M{"$lt": staleBefore}},
+			},
+		},
+		bson.M{"$set": bson.M{"checking_out": claim, "checking_out_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("error: cart is being checked out or has been changed by another request, try again")
+		}
+		log.Printf("Error: ClaimOneCart failed: %s", err.Error())
+		return nil, errors.New("error: claim cart failed")
+	}
+
+	return result, nil
+}
+
+// Note that: the claim is only released by the checkout that holds it
+func (r *paymentRepository) ReleaseOneCart(pctx context.Context, playerId, claim string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("carts")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": playerId, "checking_out": claim},
+		bson.M{"$unset": bson.M{"checking_out": "", "checking_out_at": ""}},
+	); err != nil {
+		log.Printf("Error: ReleaseOneCart failed: %s", err.Error())
+		return errors.New("error: release cart failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) InsertOneOrder(pctx context.Context, req *payment.Order) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	result, err := col.InsertOne(ctx, req)
+	if err != nil {
+		log.Printf("Error: InsertOneOrder failed: %s", err.Error())
+		return primitive.NilObjectID, errors.New("error: insert one order failed")
+	}
+
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// UpdateOneOrder saves the progress of the saga, the items and the total are set when the order is inserted.
+// Only a pending order is saved, an order that is settled already returns ErrOrderSettled
+func (r *paymentRepository) UpdateOneOrder(pctx context.Context, req *payment.Order) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": req.Id, "status": payment.OrderPending},
+		bson.M{"$set": bson.M{
+			"status":         req.Status,
+			"step":           req.Step,
+			"steps":          req.Steps,
+			"items":          req.Items,
+			"transaction_id": req.TransactionId,
+			"error":          req.Error,
+			"updated_at":     req.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOneOrder failed: %s", err.Error())
+		return errors.New("error: update one order failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: UpdateOneOrder failed: order %s is settled already", req.Id.Hex())
+		return ErrOrderSettled
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) FindOneOrder(pctx context.Context, orderId string) (*payment.Order, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	result := new(payment.Order)
+	if err := col.FindOne(ctx, bson.M{"_id": utils.ConvertToObjectId(orderId)}).Decode(result); err != nil {
+		log.Printf("Error: FindOneOrder failed: %s", err.Error())
+		return nil, errors.New("error: order not found")
+	}
+
+	return result, nil
+}
+
+// FindManyStaleOrders finds the pending orders that were not saved since the time, a running saga saves its order at every step
+func (r *paymentRepository) FindManyStaleOrders(pctx context.Context, before time.Time) ([]*payment.Order, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	cursors, err := col.Find(ctx, bson.M{
+		"status":     payment.OrderPending,
+		"updated_at": bson.M{"$lt": before},
+	})
+	if err != nil {
+		log.Printf("Error: FindManyStaleOrders failed: %s", err.Error())
+		return make([]*payment.Order, 0), errors.New("error: find many stale orders failed")
+	}
+
+	results := make([]*payment.Order, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyStaleOrders failed: %s", err.Error())
+		return make([]*payment.Order, 0), errors.New("error: find many stale orders failed")
+	}
+
+	return results, nil
+}
+
+// FindManySoldInventoryIds finds which of the inventory ids were sold by a completed sell of the player
+func (r *paymentRepository) FindManySoldInventoryIds(pctx context.Context, playerId string, inventoryIds []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	cursors, err := col.Find(ctx, bson.M{
+		"player_id":          playerId,
+		"type":               payment.OrderSell,
+		"status":             bson.M{"$in": []string{payment.OrderPending, payment.OrderCompleted}},
+		"items.inventory_id": bson.M{"$in": inventoryIds},
+	})
+	if err != nil {
+		log.Printf("Error: FindManySoldInventoryIds failed: %s", err.Error())
+		return make([]string, 0), errors.New("error: find sold items failed")
+	}
+
+	sells := make([]*payment.Order, 0)
+	if err := cursors.All(ctx, &sells); err != nil {
+		log.Printf("Error: FindManySoldInventoryIds failed: %s", err.Error())
+		return make([]string, 0), errors.New("error: find sold items failed")
+	}
+
+	setIds := make(map[string]bool)
+	for _, v := range inventoryIds {
+		setIds[v] = true
+	}
+
+	results := make([]string, 0)
+	for _, sell := range sells {
+		for _, v := range sell.Items {
+			if setIds[v.InventoryId] {
+				results = append(results, v.InventoryId)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ClaimOneOrderRefund links the refund to a completed buy that has no refund yet, so a buy is refunded once
+func (r *paymentRepository) ClaimOneOrderRefund(pctx context.Context, orderId, refundId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":       utils.ConvertToObjectId(orderId),
+			"type":      payment.OrderBuy,
+			"status":    payment.OrderCompleted,
+			"refund_id": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"refund_id": refundId, "updated_at": utils.LocalTime()}},
+	)
+	if err != nil {
+		log.Printf("Error: ClaimOneOrderRefund failed: %s", err.Error())
+		return false, errors.New("error: claim order refund failed")
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseOneOrderRefund unlinks a refund that failed, the buy can be refunded again
+func (r *paymentRepository) ReleaseOneOrderRefund(pctx context.Context, orderId, refundId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(orderId), "refund_id": refundId},
+		bson.M{"$unset": bson.M{"refund_id": ""}, "$set": bson.M{"updated_at": utils.LocalTime()}},
+	); err != nil {
+		log.Printf("Error: ReleaseOneOrderRefund failed: %s", err.Error())
+		return errors.New("error: release order refund failed")
+	}
+
+	return nil
+}
+
+// SumPlayerSpend sums the totals of the buys since the time, a buy that failed or timed out was compensated and is not counted
+func (r *paymentRepository) SumPlayerSpend(pctx context.Context, playerId string, since time.Time) (float64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	pipeline := bson.A{
+		bson.D{{"$match", bson.D{
+			{"player_id", playerId},
+			{"type", payment.OrderBuy},
+			{"status", bson.D{{"$in", bson.A{payment.OrderPending, payment.OrderCompleted}}}},
+			{"created_at", bson.D{{"$gte", since}}},
+		}}},
+		bson.D{{"$group", bson.D{
+			{"_id", "$player_id"},
+			{"total", bson.D{{"$sum", "$total"}}},
+		}}},
+	}
+
+	cursors, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("Error: SumPlayerSpend failed: %s", err.Error())
+		return 0, errors.New("error: sum player spend failed")
+	}
+
+	result := new(struct {
+		Total float64 `bson:"total"`
+	})
+	for cursors.Next(ctx) {
+		if err := cursors.Decode(result); err != nil {
+			log.Printf("Error: SumPlayerSpend failed: %s", err.Error())
+			return 0, errors.New("error: sum player spend failed")
+		}
+	}
+
+	return result.Total, nil
+}
+
+// CountPlayerOrders counts the buys since the time, every buy that was checked is counted also the rejected ones
+func (r *paymentRepository) CountPlayerOrders(pctx context.Context, playerId string, since time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("orders")
+
+	count, err := col.CountDocuments(ctx, bson.M{
+		"player_id":  playerId,
+		"type":       payment.OrderBuy,
+		"created_at": bson.M{"$gte": since},
+	})
+	if err != nil {
+		log.Printf("Error: CountPlayerOrders failed: %s", err.Error())
+		return -1, errors.New("error: count player orders failed")
+	}
+
+	return count, nil
+}
+
+// Note that: a player without a risk rule has none, the limits of the config are used
+func (r *paymentRepository) FindOneRiskRule(pctx context.Context, playerId string) (*payment.RiskRule, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("risk_rules")
+
+	result := new(payment.RiskRule)
+	if err := col.FindOne(ctx, bson.M{"player_id": playerId}).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("Error: FindOneRiskRule failed: %s", err.Error())
+		return nil, errors.New("error: find risk rule failed")
+	}
+
+	return result, nil
+}
+
+func (r *paymentRepository) UpsertOneRiskRule(pctx context.Context, req *payment.RiskRule) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("risk_rules")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"player_id": req.PlayerId},
+		bson.M{"$set": bson.M{
+			"daily_spend_cap":       req.DailySpendCap,
+			"max_order_value":       req.MaxOrderValue,
+			"max_orders_per_minute": req.MaxOrdersPerMinute,
+			"allow_new_account":     req.AllowNewAccount,
+			"updated_by":            req.UpdatedBy,
+			"updated_at":            req.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		log.Printf("Error: UpsertOneRiskRule failed: %s", err.Error())
+		return errors.New("error: upsert risk rule failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) InsertOneRiskDecision(pctx context.Context, req *payment.RiskDecision) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("risk_decisions")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneRiskDecision failed: %s", err.Error())
+		return errors.New("error: insert one risk decision failed")
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) FindManyRiskDecisions(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*payment.RiskDecision, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("risk_decisions")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyRiskDecisions failed: %s", err.Error())
+		return make([]*payment.RiskDecision, 0), errors.New("error: find many risk decisions failed")
+	}
+
+	results := make([]*payment.RiskDecision, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyRiskDecisions failed: %s", err.Error())
+		return make([]*payment.RiskDecision, 0), errors.New("error: find many risk decisions failed")
+	}
+
+	return results, nil
+}
+
+func (r *paymentRepository) CountRiskDecisions(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.paymentDbConn(ctx)
+	col := db.Collection("risk_decisions")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountRiskDecisions failed: %s", err.Error())
+		return -1, errors.New("error: count risk decisions failed")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"fmt"
+	"time"
+)
+
+// Risk rules, they are checked in this order and the first one that rejects the buy is recorded
+const (
+	RuleNewAccount    = "new_account"
+	RuleMaxOrderValue = "max_order_value"
+	RuleVelocity      = "velocity"
+	RuleDailySpendCap = "daily_spend_cap"
+)
+
+// RiskCheck is the buy and the recent buys of the player, spent_today and orders_last_minute count this buy
+// because its pending order is inserted before the check
+type RiskCheck struct {
+	Total            float64
+	SpentToday       float64
+	OrdersLastMinute int64
+	AccountAge       time.Duration
+}
+
+// Limits are the limits of the config with the ones of the rule on top, a player that is allowed as a new account
+// is not checked by the new_account rule
+func (r *RiskRule) Limits(defaults *RiskLimits) *RiskLimits {
+	limits := *defaults
+	if r == nil {
+		return &limits
+	}
+
+	if r.DailySpendCap > 0 {
+		limits.DailySpendCap = r.DailySpendCap
+	}
+	if r.MaxOrderValue > 0 {
+		limits.MaxOrderValue = r.MaxOrderValue
+	}
+	if r.MaxOrdersPerMinute > 0 {
+		limits.MaxOrdersPerMinute = r.MaxOrdersPerMinute
+	}
+	if r.AllowNewAccount {
+		limits.NewAccountAge = 0
+	}
+	return &limits
+}
+
+// Evaluate returns the rule that rejects the buy and why, the rule is empty when the buy is allowed
+func (l *RiskLimits) Evaluate(c *RiskCheck) (string, string) {
+	if l.NewAccountAge > 0 {
+		if age := time.Duration(l.NewAccountAge) * time.Second; c.AccountAge < age {
+			return RuleNewAccount, fmt.Sprintf("account is younger than %v", age)
+		}
+	}
+	if l.MaxOrderValue > 0 && c.Total > l.MaxOrderValue {
+		return RuleMaxOrderValue, fmt.Sprintf("total %v is more than the max order value of %v", c.Total, l.MaxOrderValue)
+	}
+	if l.MaxOrdersPerMinute > 0 && c.OrdersLastMinute > l.MaxOrdersPerMinute {
+		return RuleVelocity, fmt.Sprintf("more than %d orders in a minute", l.MaxOrdersPerMinute)
+	}
+	if l.DailySpendCap > 0 && roundMoney(c.SpentToday) > l.DailySpendCap {
+		return RuleDailySpendCap, fmt.Sprintf("spend of today %v is more than the daily spend cap of %v", roundMoney(c.SpentToday), l.DailySpendCap)
+	}
+	return "", ""
+}
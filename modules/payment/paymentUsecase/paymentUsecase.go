@@ -3,17 +3,27 @@ package paymentUsecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
 	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type (
@@ -22,17 +32,41 @@ type (
 		UpserOffset(pctx context.Context, offset int64) error
 		FindItemsInIds(pctx context.Context, grpcUrl string, req []*payment.ItemServiceReqDatum) error
 		BuyItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error)
+		BuyItemAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) (*payment.OrderRes, error)
 		SellItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error)
+		SellItemAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) (*payment.OrderRes, error)
+		CreateCoupon(pctx context.Context, playerId string, req *payment.CreateCouponReq) (*payment.Coupon, error)
+		FindCoupons(pctx context.Context, cfg *config.Config, req *payment.CouponSearchReq) (*models.PaginateRes, error)
+		EnableOrDisableCoupon(pctx context.Context, couponId string) (bool, error)
+		FindCart(pctx context.Context, cfg *config.Config, playerId string) (*payment.CartRes, error)
+		AddCartItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.AddCartItemReq) (*payment.CartRes, error)
+		RemoveCartItem(pctx context.Context, cfg *config.Config, playerId, itemId string) (*payment.CartRes, error)
+		ClearCart(pctx context.Context, playerId string) error
+		CheckoutCart(pctx context.Context, cfg *config.Config, playerId string, req *payment.CartCheckoutReq) ([]*payment.PaymentTransferRes, error)
+		CheckoutCartAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.CartCheckoutReq) (*payment.OrderRes, error)
+		FindOrder(pctx context.Context, playerId, orderId string) (*payment.OrderRes, error)
+		WatchOrder(orderId string) (<-chan struct{}, func())
+		WaitSagas(pctx context.Context) error
+		RecoverStaleOrders(pctx context.Context, cfg *config.Config) error
+		RefundOrder(pctx context.Context, cfg *config.Config, adminId, orderId string, req *payment.RefundOrderReq) (*payment.OrderRes, error)
+		FindRiskRule(pctx context.Context, cfg *config.Config, playerId string) (*payment.RiskRuleRes, error)
+		UpsertRiskRule(pctx context.Context, cfg *config.Config, adminId, playerId string, req *payment.UpsertRiskRuleReq) (*payment.RiskRuleRes, error)
+		FindRiskDecisions(pctx context.Context, cfg *config.Config, req *payment.RiskDecisionSearchReq) (*models.PaginateRes, error)
 	}
 
+	// Note that: sagas are the async sagas that are running, watchers are notified every time their order is saved
 	paymentUsecase struct {
 		paymentRepository paymentRepository.PaymentRepositoryService
+		sagas             sync.WaitGroup
+		mu                sync.Mutex
+		watchers          map[string][]chan struct{}
 	}
 )
 
 func NewPaymentUsecase(paymentRepository paymentRepository.PaymentRepositoryService) PaymentUsecaseService {
 	return &paymentUsecase{
 		paymentRepository: paymentRepository,
+		watchers:          make(map[string][]chan struct{}),
 	}
 }
 
@@ -67,258 +101,1424 @@ func (u *paymentUsecase) PaymentConsumer(pctx context.Context, cfg *config.Confi
 	return consumer, nil
 }
 
-func (u *paymentUsecase) BuyOrSellConsumer(pctx context.Context, key string, cfg *config.Config, resCh chan<- *payment.PaymentTransferRes) {
-	consumer, err := u.PaymentConsumer(pctx, cfg)
+func (u *paymentUsecase) BuyItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
+	order, redemption, err := u.prepareBuy(pctx, cfg, playerId, req)
 	if err != nil {
-		resCh <- nil
-		return
+		return nil, err
 	}
-	defer consumer.Close()
 
-	log.Println("Start BuyOrSellConsumer ...")
+	return u.runBuy(pctx, cfg, order, redemption)
+}
+
+// BuyItemAsync checks the buy and returns the pending order, the saga runs after the return
+func (u *paymentUsecase) BuyItemAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) (*payment.OrderRes, error) {
+	order, redemption, err := u.prepareBuy(pctx, cfg, playerId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := order.ToRes()
+
+	u.goSaga(func(ctx context.Context) {
+		u.runBuy(ctx, cfg, order, redemption)
+	})
+
+	return res, nil
+}
+
+// goSaga runs an async saga after the return, the saga does not use the context of the request
+// because the request is done before the saga, the shutdown waits for it by WaitSagas
+func (u *paymentUsecase) goSaga(run func(ctx context.Context)) {
+	u.sagas.Add(1)
+	go func() {
+		defer u.sagas.Done()
+		run(context.Background())
+	}()
+}
+
+// WaitSagas waits for the async sagas until the context is done, a saga that is still running is left pending
+// and is compensated by RecoverStaleOrders
+func (u *paymentUsecase) WaitSagas(pctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		u.sagas.Wait()
+		close(done)
+	}()
 
 	select {
-	case err := <-consumer.Errors():
-		log.Println("Error: BuyOrSellConsumer failed: ", err.Error())
-		resCh <- nil
-		return
-	case msg := <-consumer.Messages():
-		if string(msg.Key) == key {
-			u.UpserOffset(pctx, msg.Offset+1)
+	case <-done:
+		return nil
+	case <-pctx.Done():
+		return errors.New("error: sagas are still running")
+	}
+}
 
-			req := new(payment.PaymentTransferRes)
+// prepareBuy checks the buy, reserves the coupon and inserts the pending order
+func (u *paymentUsecase) prepareBuy(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) (*payment.Order, *payment.CouponRedemption, error) {
+	rule, err := u.paymentRepository.FindOneRiskRule(pctx, playerId)
+	if err != nil {
+		return nil, nil, err
+	}
+	limits := rule.Limits(riskDefaults(cfg))
 
-			if err := queue.DecodeMessage(req, msg.Value); err != nil {
-				resCh <- nil
-				return
-			}
+	// The profile is read for the verify of the email and for the age of the account
+	var profile *playerPb.PlayerProfile
+	if !cfg.Verify.AllowUnverifiedBuy || limits.NewAccountAge > 0 {
+		profile, err = u.paymentRepository.FindOnePlayerProfile(pctx, cfg.Grpc.PlayerUrl, &playerPb.FindOnePlayerProfileToRefreshReq{
+			PlayerId: strings.TrimPrefix(playerId, "player:"),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if !cfg.Verify.AllowUnverifiedBuy && !profile.IsVerified {
+			return nil, nil, errors.New("error: email is not verified")
+		}
+	}
 
-			resCh <- req
-			log.Printf("BuyOrSellConsumer | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+	// The item of a free_item coupon is bought with the other items, the coupon takes its price off
+	var coupon *payment.Coupon
+	if req.Code != "" {
+		var err error
+		coupon, err = u.paymentRepository.FindOneCouponByCode(pctx, payment.NormalizeCouponCode(req.Code))
+		if err != nil {
+			return nil, nil, err
+		}
+		if coupon.BenefitType == payment.BenefitFreeItem {
+			req.Items = append(req.Items, &payment.ItemServiceReqDatum{ItemId: coupon.ItemId})
 		}
 	}
-}
 
-func (u *paymentUsecase) BuyItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
 	if err := u.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, req.Items); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Note that: the coupon is reserved before the first step, every compensation below releases it
+	var redemption *payment.CouponRedemption
+	if coupon != nil {
+		if err := coupon.Check(utils.LocalTime(), req.Items); err != nil {
+			return nil, nil, err
+		}
+
+		var err error
+		redemption, err = u.reserveCoupon(pctx, coupon, playerId, coupon.Apply(req.Items))
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	stage1 := make([]*payment.PaymentTransferRes, 0)
+	// Note that: the order is one debit of the total and one insert of all the items, not one of each per item
+	total := 0.0
 	for _, item := range req.Items {
-		u.paymentRepository.DockedPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
-			PlayerId: playerId,
-			Amount:   -item.Price,
-		})
+		total += item.Price
+	}
 
-		resCh := make(chan *payment.PaymentTransferRes)
+	total = math.Round(total*100) / 100
 
-		go u.BuyOrSellConsumer(pctx, "buy", cfg, resCh)
+	// Note that: the pending order is inserted before the risk rules are checked so the check counts it, two buys
+	// at the same moment see each other and can not both pass the daily spend cap or the velocity.
+	// A rejected buy is kept as a failed order so the decision has an order to point to
+	order, err := u.insertOrder(pctx, playerId, payment.OrderBuy, req, total, redemption)
+	if err != nil {
+		u.releaseCoupon(pctx, redemption)
+		return nil, nil, err
+	}
 
-		res := <-resCh
-		if res != nil {
-			log.Println(res)
-			stage1 = append(stage1, &payment.PaymentTransferRes{
-				InventoryId:   "",
-				TransactionId: res.TransactionId,
-				PlayerId:      playerId,
-				ItemId:        item.ItemId,
-				Amount:        item.Price,
-				Error:         res.Error,
-			})
-		}
+	decision, err := u.checkRisk(pctx, playerId, limits, profile, total)
+	if err != nil {
+		u.rejectOrder(pctx, order, redemption, err)
+		return nil, nil, err
 	}
 
-	for _, s1 := range stage1 {
-		if s1.Error != "" {
-			for _, ss1 := range stage1 {
-				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
-					TransactionId: ss1.TransactionId,
-				})
-			}
-			return nil, errors.New("error: buy item failed")
-		}
+	decision.OrderId = order.Id.Hex()
+	if err := u.paymentRepository.InsertOneRiskDecision(pctx, decision); err != nil {
+		u.rejectOrder(pctx, order, redemption, err)
+		return nil, nil, err
+	}
+	if !decision.IsAllowed {
+		err := fmt.Errorf("error: buy is rejected by the %s rule, %s", decision.Rule, decision.Reason)
+		u.rejectOrder(pctx, order, redemption, err)
+		return nil, nil, err
+	}
+
+	return order, redemption, nil
+}
+
+// checkRisk evaluates the risk rules on the buy, the pending order of the buy is inserted already
+// so the spend and the count of the orders include it
+func (u *paymentUsecase) checkRisk(pctx context.Context, playerId string, limits *payment.RiskLimits, profile *playerPb.PlayerProfile, total float64) (*payment.RiskDecision, error) {
+	now := utils.LocalTime()
+	check := &payment.RiskCheck{Total: total}
 
+	if limits.DailySpendCap > 0 {
+		spent, err := u.paymentRepository.SumPlayerSpend(pctx, playerId, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+		if err != nil {
+			return nil, err
+		}
+		check.SpentToday = spent
+	}
+	if limits.MaxOrdersPerMinute > 0 {
+		count, err := u.paymentRepository.CountPlayerOrders(pctx, playerId, now.Add(-time.Minute))
+		if err != nil {
+			return nil, err
+		}
+		check.OrdersLastMinute = count
+	}
+	if limits.NewAccountAge > 0 && profile != nil {
+		check.AccountAge = now.Sub(utils.ConvertStringTimeToTime(profile.CreatedAt))
 	}
 
-	stage2 := make([]*payment.PaymentTransferRes, 0)
-	for _, s1 := range stage1 {
-		u.paymentRepository.AddPlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
-			PlayerId: playerId,
-			ItemId:   s1.ItemId,
-		})
+	rule, reason := limits.Evaluate(check)
+
+	return &payment.RiskDecision{
+		PlayerId:  playerId,
+		Total:     total,
+		IsAllowed: rule == "",
+		Rule:      rule,
+		Reason:    reason,
+		Limits:    limits,
+		CreatedAt: now,
+	}, nil
+}
 
-		resCh := make(chan *payment.PaymentTransferRes)
+// rejectOrder fails a buy that did not start, nothing was debited so only the coupon is released
+func (u *paymentUsecase) rejectOrder(pctx context.Context, order *payment.Order, redemption *payment.CouponRedemption, err error) {
+	u.releaseCoupon(pctx, redemption)
+	order.Status = payment.OrderFailed
+	order.Error = err.Error()
+	u.saveOrder(pctx, order)
+}
+
+func riskDefaults(cfg *config.Config) *payment.RiskLimits {
+	return &payment.RiskLimits{
+		DailySpendCap:      cfg.Risk.DailySpendCap,
+		MaxOrderValue:      cfg.Risk.MaxOrderValue,
+		MaxOrdersPerMinute: cfg.Risk.MaxOrdersPerMinute,
+		NewAccountAge:      cfg.Risk.NewAccountAge,
+	}
+}
 
-		go u.BuyOrSellConsumer(pctx, "buy", cfg, resCh)
+func (u *paymentUsecase) runBuy(pctx context.Context, cfg *config.Config, order *payment.Order, redemption *payment.CouponRedemption) ([]*payment.PaymentTransferRes, error) {
+	orderId := order.Id.Hex()
+	playerId := order.PlayerId
 
-		res := <-resCh
-		if res != nil {
-			log.Println(res)
-			stage2 = append(stage2, &payment.PaymentTransferRes{
-				InventoryId:   res.InventoryId,
-				TransactionId: s1.TransactionId,
-				PlayerId:      playerId,
-				ItemId:        s1.ItemId,
-				Amount:        s1.Amount,
-				Error:         s1.Error,
+	// Stage 1: debit the total, an order that is free after the coupon has nothing to debit
+	if order.Total > 0 {
+		res, err := u.sagaStep(pctx, cfg, order, payment.StepDebit, "buy", func() error {
+			return u.paymentRepository.DockedPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
+				OrderId:  orderId,
+				PlayerId: playerId,
+				Amount:   -order.Total,
 			})
+		})
+		if err != nil {
+			// Note that: a debit that did not reply may still be done, the rollback of the order undoes it whenever it comes
+			u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{OrderId: orderId})
+			u.releaseCoupon(pctx, redemption)
+			return nil, u.failOrder(pctx, order, err)
 		}
+		order.TransactionId = res.TransactionId
 	}
 
-	for _, s2 := range stage2 {
-		if s2.Error != "" {
-			for _, ss2 := range stage2 {
-				u.paymentRepository.RollbackAddPlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
-					InventoryId: ss2.InventoryId,
-				})
-			}
+	// Stage 2: add all the items, the inventory adds all of them or none
+	res, err := u.sagaStep(pctx, cfg, order, payment.StepAddItems, "buy", func() error {
+		items := make([]*inventory.UpdateInventoryItem, 0, len(order.Items))
+		for _, v := range order.Items {
+			items = append(items, &inventory.UpdateInventoryItem{
+				ItemId:       v.ItemId,
+				ItemRevision: v.ItemRevision,
+			})
+		}
+		return u.paymentRepository.AddPlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
+			OrderId:  orderId,
+			PlayerId: playerId,
+			Items:    items,
+		})
+	})
+	if err == nil && len(res.InventoryIds) != len(order.Items) {
+		err = errors.New("error: add player items failed")
+	}
+	if err != nil {
+		u.paymentRepository.RollbackAddPlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+			OrderId:  orderId,
+			PlayerId: playerId,
+		})
+		if order.Total > 0 {
+			u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{OrderId: orderId})
+		}
+		u.releaseCoupon(pctx, redemption)
+		return nil, u.failOrder(pctx, order, err)
+	}
 
-			for _, ss2 := range stage2 {
-				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
-					TransactionId: ss2.TransactionId,
-				})
-			}
+	for i, v := range order.Items {
+		v.InventoryId = res.InventoryIds[i]
+	}
 
-			return nil, errors.New("error: buy item failed")
-		}
+	if err := u.completeOrder(pctx, order); err != nil {
+		return nil, err
 	}
+	u.redeemCoupon(pctx, redemption)
 
-	return stage2, nil
+	return orderResults(order), nil
 }
 
 func (u *paymentUsecase) SellItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) ([]*payment.PaymentTransferRes, error) {
+	order, err := u.prepareSell(pctx, cfg, playerId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.runSell(pctx, cfg, order)
+}
+
+// SellItemAsync checks the sell and returns the pending order, the saga runs after the return
+func (u *paymentUsecase) SellItemAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) (*payment.OrderRes, error) {
+	order, err := u.prepareSell(pctx, cfg, playerId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := order.ToRes()
+
+	u.goSaga(func(ctx context.Context) {
+		u.runSell(ctx, cfg, order)
+	})
+
+	return res, nil
+}
+
+// prepareSell checks the sell and inserts the pending order
+func (u *paymentUsecase) prepareSell(pctx context.Context, cfg *config.Config, playerId string, req *payment.ItemServiceReq) (*payment.Order, error) {
 	if err := u.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, req.Items); err != nil {
 		return nil, err
 	}
 
-	stage1 := make([]*payment.PaymentTransferRes, 0)
+	// Note that: the total of a sell is the half of the prices that is credited
+	total := 0.0
 	for _, item := range req.Items {
-		u.paymentRepository.RemovePlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
-			PlayerId: playerId,
-			ItemId:   item.ItemId,
-		})
+		total += item.Price
+	}
+
+	order, err := u.insertOrder(pctx, playerId, payment.OrderSell, req, math.Round(total*0.5*100)/100, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		resCh := make(chan *payment.PaymentTransferRes)
+	return order, nil
+}
 
-		go u.BuyOrSellConsumer(pctx, "sell", cfg, resCh)
+func (u *paymentUsecase) runSell(pctx context.Context, cfg *config.Config, order *payment.Order) ([]*payment.PaymentTransferRes, error) {
+	orderId := order.Id.Hex()
+	playerId := order.PlayerId
 
-		res := <-resCh
-		if res != nil {
-			log.Println(res)
-			stage1 = append(stage1, &payment.PaymentTransferRes{
-				InventoryId:   "",
-				TransactionId: "",
-				PlayerId:      playerId,
-				ItemId:        item.ItemId,
-				Amount:        item.Price,
-				Error:         res.Error,
+	// Stage 1: remove all the items, the inventory removes all of them or none
+	res, err := u.sagaStep(pctx, cfg, order, payment.StepRemoveItems, "sell", func() error {
+		items := make([]*inventory.UpdateInventoryItem, 0, len(order.Items))
+		for _, v := range order.Items {
+			items = append(items, &inventory.UpdateInventoryItem{
+				ItemId: v.ItemId,
 			})
 		}
+		return u.paymentRepository.RemovePlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
+			OrderId:  orderId,
+			PlayerId: playerId,
+			Items:    items,
+		})
+	})
+	if err != nil {
+		u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+			OrderId:  orderId,
+			PlayerId: playerId,
+		})
+		return nil, u.failOrder(pctx, order, err)
 	}
 
-	for _, s1 := range stage1 {
-		if s1.Error != "" {
-			for _, ss1 := range stage1 {
-				if ss1.Error != "error: item not found" {
-					u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
-						PlayerId: playerId,
-						ItemId:   ss1.ItemId,
-					})
-				}
-			}
-			return nil, errors.New("error: sell item failed")
+	// The sold inventory ids are kept so a refund of the buy of them does not credit them again,
+	// they are saved before the credit so a refund that starts meanwhile sees them
+	if len(res.InventoryIds) == len(order.Items) {
+		for i, v := range order.Items {
+			v.InventoryId = res.InventoryIds[i]
+		}
+		if err := u.saveOrder(pctx, order); errors.Is(err, paymentRepository.ErrOrderSettled) {
+			return nil, err
 		}
 	}
 
-	stage2 := make([]*payment.PaymentTransferRes, 0)
-	for _, s1 := range stage1 {
-		u.paymentRepository.AddPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
-			PlayerId: playerId,
-			Amount:   s1.Amount * 0.5,
+	// Stage 2: credit the total
+	if order.Total > 0 {
+		res, err := u.sagaStep(pctx, cfg, order, payment.StepCredit, "sell", func() error {
+			return u.paymentRepository.AddPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
+				OrderId:  orderId,
+				PlayerId: playerId,
+				Amount:   order.Total,
+			})
 		})
+		if err != nil {
+			u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{OrderId: orderId})
+			u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+				OrderId:  orderId,
+				PlayerId: playerId,
+			})
+			return nil, u.failOrder(pctx, order, err)
+		}
+		order.TransactionId = res.TransactionId
+	}
+
+	if err := u.completeOrder(pctx, order); err != nil {
+		return nil, err
+	}
 
-		resCh := make(chan *payment.PaymentTransferRes)
+	return orderResults(order), nil
+}
 
-		go u.BuyOrSellConsumer(pctx, "sell", cfg, resCh)
+var errStepTimeout = errors.New("error: step timed out")
 
-		res := <-resCh
-		if res != nil {
-			log.Println(res)
-			stage2 = append(stage2, &payment.PaymentTransferRes{
-				InventoryId:   "",
-				TransactionId: s1.TransactionId,
-				PlayerId:      playerId,
-				ItemId:        s1.ItemId,
-				Amount:        s1.Amount,
-				Error:         s1.Error,
-			})
-		}
+func stepTimeout(cfg *config.Config) time.Duration {
+	timeout := cfg.Saga.StepTimeout
+	if timeout <= 0 {
+		timeout = 10
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// sagaStep runs one step of the order, the progress of the step is saved before and after it.
+// Note that: an order that is settled already stops the saga, it was compensated by whom settled it
+func (u *paymentUsecase) sagaStep(pctx context.Context, cfg *config.Config, order *payment.Order, step, key string, push func() error) (*payment.PaymentTransferRes, error) {
+	progress := &payment.OrderStep{Name: step, Status: payment.StepStarted, At: utils.LocalTime()}
+	order.Step = step
+	order.Steps = append(order.Steps, progress)
+	if err := u.saveOrder(pctx, order); errors.Is(err, paymentRepository.ErrOrderSettled) {
+		return nil, err
+	}
+
+	res, err := u.stepReply(pctx, cfg, order.Id.Hex(), step, key, push)
+
+	progress.Status = payment.StepDone
+	if errors.Is(err, errStepTimeout) {
+		progress.Status = payment.StepTimedOut
+	} else if err != nil {
+		progress.Status = payment.StepFailed
+	}
+	progress.At = utils.LocalTime()
+	if err := u.saveOrder(pctx, order); errors.Is(err, paymentRepository.ErrOrderSettled) {
+		return nil, err
 	}
 
-	for _, s2 := range stage2 {
-		if s2.Error != "" {
-			for _, ss2 := range stage2 {
-				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{
-					TransactionId: ss2.TransactionId,
-				})
+	return res, err
+}
+
+// stepReply pushes the command of one step of the order and waits for the reply of the order until the step timeout,
+// the consumer starts before the command is pushed so the reply can not be missed
+func (u *paymentUsecase) stepReply(pctx context.Context, cfg *config.Config, orderId, step, key string, push func() error) (*payment.PaymentTransferRes, error) {
+	consumer, err := u.PaymentConsumer(pctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	if err := push(); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(stepTimeout(cfg))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			log.Printf("Error: %s step of order %s timed out", step, orderId)
+			return nil, errStepTimeout
+		case err := <-consumer.Errors():
+			log.Println("Error: sagaStep failed: ", err.Error())
+		case msg := <-consumer.Messages():
+			if string(msg.Key) != key {
+				continue
 			}
 
-			for _, ss2 := range stage2 {
-				if ss2.Error != "error: item not found" {
-					u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
-						InventoryId: ss2.InventoryId,
-					})
-				}
+			res := new(payment.PaymentTransferRes)
+			if err := queue.DecodeMessage(res, msg.Value); err != nil {
+				continue
+			}
+
+			// Note that: the reply of another order or the late reply of a step that timed out is skipped
+			if res.OrderId != orderId {
+				continue
 			}
+			u.UpserOffset(pctx, msg.Offset+1)
 
-			return nil, errors.New("error: sell item failed")
+			log.Printf("sagaStep | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+
+			if res.Error != "" {
+				return nil, errors.New(res.Error)
+			}
+			return res, nil
 		}
 	}
+}
+
+func (u *paymentUsecase) insertOrder(pctx context.Context, playerId, orderType string, req *payment.ItemServiceReq, total float64, redemption *payment.CouponRedemption) (*payment.Order, error) {
+	now := utils.LocalTime()
+
+	order := &payment.Order{
+		PlayerId:  playerId,
+		Type:      orderType,
+		Status:    payment.OrderPending,
+		Steps:     make([]*payment.OrderStep, 0),
+		Items:     make([]*payment.OrderItem, 0, len(req.Items)),
+		Total:     total,
+		Code:      payment.NormalizeCouponCode(req.Code),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if redemption != nil {
+		order.RedemptionId = redemption.Id.Hex()
+	}
+	for _, v := range req.Items {
+		order.Items = append(order.Items, &payment.OrderItem{
+			ItemId:       v.ItemId,
+			ItemRevision: v.Revision,
+			Price:        v.Price,
+			Discount:     v.Discount,
+		})
+	}
+
+	orderId, err := u.paymentRepository.InsertOneOrder(pctx, order)
+	if err != nil {
+		return nil, err
+	}
+	order.Id = orderId
 
-	return stage2, nil
+	return order, nil
 }
 
-func (u *paymentUsecase) FindItemsInIds(pctx context.Context, grpcUrl string, req []*payment.ItemServiceReqDatum) error {
-	setIds := make(map[string]bool)
-	for _, v := range req {
-		if !setIds[v.ItemId] {
-			setIds[v.ItemId] = true
-		}
+// failOrder saves the order after the compensation was sent, a step that timed out returns the order so it can be polled
+func (u *paymentUsecase) failOrder(pctx context.Context, order *payment.Order, err error) error {
+	u.settleFailedOrder(pctx, order, err)
+
+	if order.Status == payment.OrderTimedOut {
+		return &payment.OrderTimeoutError{Order: order.ToRes()}
 	}
+	return err
+}
 
-	itemData, err := u.paymentRepository.FindItemsInIds(pctx, grpcUrl, &itemPb.FindItemsInIdsReq{
-		Ids: func() []string {
-			itemIds := make([]string, 0)
-			for k := range setIds {
-				itemIds = append(itemIds, k)
+// settleFailedOrder saves the order as failed, ErrOrderSettled is returned when the order was settled already
+func (u *paymentUsecase) settleFailedOrder(pctx context.Context, order *payment.Order, err error) error {
+	order.Status = payment.OrderFailed
+	if errors.Is(err, errStepTimeout) {
+		order.Status = payment.OrderTimedOut
+	}
+	order.Error = err.Error()
+	order.Steps = append(order.Steps, &payment.OrderStep{Name: payment.StepCompensate, Status: payment.StepDone, At: utils.LocalTime()})
+	return u.saveOrder(pctx, order)
+}
+
+// completeOrder returns ErrOrderSettled when the order was failed while the saga ran, the order is compensated already
+func (u *paymentUsecase) completeOrder(pctx context.Context, order *payment.Order) error {
+	order.Status = payment.OrderCompleted
+	if err := u.saveOrder(pctx, order); errors.Is(err, paymentRepository.ErrOrderSettled) {
+		return err
+	}
+	return nil
+}
+
+// saveOrder saves a pending order only, the watchers are notified when it is saved
+func (u *paymentUsecase) saveOrder(pctx context.Context, order *payment.Order) error {
+	order.UpdatedAt = utils.LocalTime()
+	if err := u.paymentRepository.UpdateOneOrder(pctx, order); err != nil {
+		return err
+	}
+	u.notifyOrder(order.Id.Hex())
+	return nil
+}
+
+// WatchOrder returns a channel that is notified when the order is saved by this service, the order of another instance
+// is not notified so the watcher still reads the order every while. stop is called when the watch is done
+func (u *paymentUsecase) WatchOrder(orderId string) (<-chan struct{}, func()) {
+	changed := make(chan struct{}, 1)
+
+	u.mu.Lock()
+	u.watchers[orderId] = append(u.watchers[orderId], changed)
+	u.mu.Unlock()
+
+	stop := func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+
+		watchers := u.watchers[orderId]
+		for i, v := range watchers {
+			if v == changed {
+				watchers = append(watchers[:i], watchers[i+1:]...)
+				break
 			}
-			return itemIds
-		}(),
-	})
-	if err != nil {
-		log.Printf("Error: FindItemsInIds failed: %s", err.Error())
-		return errors.New("error: items not found")
+		}
+		if len(watchers) == 0 {
+			delete(u.watchers, orderId)
+			return
+		}
+		u.watchers[orderId] = watchers
 	}
 
-	itemMaps := make(map[string]*item.ItemShowCase)
-	for _, v := range itemData.Items {
-		itemMaps[v.Id] = &item.ItemShowCase{
-			ItemId:   v.Id,
-			Title:    v.Title,
-			Price:    v.Price,
-			ImageUrl: v.ImageUrl,
-			Damage:   int(v.Damage),
+	return changed, stop
+}
+
+// Note that: a watcher that was notified and did not read the order yet is not notified twice, the saga never waits for it
+func (u *paymentUsecase) notifyOrder(orderId string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, v := range u.watchers[orderId] {
+		select {
+		case v <- struct{}{}:
+		default:
 		}
 	}
+}
 
-	for i := range req {
-		if _, ok := itemMaps[req[i].ItemId]; !ok {
-			log.Printf("Error: FindItemsInIds failed: %s", err.Error())
-			return errors.New("error: items not found")
+// RecoverStaleOrders compensates the pending orders whose saga has stopped, e.g. the service was restarted while
+// an async saga was running. An order is stale when it was not saved for 3 step timeouts, the compensations are
+// by the order id so an order that is compensated twice is not undone twice.
+// Note that: the order is failed before it is compensated, an order that was settled after it was found is skipped
+// and the saga of an order that is failed here stops at its next save
+func (u *paymentUsecase) RecoverStaleOrders(pctx context.Context, cfg *config.Config) error {
+	orders, err := u.paymentRepository.FindManyStaleOrders(pctx, utils.LocalTime().Add(-3*stepTimeout(cfg)))
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		orderId := order.Id.Hex()
+		log.Printf("Recover stale %s order %s at %s step", order.Type, orderId, order.Step)
+
+		if err := u.settleFailedOrder(pctx, order, errors.New("error: saga of the order has stopped")); err != nil {
+			log.Printf("Error: RecoverStaleOrders failed: order %s is not recovered: %s", orderId, err.Error())
+			continue
+		}
+
+		switch order.Type {
+		case payment.OrderBuy:
+			u.paymentRepository.RollbackAddPlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+				OrderId:  orderId,
+				PlayerId: order.PlayerId,
+			})
+			if order.Total > 0 {
+				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{OrderId: orderId})
+			}
+			if order.RedemptionId != "" {
+				redemption, err := u.paymentRepository.FindOneCouponRedemption(pctx, order.RedemptionId)
+				if err == nil {
+					u.releaseCoupon(pctx, redemption)
+				}
+			}
+		case payment.OrderSell, payment.OrderRefund:
+			if order.Total > 0 {
+				u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{OrderId: orderId})
+			}
+			u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+				OrderId:  orderId,
+				PlayerId: order.PlayerId,
+			})
+			if order.Type == payment.OrderRefund {
+				u.paymentRepository.ReleaseOneOrderRefund(pctx, order.RefundOf, orderId)
+			}
 		}
-		req[i].Price = itemMaps[req[i].ItemId].Price
 	}
 
 	return nil
 }
+
+func orderResults(order *payment.Order) []*payment.PaymentTransferRes {
+	results := make([]*payment.PaymentTransferRes, 0, len(order.Items))
+	for _, v := range order.Items {
+		results = append(results, &payment.PaymentTransferRes{
+			OrderId:       order.Id.Hex(),
+			InventoryId:   v.InventoryId,
+			TransactionId: order.TransactionId,
+			PlayerId:      order.PlayerId,
+			ItemId:        v.ItemId,
+			ItemRevision:  v.ItemRevision,
+			Amount:        v.Price,
+			Discount:      v.Discount,
+			Error:         "",
+		})
+	}
+	return results
+}
+
+func (u *paymentUsecase) FindOrder(pctx context.Context, playerId, orderId string) (*payment.OrderRes, error) {
+	if _, err := primitive.ObjectIDFromHex(orderId); err != nil {
+		return nil, errors.New("error: order not found")
+	}
+
+	order, err := u.paymentRepository.FindOneOrder(pctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.PlayerId != playerId {
+		return nil, errors.New("error: order not found")
+	}
+
+	return order.ToRes(), nil
+}
+
+// RefundOrder refunds a completed buy, the items that are still in the inventory are removed and the amount is credited,
+// a buy has one refund, a refund that fails is compensated and the buy can be refunded again
+func (u *paymentUsecase) RefundOrder(pctx context.Context, cfg *config.Config, adminId, orderId string, req *payment.RefundOrderReq) (*payment.OrderRes, error) {
+	if _, err := primitive.ObjectIDFromHex(orderId); err != nil {
+		return nil, errors.New("error: order not found")
+	}
+
+	bought, err := u.paymentRepository.FindOneOrder(pctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if bought.Type != payment.OrderBuy || bought.Status != payment.OrderCompleted {
+		return nil, errors.New("error: only a completed buy can be refunded")
+	}
+	if bought.RefundId != "" {
+		return nil, errors.New("error: order is refunded already")
+	}
+
+	if req.Amount < 0 {
+		return nil, errors.New("error: amount is less than 0")
+	}
+
+	// Note that: the items that were sold were credited by the sell already, their price is taken off what can be refunded
+	inventoryIds := make([]string, 0, len(bought.Items))
+	for _, v := range bought.Items {
+		if v.InventoryId != "" {
+			inventoryIds = append(inventoryIds, v.InventoryId)
+		}
+	}
+	soldIds, err := u.paymentRepository.FindManySoldInventoryIds(pctx, bought.PlayerId, inventoryIds)
+	if err != nil {
+		return nil, err
+	}
+	sold := make(map[string]bool)
+	for _, v := range soldIds {
+		sold[v] = true
+	}
+
+	refundable := bought.Total
+	for _, v := range bought.Items {
+		if sold[v.InventoryId] {
+			refundable -= v.Price
+		}
+	}
+	refundable = math.Max(math.Round(refundable*100)/100, 0)
+	if refundable == 0 && len(soldIds) > 0 {
+		return nil, errors.New("error: items of the order were sold, nothing is left to refund")
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = refundable
+	}
+	amount = math.Round(amount*100) / 100
+	if amount > refundable {
+		if len(soldIds) > 0 {
+			return nil, fmt.Errorf("error: amount is more than the %.2f that is left after the sold items of the order", refundable)
+		}
+		return nil, fmt.Errorf("error: amount is more than the total %.2f of the order", bought.Total)
+	}
+
+	now := utils.LocalTime()
+	refund := &payment.Order{
+		PlayerId:   bought.PlayerId,
+		Type:       payment.OrderRefund,
+		Status:     payment.OrderPending,
+		Steps:      make([]*payment.OrderStep, 0),
+		Items:      make([]*payment.OrderItem, 0, len(bought.Items)),
+		Total:      amount,
+		RefundOf:   orderId,
+		RefundedBy: adminId,
+		Reason:     req.Reason,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	for _, v := range bought.Items {
+		item := *v
+		refund.Items = append(refund.Items, &item)
+	}
+
+	refundId, err := u.paymentRepository.InsertOneOrder(pctx, refund)
+	if err != nil {
+		return nil, err
+	}
+	refund.Id = refundId
+
+	// Note that: the claim is the check of a second refund, two refunds of the same buy can not both claim it
+	ok, err := u.paymentRepository.ClaimOneOrderRefund(pctx, orderId, refundId.Hex())
+	if err == nil && !ok {
+		err = errors.New("error: order is refunded already")
+	}
+	if err != nil {
+		refund.Status = payment.OrderFailed
+		refund.Error = err.Error()
+		u.saveOrder(pctx, refund)
+		return nil, err
+	}
+
+	fail := func(err error) error {
+		u.paymentRepository.ReleaseOneOrderRefund(pctx, orderId, refundId.Hex())
+		return u.failOrder(pctx, refund, err)
+	}
+
+	// Stage 1: remove the items that are still in the inventory, the items that were consumed are skipped
+	// and the items that were sold are not asked
+	res, err := u.sagaStep(pctx, cfg, refund, payment.StepRemoveItems, "refund", func() error {
+		items := make([]*inventory.UpdateInventoryItem, 0, len(refund.Items))
+		for _, v := range refund.Items {
+			if sold[v.InventoryId] {
+				continue
+			}
+			items = append(items, &inventory.UpdateInventoryItem{
+				InventoryId: v.InventoryId,
+				ItemId:      v.ItemId,
+			})
+		}
+		return u.paymentRepository.RefundPlayerItem(pctx, cfg, &inventory.UpdateInventoryReq{
+			OrderId:  refundId.Hex(),
+			PlayerId: refund.PlayerId,
+			Items:    items,
+		})
+	})
+	if err != nil {
+		u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+			OrderId:  refundId.Hex(),
+			PlayerId: refund.PlayerId,
+		})
+		return nil, fail(err)
+	}
+
+	removed := make(map[string]bool)
+	for _, v := range res.InventoryIds {
+		removed[v] = true
+	}
+	for _, v := range refund.Items {
+		v.IsConsumed = !removed[v.InventoryId]
+	}
+
+	// Stage 2: credit the amount of the refund
+	if refund.Total > 0 {
+		res, err := u.sagaStep(pctx, cfg, refund, payment.StepCredit, "sell", func() error {
+			return u.paymentRepository.AddPlayerMoney(pctx, cfg, &player.CreatePlayerTransactionReq{
+				OrderId:  refundId.Hex(),
+				PlayerId: refund.PlayerId,
+				Amount:   refund.Total,
+			})
+		})
+		if err != nil {
+			u.paymentRepository.RollbackTransaction(pctx, cfg, &player.RollbackPlayerTransactionReq{OrderId: refundId.Hex()})
+			u.paymentRepository.RollbackRemovePlayerItem(pctx, cfg, &inventory.RollbackPlayerInventoryReq{
+				OrderId:  refundId.Hex(),
+				PlayerId: refund.PlayerId,
+			})
+			return nil, fail(err)
+		}
+		refund.TransactionId = res.TransactionId
+	}
+
+	if err := u.completeOrder(pctx, refund); err != nil {
+		return nil, err
+	}
+
+	return refund.ToRes(), nil
+}
+
+func (u *paymentUsecase) FindItemsInIds(pctx context.Context, grpcUrl string, req []*payment.ItemServiceReqDatum) error {
+	setIds := make(map[string]bool)
+	for _, v := range req {
+		if !setIds[v.ItemId] {
+			setIds[v.ItemId] = true
+		}
+	}
+
+	itemData, err := u.paymentRepository.FindItemsInIds(pctx, grpcUrl, &itemPb.FindItemsInIdsReq{
+		Ids: func() []string {
+			itemIds := make([]string, 0)
+			for k := range setIds {
+				itemIds = append(itemIds, k)
+			}
+			return itemIds
+		}(),
+	})
+	if err != nil {
+		log.Printf("Error: FindItemsInIds failed: %s", err.Error())
+		return errors.New("error: items not found")
+	}
+
+	itemMaps := make(map[string]*item.ItemShowCase)
+	for _, v := range itemData.Items {
+		itemMaps[v.Id] = &item.ItemShowCase{
+			ItemId:   v.Id,
+			Title:    v.Title,
+			Price:    v.Price,
+			ImageUrl: v.ImageUrl,
+			Damage:   int(v.Damage),
+			Revision: int(v.Revision),
+		}
+	}
+
+	for i := range req {
+		if _, ok := itemMaps[req[i].ItemId]; !ok {
+			log.Printf("Error: FindItemsInIds failed: item %s not found", req[i].ItemId)
+			return errors.New("error: items not found")
+		}
+		req[i].Price = itemMaps[req[i].ItemId].Price
+		req[i].Revision = itemMaps[req[i].ItemId].Revision
+	}
+
+	return nil
+}
+
+// The use of the player is taken first, it is given back when the coupon itself has no use left
+func (u *paymentUsecase) reserveCoupon(pctx context.Context, coupon *payment.Coupon, playerId string, discount float64) (*payment.CouponRedemption, error) {
+	couponId := coupon.Id.Hex()
+
+	if err := u.paymentRepository.ReserveCouponPlayerUse(pctx, couponId, playerId, coupon.MaxUsesPerPlayer); err != nil {
+		return nil, err
+	}
+	if err := u.paymentRepository.ReserveCouponUse(pctx, couponId, utils.LocalTime()); err != nil {
+		u.paymentRepository.ReleaseCouponPlayerUse(pctx, couponId, playerId)
+		return nil, err
+	}
+
+	redemption := &payment.CouponRedemption{
+		CouponId:  couponId,
+		Code:      coupon.Code,
+		PlayerId:  playerId,
+		Status:    payment.RedemptionReserved,
+		Discount:  discount,
+		CreatedAt: utils.LocalTime(),
+		UpdatedAt: utils.LocalTime(),
+	}
+
+	redemptionId, err := u.paymentRepository.InsertOneCouponRedemption(pctx, redemption)
+	if err != nil {
+		u.paymentRepository.ReleaseCouponUse(pctx, couponId)
+		u.paymentRepository.ReleaseCouponPlayerUse(pctx, couponId, playerId)
+		return nil, err
+	}
+	redemption.Id = redemptionId
+
+	return redemption, nil
+}
+
+// Note that: the uses are only given back by the call that moves the redemption out of reserved, so never twice
+func (u *paymentUsecase) releaseCoupon(pctx context.Context, redemption *payment.CouponRedemption) {
+	if redemption == nil {
+		return
+	}
+
+	ok, err := u.paymentRepository.UpdateOneCouponRedemptionStatus(pctx, redemption.Id, payment.RedemptionReserved, payment.RedemptionReleased)
+	if err != nil || !ok {
+		log.Printf("Error: releaseCoupon failed: redemption %s is not released", redemption.Id.Hex())
+		return
+	}
+
+	u.paymentRepository.ReleaseCouponUse(pctx, redemption.CouponId)
+	u.paymentRepository.ReleaseCouponPlayerUse(pctx, redemption.CouponId, redemption.PlayerId)
+}
+
+func (u *paymentUsecase) redeemCoupon(pctx context.Context, redemption *payment.CouponRedemption) {
+	if redemption == nil {
+		return
+	}
+
+	if _, err := u.paymentRepository.UpdateOneCouponRedemptionStatus(pctx, redemption.Id, payment.RedemptionReserved, payment.RedemptionRedeemed); err != nil {
+		log.Printf("Error: redeemCoupon failed: redemption %s is not redeemed", redemption.Id.Hex())
+	}
+}
+
+func (u *paymentUsecase) CreateCoupon(pctx context.Context, playerId string, req *payment.CreateCouponReq) (*payment.Coupon, error) {
+	code := payment.NormalizeCouponCode(req.Code)
+	if !payment.IsValidCouponCode(code) {
+		return nil, errors.New("error: code must be 3 to 32 letters, digits, _ or -")
+	}
+
+	itemId := ""
+	switch req.BenefitType {
+	case payment.BenefitPercent:
+		if req.Value <= 0 || req.Value > 100 {
+			return nil, errors.New("error: value of a percent coupon must be between 1 and 100")
+		}
+	case payment.BenefitFixed:
+		if req.Value <= 0 || math.IsInf(req.Value, 0) {
+			return nil, errors.New("error: value of a fixed coupon must be more than 0")
+		}
+	case payment.BenefitFreeItem:
+		id := strings.TrimPrefix(req.ItemId, "item:")
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			return nil, errors.New("error: item_id of a free_item coupon is invalid")
+		}
+		itemId = "item:" + id
+	default:
+		return nil, errors.New("error: benefit_type must be percent, fixed or free_item")
+	}
+
+	if req.MinSpend < 0 || math.IsNaN(req.MinSpend) || math.IsInf(req.MinSpend, 0) {
+		return nil, errors.New("error: min_spend must not be negative")
+	}
+	if req.MaxUses < 0 || req.MaxUsesPerPlayer < 0 {
+		return nil, errors.New("error: max_uses and max_uses_per_player must not be negative")
+	}
+	if !req.ExpiresAt.After(utils.LocalTime()) {
+		return nil, errors.New("error: expires_at must be in the future")
+	}
+
+	result := &payment.Coupon{
+		Code:             code,
+		BenefitType:      req.BenefitType,
+		Value:            req.Value,
+		ItemId:           itemId,
+		MinSpend:         req.MinSpend,
+		MaxUses:          req.MaxUses,
+		MaxUsesPerPlayer: req.MaxUsesPerPlayer,
+		ExpiresAt:        req.ExpiresAt,
+		IsActive:         true,
+		PlayerId:         playerId,
+		CreatedAt:        utils.LocalTime(),
+		UpdatedAt:        utils.LocalTime(),
+	}
+	if result.BenefitType == payment.BenefitFreeItem {
+		result.Value = 0
+	}
+
+	couponId, err := u.paymentRepository.InsertOneCoupon(pctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Id = couponId
+
+	return result, nil
+}
+
+var couponSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+	{Name: "code", Field: "code", Kind: models.SortKindString},
+	{Name: "expires_at", Field: "expires_at", Kind: models.SortKindTime},
+}
+
+func (u *paymentUsecase) FindCoupons(pctx context.Context, cfg *config.Config, req *payment.CouponSearchReq) (*models.PaginateRes, error) {
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "coupons", &req.PaginateReq, couponSorts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter
+	filter := bson.D{}
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
+	}
+
+	// Find
+	results, err := u.paymentRepository.FindManyCoupons(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, prev := models.Page(paginate, results, func(v *payment.Coupon) (any, primitive.ObjectID) {
+		switch paginate.Sort.Name {
+		case "code":
+			return v.Code, v.Id
+		case "expires_at":
+			return v.ExpiresAt, v.Id
+		}
+		return v.Id, v.Id
+	})
+
+	// Count
+	total, err := u.paymentRepository.CountCoupons(pctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate.NewPaginateRes(results, total, cfg.Paginate.PaymentNextPageBasedUrl+"/coupons", paginate.Query(), next, prev), nil
+}
+
+func (u *paymentUsecase) EnableOrDisableCoupon(pctx context.Context, couponId string) (bool, error) {
+	result, err := u.paymentRepository.FindOneCoupon(pctx, couponId)
+	if err != nil {
+		return false, err
+	}
+
+	if err := u.paymentRepository.UpdateOneCouponIsActive(pctx, couponId, !result.IsActive); err != nil {
+		return false, err
+	}
+
+	return !result.IsActive, nil
+}
+
+const maxCartItems = 50
+
+// FindCart returns the cart with the current prices, the items that are disabled or deleted are taken out of the saved cart
+func (u *paymentUsecase) FindCart(pctx context.Context, cfg *config.Config, playerId string) (*payment.CartRes, error) {
+	cart, err := u.paymentRepository.FindOneCart(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &payment.CartRes{
+		PlayerId: playerId,
+		Items:    make([]*payment.CartItemRes, 0),
+		Removed:  make([]string, 0),
+	}
+	if len(cart.Items) == 0 {
+		return result, nil
+	}
+
+	itemIds := make([]string, 0)
+	for _, v := range cart.Items {
+		itemIds = append(itemIds, v.ItemId)
+	}
+
+	itemData, err := u.paymentRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: itemIds})
+	if err != nil {
+		return nil, err
+	}
+
+	itemMaps := make(map[string]*itemPb.Item)
+	for _, v := range itemData.Items {
+		itemMaps[v.Id] = v
+	}
+
+	available := make([]*payment.CartItem, 0)
+	for _, v := range cart.Items {
+		data, ok := itemMaps[v.ItemId]
+		if !ok {
+			result.Removed = append(result.Removed, v.ItemId)
+			continue
+		}
+		available = append(available, v)
+
+		subtotal := math.Round(data.Price*float64(v.Quantity)*100) / 100
+		result.Items = append(result.Items, &payment.CartItemRes{
+			ItemId:   v.ItemId,
+			Title:    data.Title,
+			Price:    data.Price,
+			ImageUrl: data.ImageUrl,
+			Quantity: v.Quantity,
+			Subtotal: subtotal,
+		})
+		result.Total += subtotal
+	}
+	result.Total = math.Round(result.Total*100) / 100
+
+	if len(result.Removed) > 0 {
+		cart.Items = available
+		cart.UpdatedAt = utils.LocalTime()
+		if err := u.paymentRepository.SaveOneCart(pctx, cart); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (u *paymentUsecase) AddCartItem(pctx context.Context, cfg *config.Config, playerId string, req *payment.AddCartItemReq) (*payment.CartRes, error) {
+	id := strings.TrimPrefix(req.ItemId, "item:")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, errors.New("error: item_id is invalid")
+	}
+	itemId := "item:" + id
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	itemData, err := u.paymentRepository.FindItemsInIds(pctx, cfg.Grpc.ItemUrl, &itemPb.FindItemsInIdsReq{Ids: []string{itemId}})
+	if err != nil {
+		return nil, err
+	}
+	if len(itemData.Items) == 0 {
+		return nil, errors.New("error: item is not available")
+	}
+
+	cart, err := u.paymentRepository.FindOneCart(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	isFound := false
+	for _, v := range cart.Items {
+		if v.ItemId == itemId {
+			if v.Quantity+quantity > 99 {
+				return nil, errors.New("error: quantity of an item in the cart must not be more than 99")
+			}
+			v.Quantity += quantity
+			isFound = true
+			break
+		}
+	}
+	if !isFound {
+		if len(cart.Items) >= maxCartItems {
+			return nil, fmt.Errorf("error: cart must not have more than %d items", maxCartItems)
+		}
+		cart.Items = append(cart.Items, &payment.CartItem{
+			ItemId:   itemId,
+			Quantity: quantity,
+			AddedAt:  utils.LocalTime(),
+		})
+	}
+
+	cart.UpdatedAt = utils.LocalTime()
+	if err := u.paymentRepository.SaveOneCart(pctx, cart); err != nil {
+		return nil, err
+	}
+
+	return u.FindCart(pctx, cfg, playerId)
+}
+
+func (u *paymentUsecase) RemoveCartItem(pctx context.Context, cfg *config.Config, playerId, itemId string) (*payment.CartRes, error) {
+	itemId = "item:" + strings.TrimPrefix(itemId, "item:")
+
+	cart, err := u.paymentRepository.FindOneCart(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*payment.CartItem, 0)
+	for _, v := range cart.Items {
+		if v.ItemId != itemId {
+			items = append(items, v)
+		}
+	}
+	if len(items) == len(cart.Items) {
+		return nil, errors.New("error: item is not in the cart")
+	}
+
+	cart.Items = items
+	cart.UpdatedAt = utils.LocalTime()
+	if err := u.paymentRepository.SaveOneCart(pctx, cart); err != nil {
+		return nil, err
+	}
+
+	return u.FindCart(pctx, cfg, playerId)
+}
+
+func (u *paymentUsecase) ClearCart(pctx context.Context, playerId string) error {
+	cart, err := u.paymentRepository.FindOneCart(pctx, playerId)
+	if err != nil {
+		return err
+	}
+	if len(cart.Items) == 0 {
+		return nil
+	}
+
+	cart.Items = make([]*payment.CartItem, 0)
+	cart.UpdatedAt = utils.LocalTime()
+	return u.paymentRepository.SaveOneCart(pctx, cart)
+}
+
+// Note that: the cart is bought by the same saga as BuyItem, the player checks the cart again when an item was taken out of it,
+// the bought items are taken out of the cart only when the buy completes
+func (u *paymentUsecase) CheckoutCart(pctx context.Context, cfg *config.Config, playerId string, req *payment.CartCheckoutReq) ([]*payment.PaymentTransferRes, error) {
+	buyReq, bought, claim, err := u.prepareCheckout(pctx, cfg, playerId, req)
+	if err != nil {
+		return nil, err
+	}
+	defer u.releaseCart(pctx, playerId, claim)
+
+	res, err := u.BuyItem(pctx, cfg, playerId, buyReq)
+	if err != nil {
+		return nil, err
+	}
+	u.takeOutOfCart(pctx, playerId, bought)
+
+	return res, nil
+}
+
+// CheckoutCartAsync checks the buy of the cart and returns the pending order, the items are taken out of the cart
+// when the saga completes
+func (u *paymentUsecase) CheckoutCartAsync(pctx context.Context, cfg *config.Config, playerId string, req *payment.CartCheckoutReq) (*payment.OrderRes, error) {
+	buyReq, bought, claim, err := u.prepareCheckout(pctx, cfg, playerId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	order, redemption, err := u.prepareBuy(pctx, cfg, playerId, buyReq)
+	if err != nil {
+		u.releaseCart(pctx, playerId, claim)
+		return nil, err
+	}
+
+	res := order.ToRes()
+
+	u.goSaga(func(ctx context.Context) {
+		defer u.releaseCart(ctx, playerId, claim)
+
+		if _, err := u.runBuy(ctx, cfg, order, redemption); err != nil {
+			return
+		}
+		u.takeOutOfCart(ctx, playerId, bought)
+	})
+
+	return res, nil
+}
+
+// A claim that is older than this is left by a checkout that has stopped, e.g. the service was restarted
+const cartClaimTimeout = 10 * time.Minute
+
+// prepareCheckout claims the cart and makes the buy of the claimed items, bought is the quantity of every item.
+// The claim is released by the caller when the buy ends, a checkout that fails here releases it
+func (u *paymentUsecase) prepareCheckout(pctx context.Context, cfg *config.Config, playerId string, req *payment.CartCheckoutReq) (*payment.ItemServiceReq, map[string]int, string, error) {
+	current, err := u.paymentRepository.FindOneCart(pctx, playerId)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(current.Items) == 0 {
+		return nil, nil, "", errors.New("error: cart is empty")
+	}
+
+	now := utils.LocalTime()
+	claim := primitive.NewObjectID().Hex()
+	cart, err := u.paymentRepository.ClaimOneCart(pctx, playerId, current.Version, claim, now, now.Add(-cartClaimTimeout))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// The items that are not available anymore are taken out by FindCart, the player checks the cart again
+	checked, err := u.FindCart(pctx, cfg, playerId)
+	if err != nil {
+		u.releaseCart(pctx, playerId, claim)
+		return nil, nil, "", err
+	}
+	if len(checked.Removed) > 0 {
+		u.releaseCart(pctx, playerId, claim)
+		return nil, nil, "", fmt.Errorf("error: items %s are not available anymore, the cart is updated", strings.Join(checked.Removed, ", "))
+	}
+
+	buyReq := &payment.ItemServiceReq{
+		Items: make([]*payment.ItemServiceReqDatum, 0),
+		Code:  req.Code,
+	}
+	bought := make(map[string]int)
+	for _, v := range cart.Items {
+		for i := 0; i < v.Quantity; i++ {
+			buyReq.Items = append(buyReq.Items, &payment.ItemServiceReqDatum{ItemId: v.ItemId})
+		}
+		bought[v.ItemId] = v.Quantity
+	}
+
+	return buyReq, bought, claim, nil
+}
+
+func (u *paymentUsecase) releaseCart(pctx context.Context, playerId, claim string) {
+	if err := u.paymentRepository.ReleaseOneCart(pctx, playerId, claim); err != nil {
+		log.Printf("Error: CheckoutCart failed: cart of %s is not released: %s", playerId, err.Error())
+	}
+}
+
+// The cart is read again, the items that were added while the buy was running are kept
+func (u *paymentUsecase) takeOutOfCart(pctx context.Context, playerId string, bought map[string]int) {
+	saved, err := u.paymentRepository.FindOneCart(pctx, playerId)
+	if err != nil {
+		log.Printf("Error: CheckoutCart failed: cart of %s is not updated: %s", playerId, err.Error())
+		return
+	}
+	items := make([]*payment.CartItem, 0)
+	for _, v := range saved.Items {
+		v.Quantity -= bought[v.ItemId]
+		if v.Quantity > 0 {
+			items = append(items, v)
+		}
+	}
+	saved.Items = items
+	saved.UpdatedAt = utils.LocalTime()
+	if err := u.paymentRepository.SaveOneCart(pctx, saved); err != nil {
+		log.Printf("Error: CheckoutCart failed: cart of %s is not updated: %s", playerId, err.Error())
+	}
+}
+
+func (u *paymentUsecase) FindRiskRule(pctx context.Context, cfg *config.Config, playerId string) (*payment.RiskRuleRes, error) {
+	playerId = "player:" + strings.TrimPrefix(playerId, "player:")
+
+	rule, err := u.paymentRepository.FindOneRiskRule(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment.RiskRuleRes{
+		PlayerId: playerId,
+		Rule:     rule,
+		Limits:   rule.Limits(riskDefaults(cfg)),
+	}, nil
+}
+
+func (u *paymentUsecase) UpsertRiskRule(pctx context.Context, cfg *config.Config, adminId, playerId string, req *payment.UpsertRiskRuleReq) (*payment.RiskRuleRes, error) {
+	playerId = "player:" + strings.TrimPrefix(playerId, "player:")
+	if _, err := primitive.ObjectIDFromHex(strings.TrimPrefix(playerId, "player:")); err != nil {
+		return nil, errors.New("error: player_id is invalid")
+	}
+
+	if err := u.paymentRepository.UpsertOneRiskRule(pctx, &payment.RiskRule{
+		PlayerId:           playerId,
+		DailySpendCap:      req.DailySpendCap,
+		MaxOrderValue:      req.MaxOrderValue,
+		MaxOrdersPerMinute: req.MaxOrdersPerMinute,
+		AllowNewAccount:    req.AllowNewAccount,
+		UpdatedBy:          adminId,
+		UpdatedAt:          utils.LocalTime(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return u.FindRiskRule(pctx, cfg, playerId)
+}
+
+var riskDecisionSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+}
+
+func (u *paymentUsecase) FindRiskDecisions(pctx context.Context, cfg *config.Config, req *payment.RiskDecisionSearchReq) (*models.PaginateRes, error) {
+	// The newest decision is the first unless the direction is set
+	if req.Direction == "" {
+		req.Direction = "desc"
+	}
+	if req.PlayerId != "" {
+		req.PlayerId = "player:" + strings.TrimPrefix(req.PlayerId, "player:")
+	}
+
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "risk_decisions:"+req.PlayerId, &req.PaginateReq, riskDecisionSorts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := paginate.Query()
+
+	// Filter
+	filter := bson.D{}
+	if req.PlayerId != "" {
+		filter = append(filter, bson.E{"player_id", req.PlayerId})
+		query.Set("player_id", req.PlayerId)
+	}
+
+	countFilter := append(bson.D{}, filter...)
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
+	}
+
+	// Find
+	results, err := u.paymentRepository.FindManyRiskDecisions(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, prev := models.Page(paginate, results, func(v *payment.RiskDecision) (any, primitive.ObjectID) {
+		return v.Id, v.Id
+	})
+
+	// Count
+	total, err := u.paymentRepository.CountRiskDecisions(pctx, countFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate.NewPaginateRes(results, total, cfg.Paginate.PaymentNextPageBasedUrl+"/risk/decisions", query, next, prev), nil
+}
@@ -12,9 +12,24 @@ type (
 		Email       string             `json:"email" bson:"email"`
 		Password    string             `json:"password" bson:"password"`
 		Username    string             `json:"username" bson:"username"`
+		DisplayName string             `json:"display_name" bson:"display_name"`
+		AvatarUrl   string             `json:"avatar_url" bson:"avatar_url"`
+		AvatarKey   string             `json:"-" bson:"avatar_key"`
 		CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
 		UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+		IsVerified  bool               `json:"is_verified" bson:"is_verified"`
+		IsDeleted   bool               `json:"is_deleted" bson:"is_deleted"`
+		DeletedAt   time.Time          `json:"deleted_at" bson:"deleted_at,omitempty"`
 		PlayerRoles []PlayerRole       `bson:"player_roles"`
+		Identities  []PlayerIdentity   `bson:"identities,omitempty"`
+	}
+
+	// External identity from an OIDC provider
+	PlayerIdentity struct {
+		Provider string    `json:"provider" bson:"provider"`
+		Subject  string    `json:"subject" bson:"subject"`
+		Email    string    `json:"email" bson:"email"`
+		LinkedAt time.Time `json:"linked_at" bson:"linked_at"`
 	}
 
 	PlayerRole struct {
@@ -23,11 +38,15 @@ type (
 	}
 
 	PlayerProfileBson struct {
-		Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
-		Email     string             `json:"email" bson:"email"`
-		Username  string             `json:"username" bson:"username"`
-		CreatedAt time.Time          `json:"created_at" bson:"created_at"`
-		UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+		Id          primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		Email       string             `json:"email" bson:"email"`
+		Username    string             `json:"username" bson:"username"`
+		DisplayName string             `json:"display_name" bson:"display_name"`
+		AvatarUrl   string             `json:"avatar_url" bson:"avatar_url"`
+		IsVerified  bool               `json:"is_verified" bson:"is_verified"`
+		IsDeleted   bool               `json:"is_deleted" bson:"is_deleted"`
+		CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
 	}
 
 	PlayerSavingAccount struct {
@@ -37,8 +56,60 @@ type (
 
 	PlayerTransaction struct {
 		Id        primitive.ObjectID `bson:"_id,omitempty"`
+		OrderId   string             `bson:"order_id,omitempty"`
 		PlayerId  string             `bson:"player_id"`
 		Amount    float64            `bson:"amount"`
 		CreatedAt time.Time          `bson:"created_at"`
 	}
+
+	// Note that: a rollback is kept by the order, a transaction of the order that comes after it undoes itself
+	PlayerTransactionRollback struct {
+		OrderId   string    `bson:"_id"`
+		CreatedAt time.Time `bson:"created_at"`
+	}
+
+	PasswordReset struct {
+		Id        primitive.ObjectID `bson:"_id,omitempty"`
+		PlayerId  string             `bson:"player_id"`
+		TokenHash string             `bson:"token_hash"`
+		IsUsed    bool               `bson:"is_used"`
+		ExpiresAt time.Time          `bson:"expires_at"`
+		CreatedAt time.Time          `bson:"created_at"`
+		UsedAt    time.Time          `bson:"used_at,omitempty"`
+	}
+
+	EmailVerification struct {
+		Id        primitive.ObjectID `bson:"_id,omitempty"`
+		PlayerId  string             `bson:"player_id"`
+		Email     string             `bson:"email"`
+		TokenHash string             `bson:"token_hash"`
+		IsUsed    bool               `bson:"is_used"`
+		ExpiresAt time.Time          `bson:"expires_at"`
+		CreatedAt time.Time          `bson:"created_at"`
+		UsedAt    time.Time          `bson:"used_at,omitempty"`
+	}
+
+	// Note that: a player has an item in the wishlist once, it is unique by the player and the item
+	PlayerWishlist struct {
+		Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId  string             `json:"player_id" bson:"player_id"`
+		ItemId    string             `json:"item_id" bson:"item_id"`
+		CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	}
+
+	// Note that: a notification is unique by the player and the alert, so an alert that is consumed again is not notified twice
+	PlayerNotification struct {
+		Id            primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+		PlayerId      string             `json:"player_id" bson:"player_id"`
+		AlertId       string             `json:"alert_id" bson:"alert_id"`
+		Type          string             `json:"type" bson:"type"`
+		ItemId        string             `json:"item_id" bson:"item_id"`
+		Title         string             `json:"title" bson:"title"`
+		Message       string             `json:"message" bson:"message"`
+		Price         float64            `json:"price" bson:"price"`
+		PreviousPrice float64            `json:"previous_price,omitempty" bson:"previous_price,omitempty"`
+		IsRead        bool               `json:"is_read" bson:"is_read"`
+		ReadAt        *time.Time         `json:"read_at,omitempty" bson:"read_at,omitempty"`
+		CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	}
 )
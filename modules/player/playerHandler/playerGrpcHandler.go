@@ -31,3 +31,19 @@ func (g *playerGrpcHandler) FindOnePlayerProfileToRefresh(ctx context.Context, r
 func (g *playerGrpcHandler) GetPlayerSavingAccount(ctx context.Context, req *playerPb.GetPlayerSavingAccountReq) (*playerPb.GetPlayerSavingAccountRes, error) {
 	return nil, nil
 }
+
+func (g *playerGrpcHandler) FindPlayerRoles(ctx context.Context, req *playerPb.FindPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	return g.playerUsecase.FindPlayerRoles(ctx, req.PlayerId)
+}
+
+func (g *playerGrpcHandler) AddPlayerRoles(ctx context.Context, req *playerPb.AddPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	return g.playerUsecase.AddPlayerRoles(ctx, req)
+}
+
+func (g *playerGrpcHandler) RemovePlayerRoles(ctx context.Context, req *playerPb.RemovePlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	return g.playerUsecase.RemovePlayerRoles(ctx, req)
+}
+
+func (g *playerGrpcHandler) FindOrCreatePlayerByIdentity(ctx context.Context, req *playerPb.FindOrCreatePlayerByIdentityReq) (*playerPb.PlayerProfile, error) {
+	return g.playerUsecase.FindOrCreatePlayerByIdentity(ctx, req)
+}
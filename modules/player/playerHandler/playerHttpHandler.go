@@ -2,6 +2,7 @@ package playerHandler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -19,6 +20,23 @@ type (
 		FindOnePlayerProfile(c echo.Context) error
 		AddPlayerMoney(c echo.Context) error
 		GetPlayerSavingAccount(c echo.Context) error
+		VerifyEmail(c echo.Context) error
+		ResendEmailVerification(c echo.Context) error
+		UpdatePlayerProfile(c echo.Context) error
+		ChangeEmail(c echo.Context) error
+		UploadAvatar(c echo.Context) error
+		DeletePlayer(c echo.Context) error
+		ChangePassword(c echo.Context) error
+		ForgotPassword(c echo.Context) error
+		ResetPassword(c echo.Context) error
+		SearchPlayers(c echo.Context) error
+		FindPlayerOverview(c echo.Context) error
+		AddWishlistItem(c echo.Context) error
+		FindWishlist(c echo.Context) error
+		RemoveWishlistItem(c echo.Context) error
+		FindNotifications(c echo.Context) error
+		ReadNotification(c echo.Context) error
+		ReadAllNotifications(c echo.Context) error
 	}
 
 	playerHttpHandler struct {
@@ -28,7 +46,7 @@ type (
 )
 
 func NewPlayerHttpHandler(cfg *config.Config, playerUsecase playerUsecase.PlayerUsecaseService) PlayerHttpHandlerService {
-	return &playerHttpHandler{playerUsecase: playerUsecase}
+	return &playerHttpHandler{cfg: cfg, playerUsecase: playerUsecase}
 }
 
 func (h *playerHttpHandler) CreatePlayer(c echo.Context) error {
@@ -42,7 +60,7 @@ func (h *playerHttpHandler) CreatePlayer(c echo.Context) error {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	res, err := h.playerUsecase.CreatePlayer(ctx, req)
+	res, err := h.playerUsecase.CreatePlayer(ctx, h.cfg, req)
 	if err != nil {
 		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
 	}
@@ -95,3 +113,314 @@ func (h *playerHttpHandler) GetPlayerSavingAccount(c echo.Context) error {
 
 	return response.SuccessResponse(c, http.StatusBadRequest, res)
 }
+
+func (h *playerHttpHandler) VerifyEmail(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.VerifyEmailReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.playerUsecase.VerifyEmail(ctx, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Email is verified",
+	})
+}
+
+func (h *playerHttpHandler) ResendEmailVerification(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.ResendEmailVerificationReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.playerUsecase.ResendEmailVerification(ctx, h.cfg, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "If the email is registered and not verified, a verification email has been sent",
+	})
+}
+
+func (h *playerHttpHandler) UpdatePlayerProfile(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.UpdatePlayerProfileReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	res, err := h.playerUsecase.UpdatePlayerProfile(ctx, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) ChangeEmail(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.ChangeEmailReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	res, err := h.playerUsecase.ChangeEmail(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) UploadAvatar(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := c.Get("player_id").(string)
+
+	// Note that: the body is limited before it is parsed, the usecase checks the size of the file itself
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, h.cfg.Blob.MaxAvatarSize+(1<<20))
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: avatar is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, "error: read avatar failed")
+	}
+	defer file.Close()
+
+	res, err := h.playerUsecase.UploadAvatar(ctx, h.cfg, playerId, file)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) DeletePlayer(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.DeletePlayerReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	if err := h.playerUsecase.DeletePlayer(ctx, h.cfg, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Account is deleted",
+	})
+}
+
+func (h *playerHttpHandler) ChangePassword(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.ChangePasswordReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+	req.PlayerId = c.Get("player_id").(string)
+
+	if err := h.playerUsecase.ChangePassword(ctx, h.cfg, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Password is changed, please login again",
+	})
+}
+
+func (h *playerHttpHandler) ForgotPassword(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.ForgotPasswordReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.playerUsecase.ForgotPassword(ctx, h.cfg, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "If the email is registered, a reset token has been sent",
+	})
+}
+
+func (h *playerHttpHandler) ResetPassword(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.ResetPasswordReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.playerUsecase.ResetPassword(ctx, h.cfg, req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, &response.MsgResponse{
+		Message: "Password is reset, please login again",
+	})
+}
+
+func (h *playerHttpHandler) SearchPlayers(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.PlayerSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.playerUsecase.SearchPlayers(ctx, h.cfg, req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) FindPlayerOverview(c echo.Context) error {
+	ctx := context.Background()
+
+	playerId := strings.TrimPrefix(c.Param("player_id"), "player:")
+
+	res, err := h.playerUsecase.FindPlayerOverview(ctx, h.cfg, playerId)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) AddWishlistItem(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.AddWishlistItemReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.playerUsecase.AddWishlistItem(ctx, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, res)
+}
+
+func (h *playerHttpHandler) FindWishlist(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.playerUsecase.FindWishlist(ctx, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) RemoveWishlistItem(c echo.Context) error {
+	ctx := context.Background()
+
+	itemId := c.Param("item_id")
+
+	if err := h.playerUsecase.RemoveWishlistItem(ctx, c.Get("player_id").(string), itemId); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"message": fmt.Sprintf("item_id: %s is removed from the wishlist", itemId),
+	})
+}
+
+func (h *playerHttpHandler) FindNotifications(c echo.Context) error {
+	ctx := context.Background()
+
+	wrapper := request.ContextWrapper(c)
+
+	req := new(player.NotificationSearchReq)
+
+	if err := wrapper.Bind(req); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := h.playerUsecase.FindNotifications(ctx, h.cfg, c.Get("player_id").(string), req)
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *playerHttpHandler) ReadNotification(c echo.Context) error {
+	ctx := context.Background()
+
+	notificationId := c.Param("notification_id")
+
+	if err := h.playerUsecase.ReadNotification(ctx, c.Get("player_id").(string), notificationId); err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"message": fmt.Sprintf("notification_id: %s is read", notificationId),
+	})
+}
+
+func (h *playerHttpHandler) ReadAllNotifications(c echo.Context) error {
+	ctx := context.Background()
+
+	res, err := h.playerUsecase.ReadAllNotifications(ctx, c.Get("player_id").(string))
+	if err != nil {
+		return response.ErrResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, map[string]any{
+		"read": res,
+	})
+}
@@ -6,9 +6,11 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerUsecase"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
@@ -19,6 +21,8 @@ type (
 		DockedPlayerMoney()
 		AddPlayerMoney()
 		RollbackPlayerTransaction()
+		DeletePlayerTransactions()
+		NotifyItemAlerts()
 	}
 
 	playerQueueHandler struct {
@@ -177,3 +181,113 @@ func (h *playerQueueHandler) RollbackPlayerTransaction() {
 		}
 	}
 }
+
+func (h *playerQueueHandler) DeletePlayerTransactions() {
+	ctx := context.Background()
+
+	consumer, err := h.PlayerConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Start DeletePlayerTransactions ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Println("Error: DeletePlayerTransactions failed: ", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			if string(msg.Key) == "pdelete" {
+				req := new(player.PlayerDeletedEvent)
+
+				if err := queue.DecodeMessage(req, msg.Value); err != nil {
+					h.playerUsecase.UpserOffset(ctx, msg.Offset+1)
+					continue
+				}
+
+				// Note that: the offset is kept until the data of the player is deleted, so a delete that fails
+				// after the retries is read again when the consumer starts again
+				if err := h.deletePlayerData(ctx, req, sigchan); err != nil {
+					log.Printf("Error: DeletePlayerTransactions failed: player %s is not deleted: %s", req.PlayerId, err.Error())
+					continue
+				}
+				h.playerUsecase.UpserOffset(ctx, msg.Offset+1)
+
+				log.Printf("DeletePlayerTransactions | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigchan:
+			log.Println("Stop DeletePlayerTransactions...")
+			return
+		}
+	}
+}
+
+// deletePlayerData tries the delete again after 1, 2 and 4 seconds, a stop signal ends the retries
+func (h *playerQueueHandler) deletePlayerData(ctx context.Context, req *player.PlayerDeletedEvent, sigchan chan os.Signal) error {
+	delay := time.Second
+
+	for i := 0; ; i++ {
+		err := h.playerUsecase.DeletePlayerTransactions(ctx, req)
+		if err == nil {
+			err = h.playerUsecase.DeletePlayerWishlists(ctx, req)
+		}
+		if err == nil || i == 3 {
+			return err
+		}
+
+		log.Printf("Error: DeletePlayerTransactions failed: player %s, try again in %s: %s", req.PlayerId, delay, err.Error())
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case sig := <-sigchan:
+			// The signal is put back so the consumer stops too
+			sigchan <- sig
+			return err
+		}
+	}
+}
+
+func (h *playerQueueHandler) NotifyItemAlerts() {
+	ctx := context.Background()
+
+	consumer, err := h.PlayerConsumer(ctx)
+	if err != nil {
+		return
+	}
+	defer consumer.Close()
+
+	log.Println("Start NotifyItemAlerts ...")
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	for {
+		select {
+		case err := <-consumer.Errors():
+			log.Println("Error: NotifyItemAlerts failed: ", err.Error())
+			continue
+		case msg := <-consumer.Messages():
+			if string(msg.Key) == item.AlertPriceDrop || string(msg.Key) == item.AlertRestock {
+				h.playerUsecase.UpserOffset(ctx, msg.Offset+1)
+
+				req := new(item.ItemAlertEvent)
+
+				if err := queue.DecodeMessage(req, msg.Value); err != nil {
+					continue
+				}
+
+				h.playerUsecase.NotifyItemAlert(ctx, req)
+
+				log.Printf("NotifyItemAlerts | Topic(%s)| Offset(%d) Message(%s) \n", msg.Topic, msg.Offset, string(msg.Value))
+			}
+		case <-sigchan:
+			log.Println("Stop NotifyItemAlerts...")
+			return
+		}
+	}
+}
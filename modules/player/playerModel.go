@@ -1,14 +1,22 @@
 package player
 
-import "time"
+import (
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+)
 
 type (
 	PlayerProfile struct {
-		Id        string    `json:"_id"`
-		Email     string    `json:"email"`
-		Username  string    `json:"username"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+		Id          string    `json:"_id"`
+		Email       string    `json:"email"`
+		Username    string    `json:"username"`
+		DisplayName string    `json:"display_name"`
+		AvatarUrl   string    `json:"avatar_url"`
+		IsVerified  bool      `json:"is_verified"`
+		IsDeleted   bool      `json:"is_deleted,omitempty"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
 	}
 
 	PlayerClaims struct {
@@ -22,12 +30,122 @@ type (
 		Username string `json:"username" form:"username" validate:"required,max=64"`
 	}
 
+	// Note that: order_id is the order of the payment saga, the transaction of a rolled back order is not inserted
 	CreatePlayerTransactionReq struct {
+		OrderId  string  `json:"order_id,omitempty" validate:"max=64"`
 		PlayerId string  `json:"player_id" validate:"required,max=64"`
 		Amount   float64 `json:"amount" validate:"required"`
 	}
 
+	// Note that: an order_id rolls back every transaction of the order, also the one that is inserted after the rollback
 	RollbackPlayerTransactionReq struct {
+		OrderId       string `json:"order_id,omitempty"`
 		TransactionId string `json:"transaction_id"`
 	}
+
+	ChangePasswordReq struct {
+		PlayerId        string `json:"-"`
+		CurrentPassword string `json:"current_password" form:"current_password" validate:"required,max=32"`
+		NewPassword     string `json:"new_password" form:"new_password" validate:"required,max=32"`
+	}
+
+	ForgotPasswordReq struct {
+		Email string `json:"email" form:"email" validate:"required,email,max=255"`
+	}
+
+	ResetPasswordReq struct {
+		Token       string `json:"token" form:"token" validate:"required"`
+		NewPassword string `json:"new_password" form:"new_password" validate:"required,max=32"`
+	}
+
+	// Note that: an empty field is left unchanged
+	UpdatePlayerProfileReq struct {
+		PlayerId    string `json:"-"`
+		Username    string `json:"username" form:"username" validate:"max=64"`
+		DisplayName string `json:"display_name" form:"display_name" validate:"max=64"`
+	}
+
+	ChangeEmailReq struct {
+		PlayerId string `json:"-"`
+		Email    string `json:"email" form:"email" validate:"required,email,max=255"`
+		Password string `json:"password" form:"password" validate:"required,max=32"`
+	}
+
+	// Note that: the password is required only for a player who has set one
+	DeletePlayerReq struct {
+		PlayerId string `json:"-"`
+		Password string `json:"password" form:"password" validate:"max=32"`
+	}
+
+	// Published to the auth, inventory and player topics with the key "pdelete"
+	PlayerDeletedEvent struct {
+		PlayerId string `json:"player_id"`
+	}
+
+	// Note that: the dates are RFC3339 or 2006-01-02
+	PlayerSearchReq struct {
+		Email          string `query:"email" validate:"max=255"`
+		Username       string `query:"username" validate:"max=64"`
+		RegisteredFrom string `query:"registered_from" validate:"max=64"`
+		RegisteredTo   string `query:"registered_to" validate:"max=64"`
+		models.PaginateReq
+	}
+
+	// Note that: a part that can not be loaded is left empty and its error is listed
+	PlayerOverview struct {
+		Profile   *PlayerProfile           `json:"profile"`
+		RoleCode  int                      `json:"role_code"`
+		Balance   float64                  `json:"balance"`
+		Inventory *PlayerOverviewInventory `json:"inventory"`
+		Sessions  []*PlayerOverviewSession `json:"sessions"`
+		Ban       *PlayerOverviewBan       `json:"ban"`
+		Errors    []string                 `json:"errors,omitempty"`
+	}
+
+	PlayerOverviewInventory struct {
+		Items []*PlayerOverviewItem `json:"items"`
+		Total int64                 `json:"total"`
+	}
+
+	PlayerOverviewItem struct {
+		InventoryId string  `json:"inventory_id"`
+		ItemId      string  `json:"item_id"`
+		Title       string  `json:"title"`
+		Price       float64 `json:"price"`
+		Damage      int     `json:"damage"`
+		ImageUrl    string  `json:"image_url"`
+	}
+
+	PlayerOverviewSession struct {
+		CredentialId string `json:"credential_id"`
+		RoleCode     int    `json:"role_code"`
+		CreatedAt    string `json:"created_at"`
+		UpdatedAt    string `json:"updated_at"`
+	}
+
+	PlayerOverviewBan struct {
+		Kind      string `json:"kind"`
+		Reason    string `json:"reason"`
+		ExpiresAt string `json:"expires_at"`
+		CreatedBy string `json:"created_by"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	VerifyEmailReq struct {
+		Token string `json:"token" form:"token" query:"token" validate:"required"`
+	}
+
+	ResendEmailVerificationReq struct {
+		Email string `json:"email" form:"email" validate:"required,email,max=255"`
+	}
+
+	AddWishlistItemReq struct {
+		ItemId string `json:"item_id" validate:"required,max=64"`
+	}
+
+	NotificationSearchReq struct {
+		// Note that: read, unread or empty for every notification
+		Status string `query:"status"`
+		models.PaginateReq
+	}
 )
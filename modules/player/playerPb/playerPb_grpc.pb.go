@@ -24,6 +24,10 @@ const (
 	PlayerGrpcService_CredentialSearch_FullMethodName              = "/PlayerGrpcService/CredentialSearch"
 	PlayerGrpcService_FindOnePlayerProfileToRefresh_FullMethodName = "/PlayerGrpcService/FindOnePlayerProfileToRefresh"
 	PlayerGrpcService_GetPlayerSavingAccount_FullMethodName        = "/PlayerGrpcService/GetPlayerSavingAccount"
+	PlayerGrpcService_FindPlayerRoles_FullMethodName               = "/PlayerGrpcService/FindPlayerRoles"
+	PlayerGrpcService_AddPlayerRoles_FullMethodName                = "/PlayerGrpcService/AddPlayerRoles"
+	PlayerGrpcService_RemovePlayerRoles_FullMethodName             = "/PlayerGrpcService/RemovePlayerRoles"
+	PlayerGrpcService_FindOrCreatePlayerByIdentity_FullMethodName  = "/PlayerGrpcService/FindOrCreatePlayerByIdentity"
 )
 
 // PlayerGrpcServiceClient is the client API for PlayerGrpcService service.
@@ -33,6 +37,10 @@ type PlayerGrpcServiceClient interface {
 	CredentialSearch(ctx context.Context, in *CredentialSearchReq, opts ...grpc.CallOption) (*PlayerProfile, error)
 	FindOnePlayerProfileToRefresh(ctx context.Context, in *FindOnePlayerProfileToRefreshReq, opts ...grpc.CallOption) (*PlayerProfile, error)
 	GetPlayerSavingAccount(ctx context.Context, in *GetPlayerSavingAccountReq, opts ...grpc.CallOption) (*GetPlayerSavingAccountRes, error)
+	FindPlayerRoles(ctx context.Context, in *FindPlayerRolesReq, opts ...grpc.CallOption) (*PlayerRolesRes, error)
+	AddPlayerRoles(ctx context.Context, in *AddPlayerRolesReq, opts ...grpc.CallOption) (*PlayerRolesRes, error)
+	RemovePlayerRoles(ctx context.Context, in *RemovePlayerRolesReq, opts ...grpc.CallOption) (*PlayerRolesRes, error)
+	FindOrCreatePlayerByIdentity(ctx context.Context, in *FindOrCreatePlayerByIdentityReq, opts ...grpc.CallOption) (*PlayerProfile, error)
 }
 
 type playerGrpcServiceClient struct {
@@ -70,6 +78,42 @@ func (c *playerGrpcServiceClient) GetPlayerSavingAccount(ctx context.Context, in
 	return out, nil
 }
 
+func (c *playerGrpcServiceClient) FindPlayerRoles(ctx context.Context, in *FindPlayerRolesReq, opts ...grpc.CallOption) (*PlayerRolesRes, error) {
+	out := new(PlayerRolesRes)
+	err := c.cc.Invoke(ctx, PlayerGrpcService_FindPlayerRoles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playerGrpcServiceClient) AddPlayerRoles(ctx context.Context, in *AddPlayerRolesReq, opts ...grpc.CallOption) (*PlayerRolesRes, error) {
+	out := new(PlayerRolesRes)
+	err := c.cc.Invoke(ctx, PlayerGrpcService_AddPlayerRoles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playerGrpcServiceClient) RemovePlayerRoles(ctx context.Context, in *RemovePlayerRolesReq, opts ...grpc.CallOption) (*PlayerRolesRes, error) {
+	out := new(PlayerRolesRes)
+	err := c.cc.Invoke(ctx, PlayerGrpcService_RemovePlayerRoles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playerGrpcServiceClient) FindOrCreatePlayerByIdentity(ctx context.Context, in *FindOrCreatePlayerByIdentityReq, opts ...grpc.CallOption) (*PlayerProfile, error) {
+	out := new(PlayerProfile)
+	err := c.cc.Invoke(ctx, PlayerGrpcService_FindOrCreatePlayerByIdentity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PlayerGrpcServiceServer is the server API for PlayerGrpcService service.
 // All implementations must embed UnimplementedPlayerGrpcServiceServer
 // for forward compatibility
@@ -77,6 +121,10 @@ type PlayerGrpcServiceServer interface {
 	CredentialSearch(context.Context, *CredentialSearchReq) (*PlayerProfile, error)
 	FindOnePlayerProfileToRefresh(context.Context, *FindOnePlayerProfileToRefreshReq) (*PlayerProfile, error)
 	GetPlayerSavingAccount(context.Context, *GetPlayerSavingAccountReq) (*GetPlayerSavingAccountRes, error)
+	FindPlayerRoles(context.Context, *FindPlayerRolesReq) (*PlayerRolesRes, error)
+	AddPlayerRoles(context.Context, *AddPlayerRolesReq) (*PlayerRolesRes, error)
+	RemovePlayerRoles(context.Context, *RemovePlayerRolesReq) (*PlayerRolesRes, error)
+	FindOrCreatePlayerByIdentity(context.Context, *FindOrCreatePlayerByIdentityReq) (*PlayerProfile, error)
 	mustEmbedUnimplementedPlayerGrpcServiceServer()
 }
 
@@ -93,6 +141,18 @@ func (UnimplementedPlayerGrpcServiceServer) FindOnePlayerProfileToRefresh(contex
 func (UnimplementedPlayerGrpcServiceServer) GetPlayerSavingAccount(context.Context, *GetPlayerSavingAccountReq) (*GetPlayerSavingAccountRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerSavingAccount not implemented")
 }
+func (UnimplementedPlayerGrpcServiceServer) FindPlayerRoles(context.Context, *FindPlayerRolesReq) (*PlayerRolesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindPlayerRoles not implemented")
+}
+func (UnimplementedPlayerGrpcServiceServer) AddPlayerRoles(context.Context, *AddPlayerRolesReq) (*PlayerRolesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPlayerRoles not implemented")
+}
+func (UnimplementedPlayerGrpcServiceServer) RemovePlayerRoles(context.Context, *RemovePlayerRolesReq) (*PlayerRolesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePlayerRoles not implemented")
+}
+func (UnimplementedPlayerGrpcServiceServer) FindOrCreatePlayerByIdentity(context.Context, *FindOrCreatePlayerByIdentityReq) (*PlayerProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindOrCreatePlayerByIdentity not implemented")
+}
 func (UnimplementedPlayerGrpcServiceServer) mustEmbedUnimplementedPlayerGrpcServiceServer() {}
 
 // UnsafePlayerGrpcServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -160,6 +220,78 @@ func _PlayerGrpcService_GetPlayerSavingAccount_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PlayerGrpcService_FindPlayerRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindPlayerRolesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerGrpcServiceServer).FindPlayerRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerGrpcService_FindPlayerRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerGrpcServiceServer).FindPlayerRoles(ctx, req.(*FindPlayerRolesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlayerGrpcService_AddPlayerRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPlayerRolesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerGrpcServiceServer).AddPlayerRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerGrpcService_AddPlayerRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerGrpcServiceServer).AddPlayerRoles(ctx, req.(*AddPlayerRolesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlayerGrpcService_RemovePlayerRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePlayerRolesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerGrpcServiceServer).RemovePlayerRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerGrpcService_RemovePlayerRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerGrpcServiceServer).RemovePlayerRoles(ctx, req.(*RemovePlayerRolesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlayerGrpcService_FindOrCreatePlayerByIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindOrCreatePlayerByIdentityReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayerGrpcServiceServer).FindOrCreatePlayerByIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlayerGrpcService_FindOrCreatePlayerByIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayerGrpcServiceServer).FindOrCreatePlayerByIdentity(ctx, req.(*FindOrCreatePlayerByIdentityReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PlayerGrpcService_ServiceDesc is the grpc.ServiceDesc for PlayerGrpcService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -179,6 +311,22 @@ var PlayerGrpcService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPlayerSavingAccount",
 			Handler:    _PlayerGrpcService_GetPlayerSavingAccount_Handler,
 		},
+		{
+			MethodName: "FindPlayerRoles",
+			Handler:    _PlayerGrpcService_FindPlayerRoles_Handler,
+		},
+		{
+			MethodName: "AddPlayerRoles",
+			Handler:    _PlayerGrpcService_AddPlayerRoles_Handler,
+		},
+		{
+			MethodName: "RemovePlayerRoles",
+			Handler:    _PlayerGrpcService_RemovePlayerRoles_Handler,
+		},
+		{
+			MethodName: "FindOrCreatePlayerByIdentity",
+			Handler:    _PlayerGrpcService_FindOrCreatePlayerByIdentity_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "modules/player/playerPb/playerPb.proto",
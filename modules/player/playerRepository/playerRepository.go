@@ -4,13 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"time"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	authPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authPb"
+	inventoryPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/blobstore"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/notifier"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/queue"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
@@ -30,9 +38,48 @@ type (
 		GetPlayerSavingAccount(pctx context.Context, playerId string) (*player.PlayerSavingAccount, error)
 		FindOnePlayerCredential(pctx context.Context, email string) (*player.Player, error)
 		FindOnePlayerProfileToRefresh(pctx context.Context, playerId string) (*player.Player, error)
+		FindManyPlayers(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*player.PlayerProfileBson, error)
+		CountPlayers(pctx context.Context, filter primitive.D) (int64, error)
+		FindPlayerInventory(pctx context.Context, grpcUrl string, req *inventoryPb.FindPlayerInventoryReq) (*inventoryPb.FindPlayerInventoryRes, error)
+		FindPlayerSessions(pctx context.Context, grpcUrl string, req *authPb.FindPlayerSessionsReq) (*authPb.FindPlayerSessionsRes, error)
+		AddManyPlayerRoles(pctx context.Context, playerId string, roles []player.PlayerRole) error
+		RemoveManyPlayerRoles(pctx context.Context, playerId string, roleCodes []int) error
+		FindOnePlayerByIdentity(pctx context.Context, provider, subject string) (*player.Player, error)
+		PushOnePlayerIdentity(pctx context.Context, playerId string, identity *player.PlayerIdentity) error
+		UpdateOnePlayerPassword(pctx context.Context, playerId, hashedPassword string) error
+		InsertOnePasswordReset(pctx context.Context, req *player.PasswordReset) error
+		UseOnePasswordReset(pctx context.Context, tokenHash string) (*player.PasswordReset, error)
+		UpdateManyPasswordResetsUsed(pctx context.Context, playerId string) error
+		UpdateOnePlayerVerified(pctx context.Context, playerId, email string) error
+		InsertOneEmailVerification(pctx context.Context, req *player.EmailVerification) error
+		UseOneEmailVerification(pctx context.Context, tokenHash string) (*player.EmailVerification, error)
+		UpdateOnePlayerProfile(pctx context.Context, req *player.UpdatePlayerProfileReq) error
+		UpdateOnePlayerEmail(pctx context.Context, playerId, email string) error
+		UpdateOnePlayerAvatar(pctx context.Context, playerId, avatarUrl, avatarKey string) error
+		AnonymizeOnePlayer(pctx context.Context, playerId string) error
+		DeleteManyPlayerTransactions(pctx context.Context, playerId string) error
+		PutOneBlob(pctx context.Context, cfg *config.Config, key, contentType string, body io.Reader) (string, error)
+		DeleteOneBlob(pctx context.Context, cfg *config.Config, key string) error
+		PlayerDeletedRes(pctx context.Context, cfg *config.Config, req *player.PlayerDeletedEvent) error
+		SendNotification(pctx context.Context, cfg *config.Config, msg *notifier.Message) error
+		RevokePlayerCredentials(pctx context.Context, grpcUrl string, req *authPb.RevokePlayerCredentialsReq) (*authPb.RevokePlayerCredentialsRes, error)
 		DeleteOnePlayerTransaction(pctx context.Context, transactionId string) error
+		DeleteManyOrderTransactions(pctx context.Context, orderId string) error
+		UpsertOneTransactionRollback(pctx context.Context, orderId string) error
+		FindOneTransactionRollback(pctx context.Context, orderId string) bool
 		DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
 		AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error
+		InsertOnePlayerWishlist(pctx context.Context, req *player.PlayerWishlist) error
+		FindManyPlayerWishlists(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*player.PlayerWishlist, error)
+		CountPlayerWishlists(pctx context.Context, filter primitive.D) (int64, error)
+		DeleteOnePlayerWishlist(pctx context.Context, playerId, itemId string) error
+		DeleteManyPlayerWishlists(pctx context.Context, playerId string) error
+		InsertManyPlayerNotifications(pctx context.Context, req []*player.PlayerNotification) error
+		FindManyPlayerNotifications(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*player.PlayerNotification, error)
+		CountPlayerNotifications(pctx context.Context, filter primitive.D) (int64, error)
+		UpdateOnePlayerNotificationRead(pctx context.Context, playerId, notificationId string, now time.Time) error
+		UpdateManyPlayerNotificationsRead(pctx context.Context, playerId string, now time.Time) (int64, error)
+		DeleteManyPlayerNotifications(pctx context.Context, playerId string) error
 	}
 
 	playerRepository struct {
@@ -135,6 +182,58 @@ func (r *playerRepository) DeleteOnePlayerTransaction(pctx context.Context, tran
 	return nil
 }
 
+func (r *playerRepository) DeleteManyOrderTransactions(pctx context.Context, orderId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_transactions")
+
+	result, err := col.DeleteMany(ctx, bson.M{"order_id": orderId})
+	if err != nil {
+		log.Printf("Error: DeleteManyOrderTransactions: %s", err.Error())
+		return errors.New("error: delete many order transactions failed")
+	}
+	log.Printf("Delete result: %v", result)
+
+	return nil
+}
+
+func (r *playerRepository) UpsertOneTransactionRollback(pctx context.Context, orderId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_transactions_rollbacks")
+
+	if _, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": orderId},
+		bson.M{"$setOnInsert": bson.M{"created_at": utils.LocalTime()}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		log.Printf("Error: UpsertOneTransactionRollback: %s", err.Error())
+		return errors.New("error: upsert one transaction rollback failed")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) FindOneTransactionRollback(pctx context.Context, orderId string) bool {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_transactions_rollbacks")
+
+	result := new(player.PlayerTransactionRollback)
+
+	if err := col.FindOne(ctx, bson.M{"_id": orderId}).Decode(result); err != nil {
+		return false
+	}
+	return true
+}
+
 func (r *playerRepository) FindOnePlayerProfile(pctx context.Context, playerId string) (*player.PlayerProfileBson, error) {
 	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
 	defer cancel()
@@ -149,11 +248,15 @@ func (r *playerRepository) FindOnePlayerProfile(pctx context.Context, playerId s
 		bson.M{"_id": utils.ConvertToObjectId(playerId)},
 		options.FindOne().SetProjection(
 			bson.M{
-				"_id":        1,
-				"email":      1,
-				"username":   1,
-				"created_at": 1,
-				"updated_at": 1,
+				"_id":          1,
+				"email":        1,
+				"username":     1,
+				"display_name": 1,
+				"avatar_url":   1,
+				"is_verified":  1,
+				"is_deleted":   1,
+				"created_at":   1,
+				"updated_at":   1,
 			},
 		),
 	).Decode(result); err != nil {
@@ -260,6 +363,626 @@ func (r *playerRepository) FindOnePlayerProfileToRefresh(pctx context.Context, p
 	return result, nil
 }
 
+func (r *playerRepository) FindManyPlayers(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*player.PlayerProfileBson, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	opts = append(opts, options.Find().SetProjection(
+		bson.M{
+			"_id":          1,
+			"email":        1,
+			"username":     1,
+			"display_name": 1,
+			"avatar_url":   1,
+			"is_verified":  1,
+			"is_deleted":   1,
+			"created_at":   1,
+			"updated_at":   1,
+		},
+	))
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyPlayers: %s", err.Error())
+		return nil, errors.New("error: find many players failed")
+	}
+
+	results := make([]*player.PlayerProfileBson, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyPlayers: %s", err.Error())
+		return nil, errors.New("error: find many players failed")
+	}
+
+	return results, nil
+}
+
+func (r *playerRepository) CountPlayers(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountPlayers: %s", err.Error())
+		return -1, errors.New("error: count players failed")
+	}
+
+	return count, nil
+}
+
+// Note that: the roles are added in one update, a role that the player has already is not added twice
+// and two grants at the same moment both keep their roles
+func (r *playerRepository) AddManyPlayerRoles(pctx context.Context, playerId string, roles []player.PlayerRole) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId)},
+		bson.M{
+			"$addToSet": bson.M{"player_roles": bson.M{"$each": roles}},
+			"$set":      bson.M{"updated_at": utils.LocalTime()},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: AddManyPlayerRoles: %s", err.Error())
+		return errors.New("error: add player roles failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: AddManyPlayerRoles: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) RemoveManyPlayerRoles(pctx context.Context, playerId string, roleCodes []int) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId)},
+		bson.M{
+			"$pull": bson.M{"player_roles": bson.M{"role_code": bson.M{"$in": roleCodes}}},
+			"$set":  bson.M{"updated_at": utils.LocalTime()},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: RemoveManyPlayerRoles: %s", err.Error())
+		return errors.New("error: remove player roles failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: RemoveManyPlayerRoles: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) FindOnePlayerByIdentity(pctx context.Context, provider, subject string) (*player.Player, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result := new(player.Player)
+
+	if err := col.FindOne(
+		ctx,
+		bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}},
+	).Decode(result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		log.Printf("Error: FindOnePlayerByIdentity: %s", err.Error())
+		return nil, errors.New("error: find player by identity failed")
+	}
+
+	return result, nil
+}
+
+func (r *playerRepository) PushOnePlayerIdentity(pctx context.Context, playerId string, identity *player.PlayerIdentity) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId)},
+		bson.M{
+			"$push": bson.M{"identities": identity},
+			"$set":  bson.M{"updated_at": utils.LocalTime()},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: PushOnePlayerIdentity: %s", err.Error())
+		return errors.New("error: link player identity failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: PushOnePlayerIdentity: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) UpdateOnePlayerPassword(pctx context.Context, playerId, hashedPassword string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId)},
+		bson.M{
+			"$set": bson.M{
+				"password":   hashedPassword,
+				"updated_at": utils.LocalTime(),
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOnePlayerPassword: %s", err.Error())
+		return errors.New("error: update player password failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: UpdateOnePlayerPassword: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) InsertOnePasswordReset(pctx context.Context, req *player.PasswordReset) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("password_resets")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOnePasswordReset: %s", err.Error())
+		return errors.New("error: insert password reset failed")
+	}
+
+	return nil
+}
+
+// Note that: the token is marked as used in the same query, so it can be used only once
+func (r *playerRepository) UseOnePasswordReset(pctx context.Context, tokenHash string) (*player.PasswordReset, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("password_resets")
+
+	now := utils.LocalTime()
+
+	result := new(player.PasswordReset)
+
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"token_hash": tokenHash,
+			"is_used":    false,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{
+			"$set": bson.M{
+				"is_used": true,
+				"used_at": now,
+			},
+		},
+	).Decode(result); err != nil {
+		log.Printf("Error: UseOnePasswordReset: %s", err.Error())
+		return nil, errors.New("error: reset token is invalid or expired")
+	}
+
+	return result, nil
+}
+
+func (r *playerRepository) UpdateManyPasswordResetsUsed(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("password_resets")
+
+	if _, err := col.UpdateMany(
+		ctx,
+		bson.M{"player_id": playerId, "is_used": false},
+		bson.M{
+			"$set": bson.M{
+				"is_used": true,
+				"used_at": utils.LocalTime(),
+			},
+		},
+	); err != nil {
+		log.Printf("Error: UpdateManyPasswordResetsUsed: %s", err.Error())
+		return errors.New("error: update password resets failed")
+	}
+
+	return nil
+}
+
+// Note that: the email is matched too, a token is not valid anymore once the email is changed
+func (r *playerRepository) UpdateOnePlayerVerified(pctx context.Context, playerId, email string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId), "email": email},
+		bson.M{
+			"$set": bson.M{
+				"is_verified": true,
+				"updated_at":  utils.LocalTime(),
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOnePlayerVerified: %s", err.Error())
+		return errors.New("error: verify player failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: UpdateOnePlayerVerified: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) InsertOneEmailVerification(pctx context.Context, req *player.EmailVerification) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("email_verifications")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		log.Printf("Error: InsertOneEmailVerification: %s", err.Error())
+		return errors.New("error: insert email verification failed")
+	}
+
+	return nil
+}
+
+// Note that: the token is marked as used in the same query, so it can be used only once
+func (r *playerRepository) UseOneEmailVerification(pctx context.Context, tokenHash string) (*player.EmailVerification, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("email_verifications")
+
+	now := utils.LocalTime()
+
+	result := new(player.EmailVerification)
+
+	if err := col.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"token_hash": tokenHash,
+			"is_used":    false,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{
+			"$set": bson.M{
+				"is_used": true,
+				"used_at": now,
+			},
+		},
+	).Decode(result); err != nil {
+		log.Printf("Error: UseOneEmailVerification: %s", err.Error())
+		return nil, errors.New("error: verify token is invalid or expired")
+	}
+
+	return result, nil
+}
+
+func (r *playerRepository) UpdateOnePlayerProfile(pctx context.Context, req *player.UpdatePlayerProfileReq) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	updates := bson.M{"updated_at": utils.LocalTime()}
+	if req.Username != "" {
+		updates["username"] = req.Username
+	}
+	if req.DisplayName != "" {
+		updates["display_name"] = req.DisplayName
+	}
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(req.PlayerId), "is_deleted": bson.M{"$ne": true}},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOnePlayerProfile: %s", err.Error())
+		return errors.New("error: update player profile failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: UpdateOnePlayerProfile: player %s not found", req.PlayerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+// Note that: the new email has to be verified again
+func (r *playerRepository) UpdateOnePlayerEmail(pctx context.Context, playerId, email string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId), "is_deleted": bson.M{"$ne": true}},
+		bson.M{
+			"$set": bson.M{
+				"email":       email,
+				"is_verified": false,
+				"updated_at":  utils.LocalTime(),
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOnePlayerEmail: %s", err.Error())
+		return errors.New("error: update player email failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: UpdateOnePlayerEmail: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) UpdateOnePlayerAvatar(pctx context.Context, playerId, avatarUrl, avatarKey string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId), "is_deleted": bson.M{"$ne": true}},
+		bson.M{
+			"$set": bson.M{
+				"avatar_url": avatarUrl,
+				"avatar_key": avatarKey,
+				"updated_at": utils.LocalTime(),
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOnePlayerAvatar: %s", err.Error())
+		return errors.New("error: update player avatar failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: UpdateOnePlayerAvatar: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+// Note that: the document is kept so the player id is never reused, every personal field is wiped
+func (r *playerRepository) AnonymizeOnePlayer(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("players")
+
+	now := utils.LocalTime()
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(playerId), "is_deleted": bson.M{"$ne": true}},
+		bson.M{
+			"$set": bson.M{
+				"email":        fmt.Sprintf("deleted+%s@invalid", playerId),
+				"username":     fmt.Sprintf("deleted-%s", playerId),
+				"display_name": "",
+				"password":     "",
+				"avatar_url":   "",
+				"avatar_key":   "",
+				"is_verified":  false,
+				"is_deleted":   true,
+				"deleted_at":   now,
+				"updated_at":   now,
+				"player_roles": []player.PlayerRole{},
+			},
+			"$unset": bson.M{"identities": ""},
+		},
+	)
+	if err != nil {
+		log.Printf("Error: AnonymizeOnePlayer: %s", err.Error())
+		return errors.New("error: delete player failed")
+	}
+
+	if result.MatchedCount == 0 {
+		log.Printf("Error: AnonymizeOnePlayer: player %s not found", playerId)
+		return errors.New("error: player not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) DeleteManyPlayerTransactions(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_transactions")
+
+	result, err := col.DeleteMany(ctx, bson.M{"player_id": bson.M{"$in": []string{"player:" + playerId, playerId}}})
+	if err != nil {
+		log.Printf("Error: DeleteManyPlayerTransactions: %s", err.Error())
+		return errors.New("error: delete player transactions failed")
+	}
+	log.Printf("DeleteManyPlayerTransactions result: %v", result)
+
+	return nil
+}
+
+func (r *playerRepository) PutOneBlob(pctx context.Context, cfg *config.Config, key, contentType string, body io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	url, err := blobstore.NewBlobStore(&cfg.Blob).Put(ctx, key, contentType, body)
+	if err != nil {
+		log.Printf("Error: PutOneBlob failed: %s", err.Error())
+		return "", errors.New("error: upload file failed")
+	}
+
+	return url, nil
+}
+
+func (r *playerRepository) DeleteOneBlob(pctx context.Context, cfg *config.Config, key string) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	if err := blobstore.NewBlobStore(&cfg.Blob).Delete(ctx, key); err != nil {
+		log.Printf("Error: DeleteOneBlob failed: %s", err.Error())
+		return errors.New("error: delete file failed")
+	}
+
+	return nil
+}
+
+// Note that: every service that keeps data of the player consumes the event from its own topic
+func (r *playerRepository) PlayerDeletedRes(pctx context.Context, cfg *config.Config, req *player.PlayerDeletedEvent) error {
+	reqInBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Error: PlayerDeletedRes failed: %s", err.Error())
+		return errors.New("error: player deleted res failed")
+	}
+
+	for _, topic := range []string{"auth", "inventory", "player"} {
+		if err := queue.PushMessageWithKeyToQueue(
+			[]string{cfg.Kafka.Url},
+			cfg.Kafka.ApiKey,
+			cfg.Kafka.Secret,
+			topic,
+			"pdelete",
+			reqInBytes,
+		); err != nil {
+			log.Printf("Error: PlayerDeletedRes to %s failed: %s", topic, err.Error())
+			return errors.New("error: player deleted res failed")
+		}
+	}
+
+	return nil
+}
+
+func (r *playerRepository) SendNotification(pctx context.Context, cfg *config.Config, msg *notifier.Message) error {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	if err := notifier.NewNotifier(&cfg.Notifier).Send(ctx, msg); err != nil {
+		log.Printf("Error: SendNotification failed: %s", err.Error())
+		return errors.New("error: send notification failed")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) RevokePlayerCredentials(pctx context.Context, grpcUrl string, req *authPb.RevokePlayerCredentialsReq) (*authPb.RevokePlayerCredentialsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Auth().RevokePlayerCredentials(ctx, req)
+	if err != nil {
+		log.Printf("Error: RevokePlayerCredentials failed: %s", err.Error())
+		return nil, errors.New("error: revoke player credentials failed")
+	}
+
+	return result, nil
+}
+
+func (r *playerRepository) FindPlayerInventory(pctx context.Context, grpcUrl string, req *inventoryPb.FindPlayerInventoryReq) (*inventoryPb.FindPlayerInventoryRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Inventory().FindPlayerInventory(ctx, req)
+	if err != nil {
+		log.Printf("Error: FindPlayerInventory failed: %s", err.Error())
+		return nil, errors.New("error: find player inventory failed")
+	}
+
+	return result, nil
+}
+
+func (r *playerRepository) FindPlayerSessions(pctx context.Context, grpcUrl string, req *authPb.FindPlayerSessionsReq) (*authPb.FindPlayerSessionsRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	jwtauth.SetApiKeyInContext(&ctx)
+	conn, err := grpccon.NewGrpcClient(grpcUrl)
+	if err != nil {
+		log.Printf("Error: gRPC connection failed: %s", err.Error())
+		return nil, errors.New("error: gRPC connection failed")
+	}
+
+	result, err := conn.Auth().FindPlayerSessions(ctx, req)
+	if err != nil {
+		log.Printf("Error: FindPlayerSessions failed: %s", err.Error())
+		return nil, errors.New("error: find player sessions failed")
+	}
+
+	return result, nil
+}
+
 func (r *playerRepository) DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
 	reqInBytes, err := json.Marshal(req)
 	if err != nil {
@@ -303,3 +1026,230 @@ func (r *playerRepository) AddPlayerMoneyRes(pctx context.Context, cfg *config.C
 
 	return nil
 }
+
+func (r *playerRepository) InsertOnePlayerWishlist(pctx context.Context, req *player.PlayerWishlist) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_wishlists")
+
+	if _, err := col.InsertOne(ctx, req); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("error: item is already in the wishlist")
+		}
+		log.Printf("Error: InsertOnePlayerWishlist failed: %s", err.Error())
+		return errors.New("error: insert one player wishlist failed")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) FindManyPlayerWishlists(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*player.PlayerWishlist, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_wishlists")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyPlayerWishlists failed: %s", err.Error())
+		return nil, errors.New("error: find many player wishlists failed")
+	}
+
+	results := make([]*player.PlayerWishlist, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyPlayerWishlists failed: %s", err.Error())
+		return nil, errors.New("error: find many player wishlists failed")
+	}
+
+	return results, nil
+}
+
+func (r *playerRepository) CountPlayerWishlists(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_wishlists")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountPlayerWishlists failed: %s", err.Error())
+		return -1, errors.New("error: count player wishlists failed")
+	}
+
+	return count, nil
+}
+
+func (r *playerRepository) DeleteOnePlayerWishlist(pctx context.Context, playerId, itemId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_wishlists")
+
+	result, err := col.DeleteOne(ctx, bson.M{"player_id": playerId, "item_id": itemId})
+	if err != nil {
+		log.Printf("Error: DeleteOnePlayerWishlist failed: %s", err.Error())
+		return errors.New("error: delete one player wishlist failed")
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("error: item is not in the wishlist")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) DeleteManyPlayerWishlists(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_wishlists")
+
+	result, err := col.DeleteMany(ctx, bson.M{"player_id": bson.M{"$in": []string{"player:" + playerId, playerId}}})
+	if err != nil {
+		log.Printf("Error: DeleteManyPlayerWishlists: %s", err.Error())
+		return errors.New("error: delete player wishlists failed")
+	}
+	log.Printf("DeleteManyPlayerWishlists result: %v", result)
+
+	return nil
+}
+
+// Note that: the notifications that already exist are skipped, the others are still inserted
+func (r *playerRepository) InsertManyPlayerNotifications(pctx context.Context, req []*player.PlayerNotification) error {
+	if len(req) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(pctx, 30*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_notifications")
+
+	docs := make([]any, 0)
+	for _, v := range req {
+		docs = append(docs, v)
+	}
+
+	if _, err := col.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+			isDuplicateOnly := true
+			for _, v := range bulkErr.WriteErrors {
+				if v.Code != 11000 {
+					isDuplicateOnly = false
+				}
+			}
+			if isDuplicateOnly {
+				return nil
+			}
+		}
+		log.Printf("Error: InsertManyPlayerNotifications failed: %s", err.Error())
+		return errors.New("error: insert many player notifications failed")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) FindManyPlayerNotifications(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*player.PlayerNotification, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_notifications")
+
+	cursors, err := col.Find(ctx, filter, opts...)
+	if err != nil {
+		log.Printf("Error: FindManyPlayerNotifications failed: %s", err.Error())
+		return nil, errors.New("error: find many player notifications failed")
+	}
+
+	results := make([]*player.PlayerNotification, 0)
+	if err := cursors.All(ctx, &results); err != nil {
+		log.Printf("Error: FindManyPlayerNotifications failed: %s", err.Error())
+		return nil, errors.New("error: find many player notifications failed")
+	}
+
+	return results, nil
+}
+
+func (r *playerRepository) CountPlayerNotifications(pctx context.Context, filter primitive.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_notifications")
+
+	count, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Printf("Error: CountPlayerNotifications failed: %s", err.Error())
+		return -1, errors.New("error: count player notifications failed")
+	}
+
+	return count, nil
+}
+
+func (r *playerRepository) UpdateOnePlayerNotificationRead(pctx context.Context, playerId, notificationId string, now time.Time) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_notifications")
+
+	result, err := col.UpdateOne(
+		ctx,
+		bson.M{"_id": utils.ConvertToObjectId(notificationId), "player_id": playerId},
+		bson.M{"$set": bson.M{"is_read": true, "read_at": now}},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateOnePlayerNotificationRead failed: %s", err.Error())
+		return errors.New("error: update one player notification failed")
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("error: notification not found")
+	}
+
+	return nil
+}
+
+func (r *playerRepository) UpdateManyPlayerNotificationsRead(pctx context.Context, playerId string, now time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_notifications")
+
+	result, err := col.UpdateMany(
+		ctx,
+		bson.M{"player_id": playerId, "is_read": false},
+		bson.M{"$set": bson.M{"is_read": true, "read_at": now}},
+	)
+	if err != nil {
+		log.Printf("Error: UpdateManyPlayerNotificationsRead failed: %s", err.Error())
+		return 0, errors.New("error: update many player notifications failed")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *playerRepository) DeleteManyPlayerNotifications(pctx context.Context, playerId string) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	db := r.playerDbConn(ctx)
+	col := db.Collection("player_notifications")
+
+	result, err := col.DeleteMany(ctx, bson.M{"player_id": bson.M{"$in": []string{"player:" + playerId, playerId}}})
+	if err != nil {
+		log.Printf("Error: DeleteManyPlayerNotifications: %s", err.Error())
+		return errors.New("error: delete player notifications failed")
+	}
+	log.Printf("DeleteManyPlayerNotifications result: %v", result)
+
+	return nil
+}
@@ -1,18 +1,36 @@
 package playerUsecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"math"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	authPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authPb"
+	inventoryPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
 	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/notifier"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,15 +38,39 @@ type (
 	PlayerUsecaseService interface {
 		GetOffset(pctx context.Context) (int64, error)
 		UpserOffset(pctx context.Context, offset int64) error
-		CreatePlayer(pctx context.Context, req *player.CreatePlayerReq) (*player.PlayerProfile, error)
+		CreatePlayer(pctx context.Context, cfg *config.Config, req *player.CreatePlayerReq) (*player.PlayerProfile, error)
 		FindOnePlayerProfile(pctx context.Context, playerId string) (*player.PlayerProfile, error)
+		SearchPlayers(pctx context.Context, cfg *config.Config, req *player.PlayerSearchReq) (*models.PaginateRes, error)
+		FindPlayerOverview(pctx context.Context, cfg *config.Config, playerId string) (*player.PlayerOverview, error)
 		AddPlayerMoney(pctx context.Context, req *player.CreatePlayerTransactionReq) (*player.PlayerSavingAccount, error)
 		GetPlayerSavingAccount(pctx context.Context, playerId string) (*player.PlayerSavingAccount, error)
 		FindOnePlayerCredential(pctx context.Context, password, email string) (*playerPb.PlayerProfile, error)
 		FindOnePlayerProfileToRefresh(pctx context.Context, playerId string) (*playerPb.PlayerProfile, error)
+		FindPlayerRoles(pctx context.Context, playerId string) (*playerPb.PlayerRolesRes, error)
+		AddPlayerRoles(pctx context.Context, req *playerPb.AddPlayerRolesReq) (*playerPb.PlayerRolesRes, error)
+		RemovePlayerRoles(pctx context.Context, req *playerPb.RemovePlayerRolesReq) (*playerPb.PlayerRolesRes, error)
+		FindOrCreatePlayerByIdentity(pctx context.Context, req *playerPb.FindOrCreatePlayerByIdentityReq) (*playerPb.PlayerProfile, error)
+		VerifyEmail(pctx context.Context, req *player.VerifyEmailReq) error
+		ResendEmailVerification(pctx context.Context, cfg *config.Config, req *player.ResendEmailVerificationReq) error
+		UpdatePlayerProfile(pctx context.Context, req *player.UpdatePlayerProfileReq) (*player.PlayerProfile, error)
+		ChangeEmail(pctx context.Context, cfg *config.Config, req *player.ChangeEmailReq) (*player.PlayerProfile, error)
+		UploadAvatar(pctx context.Context, cfg *config.Config, playerId string, body io.Reader) (*player.PlayerProfile, error)
+		DeletePlayer(pctx context.Context, cfg *config.Config, req *player.DeletePlayerReq) error
+		DeletePlayerTransactions(pctx context.Context, req *player.PlayerDeletedEvent) error
+		ChangePassword(pctx context.Context, cfg *config.Config, req *player.ChangePasswordReq) error
+		ForgotPassword(pctx context.Context, cfg *config.Config, req *player.ForgotPasswordReq) error
+		ResetPassword(pctx context.Context, cfg *config.Config, req *player.ResetPasswordReq) error
 		DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq)
 		RollbackPlayerTransaction(pctx context.Context, req *player.RollbackPlayerTransactionReq)
 		AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq)
+		AddWishlistItem(pctx context.Context, playerId string, req *player.AddWishlistItemReq) (*player.PlayerWishlist, error)
+		FindWishlist(pctx context.Context, playerId string) ([]*player.PlayerWishlist, error)
+		RemoveWishlistItem(pctx context.Context, playerId, itemId string) error
+		DeletePlayerWishlists(pctx context.Context, req *player.PlayerDeletedEvent) error
+		NotifyItemAlert(pctx context.Context, req *item.ItemAlertEvent)
+		FindNotifications(pctx context.Context, cfg *config.Config, playerId string, req *player.NotificationSearchReq) (*models.PaginateRes, error)
+		ReadNotification(pctx context.Context, playerId, notificationId string) error
+		ReadAllNotifications(pctx context.Context, playerId string) (int64, error)
 	}
 
 	playerUsecase struct {
@@ -47,7 +89,7 @@ func (u *playerUsecase) UpserOffset(pctx context.Context, offset int64) error {
 	return u.playerRepository.UpserOffset(pctx, offset)
 }
 
-func (u *playerUsecase) CreatePlayer(pctx context.Context, req *player.CreatePlayerReq) (*player.PlayerProfile, error) {
+func (u *playerUsecase) CreatePlayer(pctx context.Context, cfg *config.Config, req *player.CreatePlayerReq) (*player.PlayerProfile, error) {
 	if !u.playerRepository.IsUniquePlayer(pctx, req.Email, req.Username) {
 		return nil, errors.New("error: email or username already exist")
 	}
@@ -60,11 +102,12 @@ func (u *playerUsecase) CreatePlayer(pctx context.Context, req *player.CreatePla
 
 	// Insert one player
 	playerId, err := u.playerRepository.InsertOnePlayer(pctx, &player.Player{
-		Email:     req.Email,
-		Password:  string(hashedPassword),
-		Username:  req.Username,
-		CreatedAt: utils.LocalTime(),
-		UpdatedAt: utils.LocalTime(),
+		Email:      req.Email,
+		Password:   string(hashedPassword),
+		Username:   req.Username,
+		IsVerified: false,
+		CreatedAt:  utils.LocalTime(),
+		UpdatedAt:  utils.LocalTime(),
 		PlayerRoles: []player.PlayerRole{
 			{
 				RoleTitle: "Player",
@@ -72,25 +115,298 @@ func (u *playerUsecase) CreatePlayer(pctx context.Context, req *player.CreatePla
 			},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that: the player is created even if the mail is not sent, it can be sent again later
+	if err := u.sendEmailVerification(pctx, cfg, playerId.Hex(), req.Email, req.Username); err != nil {
+		log.Printf("Error: CreatePlayer: %s", err.Error())
+	}
 
 	return u.FindOnePlayerProfile(pctx, playerId.Hex())
 }
 
+func (u *playerUsecase) VerifyEmail(pctx context.Context, req *player.VerifyEmailReq) error {
+	if req.Token == "" {
+		return errors.New("error: token is required")
+	}
+
+	verification, err := u.playerRepository.UseOneEmailVerification(pctx, hashToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	return u.playerRepository.UpdateOnePlayerVerified(pctx, verification.PlayerId, verification.Email)
+}
+
+// Note that: the resend does not need a login, a player that can not login before the verify must still get a token,
+// the result is always ok so the endpoint can not be used to find out which emails exist
+func (u *playerUsecase) ResendEmailVerification(pctx context.Context, cfg *config.Config, req *player.ResendEmailVerificationReq) error {
+	if req.Email == "" {
+		return errors.New("error: email is required")
+	}
+
+	result, err := u.playerRepository.FindOnePlayerCredential(pctx, req.Email)
+	if err != nil || result.IsVerified {
+		return nil
+	}
+
+	go func() {
+		if err := u.sendEmailVerification(context.Background(), cfg, result.Id.Hex(), result.Email, result.Username); err != nil {
+			log.Printf("Error: ResendEmailVerification: %s", err.Error())
+		}
+	}()
+
+	return nil
+}
+
+func (u *playerUsecase) sendEmailVerification(pctx context.Context, cfg *config.Config, playerId, email, username string) error {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	now := utils.LocalTime()
+	expiresAt := now.Add(time.Duration(cfg.Verify.EmailDuration) * time.Second)
+
+	if err := u.playerRepository.InsertOneEmailVerification(pctx, &player.EmailVerification{
+		PlayerId:  playerId,
+		Email:     email,
+		TokenHash: tokenHash,
+		IsUsed:    false,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+
+	return u.playerRepository.SendNotification(pctx, cfg, &notifier.Message{
+		To:      email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse this token to verify your email: %s\nThe token expires at %s.",
+			username,
+			token,
+			expiresAt.Format(time.RFC1123),
+		),
+	})
+}
+
 func (u *playerUsecase) FindOnePlayerProfile(pctx context.Context, playerId string) (*player.PlayerProfile, error) {
 	result, err := u.playerRepository.FindOnePlayerProfile(pctx, playerId)
 	if err != nil {
 		return nil, err
 	}
 
+	return playerProfileBsonToRes(result), nil
+}
+
+func playerProfileBsonToRes(result *player.PlayerProfileBson) *player.PlayerProfile {
 	loc, _ := time.LoadLocation("Asia/Bangkok")
 
 	return &player.PlayerProfile{
-		Id:        result.Id.Hex(),
-		Email:     result.Email,
-		Username:  result.Username,
-		CreatedAt: result.CreatedAt.In(loc),
-		UpdatedAt: result.UpdatedAt.In(loc),
-	}, nil
+		Id:          result.Id.Hex(),
+		Email:       result.Email,
+		Username:    result.Username,
+		DisplayName: result.DisplayName,
+		AvatarUrl:   result.AvatarUrl,
+		IsVerified:  result.IsVerified,
+		IsDeleted:   result.IsDeleted,
+		CreatedAt:   result.CreatedAt.In(loc),
+		UpdatedAt:   result.UpdatedAt.In(loc),
+	}
+}
+
+// Note that: email and username match from the start and ignore the case
+func (u *playerUsecase) SearchPlayers(pctx context.Context, cfg *config.Config, req *player.PlayerSearchReq) (*models.PaginateRes, error) {
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "player", &req.PaginateReq, playerSorts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.D{}
+
+	if email := strings.TrimSpace(req.Email); email != "" {
+		filter = append(filter, bson.E{"email", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(email), Options: "i"}})
+	}
+	if username := strings.TrimSpace(req.Username); username != "" {
+		filter = append(filter, bson.E{"username", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(username), Options: "i"}})
+	}
+
+	createdAt := bson.D{}
+	if req.RegisteredFrom != "" {
+		from, err := parseSearchDate(req.RegisteredFrom)
+		if err != nil {
+			return nil, errors.New("error: registered_from is invalid")
+		}
+		createdAt = append(createdAt, bson.E{"$gte", from})
+	}
+	if req.RegisteredTo != "" {
+		to, err := parseSearchDate(req.RegisteredTo)
+		if err != nil {
+			return nil, errors.New("error: registered_to is invalid")
+		}
+		createdAt = append(createdAt, bson.E{"$lte", to})
+	}
+	if len(createdAt) > 0 {
+		filter = append(filter, bson.E{"created_at", createdAt})
+	}
+
+	countFilter := append(bson.D{}, filter...)
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
+	}
+
+	results, err := u.playerRepository.FindManyPlayers(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, prev := models.Page(paginate, results, func(v *player.PlayerProfileBson) (any, primitive.ObjectID) {
+		switch paginate.Sort.Name {
+		case "email":
+			return v.Email, v.Id
+		case "username":
+			return v.Username, v.Id
+		case "created_at":
+			return v.CreatedAt, v.Id
+		}
+		return v.Id, v.Id
+	})
+
+	total, err := u.playerRepository.CountPlayers(pctx, countFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	query := paginate.Query()
+	for key, value := range map[string]string{
+		"email":           req.Email,
+		"username":        req.Username,
+		"registered_from": req.RegisteredFrom,
+		"registered_to":   req.RegisteredTo,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	data := make([]*player.PlayerProfile, 0)
+	for _, v := range results {
+		data = append(data, playerProfileBsonToRes(v))
+	}
+
+	return paginate.NewPaginateRes(data, total, cfg.Paginate.PlayerNextPageBasedUrl, query, next, prev), nil
+}
+
+var playerSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+	{Name: "email", Field: "email", Kind: models.SortKindString},
+	{Name: "username", Field: "username", Kind: models.SortKindString},
+	{Name: "created_at", Field: "created_at", Kind: models.SortKindTime},
+}
+
+func parseSearchDate(value string) (time.Time, error) {
+	if result, err := time.Parse(time.RFC3339, value); err == nil {
+		return result, nil
+	}
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// Note that: the parts come from the player, inventory and auth services, one that fails does not fail the others
+func (u *playerUsecase) FindPlayerOverview(pctx context.Context, cfg *config.Config, playerId string) (*player.PlayerOverview, error) {
+	playerId = strings.TrimPrefix(playerId, "player:")
+
+	result, err := u.playerRepository.FindOnePlayerProfileToRefresh(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	roleCode := 0
+	for _, v := range result.PlayerRoles {
+		roleCode += v.RoleCode
+	}
+
+	res := &player.PlayerOverview{
+		Profile: playerProfileBsonToRes(&player.PlayerProfileBson{
+			Id:          result.Id,
+			Email:       result.Email,
+			Username:    result.Username,
+			DisplayName: result.DisplayName,
+			AvatarUrl:   result.AvatarUrl,
+			IsVerified:  result.IsVerified,
+			IsDeleted:   result.IsDeleted,
+			CreatedAt:   result.CreatedAt,
+			UpdatedAt:   result.UpdatedAt,
+		}),
+		RoleCode: roleCode,
+		Inventory: &player.PlayerOverviewInventory{
+			Items: make([]*player.PlayerOverviewItem, 0),
+		},
+		Sessions: make([]*player.PlayerOverviewSession, 0),
+		Errors:   make([]string, 0),
+	}
+
+	// Note that: the ledger keeps the player id with the "player:" prefix
+	savingAccount, err := u.playerRepository.GetPlayerSavingAccount(pctx, "player:"+playerId)
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+	} else {
+		res.Balance = savingAccount.Balance
+	}
+
+	inventory, err := u.playerRepository.FindPlayerInventory(pctx, cfg.Grpc.InventoryUrl, &inventoryPb.FindPlayerInventoryReq{
+		PlayerId: playerId,
+		Limit:    20,
+	})
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+	} else {
+		res.Inventory.Total = inventory.Total
+		for _, v := range inventory.Items {
+			res.Inventory.Items = append(res.Inventory.Items, &player.PlayerOverviewItem{
+				InventoryId: v.InventoryId,
+				ItemId:      v.ItemId,
+				Title:       v.Title,
+				Price:       v.Price,
+				Damage:      int(v.Damage),
+				ImageUrl:    v.ImageUrl,
+			})
+		}
+	}
+
+	sessions, err := u.playerRepository.FindPlayerSessions(pctx, cfg.Grpc.AuthUrl, &authPb.FindPlayerSessionsReq{
+		PlayerId: playerId,
+	})
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+	} else {
+		for _, v := range sessions.Sessions {
+			res.Sessions = append(res.Sessions, &player.PlayerOverviewSession{
+				CredentialId: v.CredentialId,
+				RoleCode:     int(v.RoleCode),
+				CreatedAt:    v.CreatedAt,
+				UpdatedAt:    v.UpdatedAt,
+			})
+		}
+		if sessions.Ban != nil {
+			res.Ban = &player.PlayerOverviewBan{
+				Kind:      sessions.Ban.Kind,
+				Reason:    sessions.Ban.Reason,
+				ExpiresAt: sessions.Ban.ExpiresAt,
+				CreatedBy: sessions.Ban.CreatedBy,
+				CreatedAt: sessions.Ban.CreatedAt,
+			}
+		}
+	}
+
+	return res, nil
 }
 
 func (u *playerUsecase) AddPlayerMoney(pctx context.Context, req *player.CreatePlayerTransactionReq) (*player.PlayerSavingAccount, error) {
@@ -130,12 +446,13 @@ func (u *playerUsecase) FindOnePlayerCredential(pctx context.Context, password,
 	loc, _ := time.LoadLocation("Asia/Bangkok")
 
 	return &playerPb.PlayerProfile{
-		Id:        result.Id.Hex(),
-		Email:     result.Email,
-		Username:  result.Username,
-		RoleCode:  int32(roleCode),
-		CreatedAt: result.CreatedAt.In(loc).String(),
-		UpdatedAt: result.UpdatedAt.In(loc).String(),
+		Id:         result.Id.Hex(),
+		Email:      result.Email,
+		Username:   result.Username,
+		RoleCode:   int32(roleCode),
+		CreatedAt:  result.CreatedAt.In(loc).String(),
+		UpdatedAt:  result.UpdatedAt.In(loc).String(),
+		IsVerified: result.IsVerified,
 	}, nil
 }
 
@@ -145,6 +462,11 @@ func (u *playerUsecase) FindOnePlayerProfileToRefresh(pctx context.Context, play
 		return nil, err
 	}
 
+	// Note that: a deleted player can not refresh a token anymore
+	if result.IsDeleted {
+		return nil, errors.New("error: player profile not found")
+	}
+
 	roleCode := 0
 	for _, v := range result.PlayerRoles {
 		roleCode += v.RoleCode
@@ -153,100 +475,822 @@ func (u *playerUsecase) FindOnePlayerProfileToRefresh(pctx context.Context, play
 	loc, _ := time.LoadLocation("Asia/Bangkok")
 
 	return &playerPb.PlayerProfile{
-		Id:        result.Id.Hex(),
-		Email:     result.Email,
-		Username:  result.Username,
-		RoleCode:  int32(roleCode),
-		CreatedAt: result.CreatedAt.In(loc).String(),
-		UpdatedAt: result.UpdatedAt.In(loc).String(),
+		Id:         result.Id.Hex(),
+		Email:      result.Email,
+		Username:   result.Username,
+		RoleCode:   int32(roleCode),
+		CreatedAt:  result.CreatedAt.In(loc).String(),
+		UpdatedAt:  result.UpdatedAt.In(loc).String(),
+		IsVerified: result.IsVerified,
 	}, nil
 }
 
+func (u *playerUsecase) FindPlayerRoles(pctx context.Context, playerId string) (*playerPb.PlayerRolesRes, error) {
+	result, err := u.playerRepository.FindOnePlayerProfileToRefresh(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return playerRolesToRes(result.Id.Hex(), result.PlayerRoles), nil
+}
+
+// Note that: the roles are added and removed by the repository in one update, the roles are not read
+// and written back so a grant and a revoke at the same moment do not lose each other
+func (u *playerUsecase) AddPlayerRoles(pctx context.Context, req *playerPb.AddPlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	roles := make([]player.PlayerRole, 0, len(req.Roles))
+	for _, r := range req.Roles {
+		roles = append(roles, player.PlayerRole{
+			RoleTitle: r.RoleTitle,
+			RoleCode:  int(r.RoleCode),
+		})
+	}
+
+	if err := u.playerRepository.AddManyPlayerRoles(pctx, req.PlayerId, roles); err != nil {
+		return nil, err
+	}
+
+	return u.FindPlayerRoles(pctx, req.PlayerId)
+}
+
+func (u *playerUsecase) RemovePlayerRoles(pctx context.Context, req *playerPb.RemovePlayerRolesReq) (*playerPb.PlayerRolesRes, error) {
+	roleCodes := make([]int, 0, len(req.RoleCodes))
+	for _, code := range req.RoleCodes {
+		roleCodes = append(roleCodes, int(code))
+	}
+
+	if err := u.playerRepository.RemoveManyPlayerRoles(pctx, req.PlayerId, roleCodes); err != nil {
+		return nil, err
+	}
+
+	return u.FindPlayerRoles(pctx, req.PlayerId)
+}
+
+func playerRolesToRes(playerId string, roles []player.PlayerRole) *playerPb.PlayerRolesRes {
+	res := &playerPb.PlayerRolesRes{
+		PlayerId: playerId,
+		Roles:    make([]*playerPb.PlayerRole, 0),
+	}
+	for _, v := range roles {
+		res.Roles = append(res.Roles, &playerPb.PlayerRole{
+			RoleTitle: v.RoleTitle,
+			RoleCode:  int32(v.RoleCode),
+		})
+	}
+	return res
+}
+
+func (u *playerUsecase) FindOrCreatePlayerByIdentity(pctx context.Context, req *playerPb.FindOrCreatePlayerByIdentityReq) (*playerPb.PlayerProfile, error) {
+	if req.Provider == "" || req.Subject == "" {
+		return nil, errors.New("error: provider and subject are required")
+	}
+
+	// Linked player
+	result, err := u.playerRepository.FindOnePlayerByIdentity(pctx, req.Provider, req.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		return playerToProfilePb(result), nil
+	}
+
+	if req.Email == "" {
+		return nil, errors.New("error: email is required from the identity provider")
+	}
+	email := strings.ToLower(req.Email)
+
+	identity := &player.PlayerIdentity{
+		Provider: req.Provider,
+		Subject:  req.Subject,
+		Email:    email,
+		LinkedAt: utils.LocalTime(),
+	}
+
+	// Existing player with the same email
+	// Note that: it is linked only when the provider has verified the email, otherwise anyone could take over the account
+	if result, err := u.playerRepository.FindOnePlayerCredential(pctx, email); err == nil {
+		if !req.EmailVerified {
+			return nil, errors.New("error: email is already registered, please login with password")
+		}
+
+		if err := u.playerRepository.PushOnePlayerIdentity(pctx, result.Id.Hex(), identity); err != nil {
+			return nil, err
+		}
+		if !result.IsVerified {
+			if err := u.playerRepository.UpdateOnePlayerVerified(pctx, result.Id.Hex(), result.Email); err != nil {
+				return nil, err
+			}
+			result.IsVerified = true
+		}
+
+		return playerToProfilePb(result), nil
+	}
+
+	// New player
+	username, err := u.uniqueUsername(pctx, req.Username, email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that: the password is empty, so the player can login only through the provider until a password is set
+	playerId, err := u.playerRepository.InsertOnePlayer(pctx, &player.Player{
+		Email:      email,
+		Password:   "",
+		Username:   username,
+		IsVerified: req.EmailVerified,
+		CreatedAt:  utils.LocalTime(),
+		UpdatedAt:  utils.LocalTime(),
+		PlayerRoles: []player.PlayerRole{
+			{
+				RoleTitle: "Player",
+				RoleCode:  0,
+			},
+		},
+		Identities: []player.PlayerIdentity{*identity},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.FindOnePlayerProfileToRefresh(pctx, playerId.Hex())
+}
+
+func (u *playerUsecase) uniqueUsername(pctx context.Context, username, email string) (string, error) {
+	base := strings.TrimSpace(username)
+	if base == "" {
+		base = strings.Split(email, "@")[0]
+	}
+	if len(base) > 56 {
+		base = base[:56]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if u.playerRepository.IsUniquePlayer(pctx, email, candidate) {
+			return candidate, nil
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", errors.New("error: generate username failed")
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+
+	return "", errors.New("error: username is not available")
+}
+
+func playerToProfilePb(result *player.Player) *playerPb.PlayerProfile {
+	roleCode := 0
+	for _, v := range result.PlayerRoles {
+		roleCode += v.RoleCode
+	}
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	return &playerPb.PlayerProfile{
+		Id:         result.Id.Hex(),
+		Email:      result.Email,
+		Username:   result.Username,
+		RoleCode:   int32(roleCode),
+		CreatedAt:  result.CreatedAt.In(loc).String(),
+		UpdatedAt:  result.UpdatedAt.In(loc).String(),
+		IsVerified: result.IsVerified,
+	}
+}
+
+func (u *playerUsecase) UpdatePlayerProfile(pctx context.Context, req *player.UpdatePlayerProfileReq) (*player.PlayerProfile, error) {
+	req.PlayerId = strings.TrimPrefix(req.PlayerId, "player:")
+	req.Username = strings.TrimSpace(req.Username)
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+
+	if req.Username == "" && req.DisplayName == "" {
+		return nil, errors.New("error: username or display_name is required")
+	}
+	if len(req.Username) > 64 || len(req.DisplayName) > 64 {
+		return nil, errors.New("error: username and display_name must not be longer than 64 characters")
+	}
+
+	result, err := u.playerRepository.FindOnePlayerProfile(pctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsDeleted {
+		return nil, errors.New("error: player profile not found")
+	}
+
+	if req.Username == result.Username {
+		req.Username = ""
+	}
+	if req.Username != "" && !u.playerRepository.IsUniquePlayer(pctx, "", req.Username) {
+		return nil, errors.New("error: username already exist")
+	}
+
+	if err := u.playerRepository.UpdateOnePlayerProfile(pctx, req); err != nil {
+		return nil, err
+	}
+
+	return u.FindOnePlayerProfile(pctx, req.PlayerId)
+}
+
+func (u *playerUsecase) ChangeEmail(pctx context.Context, cfg *config.Config, req *player.ChangeEmailReq) (*player.PlayerProfile, error) {
+	playerId := strings.TrimPrefix(req.PlayerId, "player:")
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email || len(email) > 255 {
+		return nil, errors.New("error: email is invalid")
+	}
+
+	result, err := u.playerRepository.FindOnePlayerProfileToRefresh(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsDeleted {
+		return nil, errors.New("error: player profile not found")
+	}
+
+	// Note that: a player from a social login has to set a password first, see forgot password
+	if result.Password == "" {
+		return nil, errors.New("error: password is not set")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(result.Password), []byte(req.Password)); err != nil {
+		log.Printf("Error: ChangeEmail: %s", err.Error())
+		return nil, errors.New("error: password is invalid")
+	}
+
+	if email == result.Email {
+		return nil, errors.New("error: new email must be different from the current email")
+	}
+	if !u.playerRepository.IsUniquePlayer(pctx, email, "") {
+		return nil, errors.New("error: email already exist")
+	}
+
+	if err := u.playerRepository.UpdateOnePlayerEmail(pctx, playerId, email); err != nil {
+		return nil, err
+	}
+
+	// A reset token sent to the old email must not be usable anymore
+	if err := u.playerRepository.UpdateManyPasswordResetsUsed(pctx, playerId); err != nil {
+		log.Printf("Error: ChangeEmail: %s", err.Error())
+	}
+
+	if err := u.sendEmailVerification(pctx, cfg, playerId, email, result.Username); err != nil {
+		log.Printf("Error: ChangeEmail: %s", err.Error())
+	}
+
+	// Let the owner of the old email know, in case the account is taken over
+	if err := u.playerRepository.SendNotification(pctx, cfg, &notifier.Message{
+		To:      result.Email,
+		Subject: "Your email has been changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email of your account has been changed to %s.\nIf you did not do this, please contact us right away.",
+			result.Username,
+			email,
+		),
+	}); err != nil {
+		log.Printf("Error: ChangeEmail: %s", err.Error())
+	}
+
+	return u.FindOnePlayerProfile(pctx, playerId)
+}
+
+// Note that: the type is detected from the content, the file name and the header from the client are not trusted
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+func (u *playerUsecase) UploadAvatar(pctx context.Context, cfg *config.Config, playerId string, body io.Reader) (*player.PlayerProfile, error) {
+	playerId = strings.TrimPrefix(playerId, "player:")
+
+	content, err := io.ReadAll(io.LimitReader(body, cfg.Blob.MaxAvatarSize+1))
+	if err != nil {
+		log.Printf("Error: UploadAvatar: %s", err.Error())
+		return nil, errors.New("error: read avatar failed")
+	}
+	if len(content) == 0 {
+		return nil, errors.New("error: avatar is required")
+	}
+	if int64(len(content)) > cfg.Blob.MaxAvatarSize {
+		return nil, fmt.Errorf("error: avatar must not be larger than %d bytes", cfg.Blob.MaxAvatarSize)
+	}
+
+	contentType := http.DetectContentType(content)
+	extension, ok := avatarExtensions[contentType]
+	if !ok {
+		return nil, errors.New("error: avatar must be a png, jpeg, gif or webp image")
+	}
+
+	result, err := u.playerRepository.FindOnePlayerProfileToRefresh(pctx, playerId)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsDeleted {
+		return nil, errors.New("error: player profile not found")
+	}
+
+	// Note that: a new key for every upload, so a cached old avatar is never served as the new one
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, errors.New("error: generate avatar name failed")
+	}
+	key := fmt.Sprintf("avatars/%s/%s%s", playerId, hex.EncodeToString(suffix), extension)
+
+	avatarUrl, err := u.playerRepository.PutOneBlob(pctx, cfg, key, contentType, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.playerRepository.UpdateOnePlayerAvatar(pctx, playerId, avatarUrl, key); err != nil {
+		u.playerRepository.DeleteOneBlob(pctx, cfg, key)
+		return nil, err
+	}
+
+	if result.AvatarKey != "" {
+		if err := u.playerRepository.DeleteOneBlob(pctx, cfg, result.AvatarKey); err != nil {
+			log.Printf("Error: UploadAvatar: old avatar is not deleted: %s", err.Error())
+		}
+	}
+
+	return u.FindOnePlayerProfile(pctx, playerId)
+}
+
+// Note that: the player is anonymized here, the other services remove their data when they consume the event
+func (u *playerUsecase) DeletePlayer(pctx context.Context, cfg *config.Config, req *player.DeletePlayerReq) error {
+	playerId := strings.TrimPrefix(req.PlayerId, "player:")
+
+	result, err := u.playerRepository.FindOnePlayerProfileToRefresh(pctx, playerId)
+	if err != nil {
+		return err
+	}
+
+	// A deleted player only publishes the event again, in case it failed the first time
+	if !result.IsDeleted {
+		if result.Password != "" {
+			if err := bcrypt.CompareHashAndPassword([]byte(result.Password), []byte(req.Password)); err != nil {
+				log.Printf("Error: DeletePlayer: %s", err.Error())
+				return errors.New("error: password is invalid")
+			}
+		}
+
+		if err := u.playerRepository.AnonymizeOnePlayer(pctx, playerId); err != nil {
+			return err
+		}
+
+		if result.AvatarKey != "" {
+			if err := u.playerRepository.DeleteOneBlob(pctx, cfg, result.AvatarKey); err != nil {
+				log.Printf("Error: DeletePlayer: avatar is not deleted: %s", err.Error())
+			}
+		}
+
+		if err := u.playerRepository.UpdateManyPasswordResetsUsed(pctx, playerId); err != nil {
+			log.Printf("Error: DeletePlayer: %s", err.Error())
+		}
+	}
+
+	// Sign out every session now, the auth service also removes them when it consumes the event
+	if _, err := u.playerRepository.RevokePlayerCredentials(pctx, cfg.Grpc.AuthUrl, &authPb.RevokePlayerCredentialsReq{
+		PlayerId: playerId,
+	}); err != nil {
+		log.Printf("Error: DeletePlayer: sessions are not revoked: %s", err.Error())
+		return errors.New("error: player is deleted but the sessions are not revoked, please try again")
+	}
+
+	if err := u.playerRepository.PlayerDeletedRes(pctx, cfg, &player.PlayerDeletedEvent{
+		PlayerId: playerId,
+	}); err != nil {
+		return errors.New("error: player is deleted but the cleanup is not started, please try again")
+	}
+
+	return nil
+}
+
+func (u *playerUsecase) DeletePlayerTransactions(pctx context.Context, req *player.PlayerDeletedEvent) error {
+	return u.playerRepository.DeleteManyPlayerTransactions(pctx, req.PlayerId)
+}
+
+func (u *playerUsecase) ChangePassword(pctx context.Context, cfg *config.Config, req *player.ChangePasswordReq) error {
+	if err := validateNewPassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	playerId := strings.TrimPrefix(req.PlayerId, "player:")
+
+	result, err := u.playerRepository.FindOnePlayerProfileToRefresh(pctx, playerId)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(result.Password), []byte(req.CurrentPassword)); err != nil {
+		log.Printf("Error: ChangePassword: %s", err.Error())
+		return errors.New("error: current password is invalid")
+	}
+
+	if req.CurrentPassword == req.NewPassword {
+		return errors.New("error: new password must be different from the current password")
+	}
+
+	return u.updatePassword(pctx, cfg, playerId, req.NewPassword)
+}
+
+// Note that: the result is always ok, so the endpoint can not be used to find out which emails exist,
+// the token is made and sent after the return so a registered email does not answer slower or fail differently
+func (u *playerUsecase) ForgotPassword(pctx context.Context, cfg *config.Config, req *player.ForgotPasswordReq) error {
+	if req.Email == "" {
+		return errors.New("error: email is required")
+	}
+
+	result, err := u.playerRepository.FindOnePlayerCredential(pctx, req.Email)
+	if err != nil {
+		return nil
+	}
+
+	go u.sendPasswordReset(context.Background(), cfg, result)
+
+	return nil
+}
+
+func (u *playerUsecase) sendPasswordReset(pctx context.Context, cfg *config.Config, result *player.Player) {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		log.Printf("Error: ForgotPassword: %s", err.Error())
+		return
+	}
+
+	now := utils.LocalTime()
+	expiresAt := now.Add(time.Duration(cfg.Password.ResetDuration) * time.Second)
+
+	if err := u.playerRepository.InsertOnePasswordReset(pctx, &player.PasswordReset{
+		PlayerId:  result.Id.Hex(),
+		TokenHash: tokenHash,
+		IsUsed:    false,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		log.Printf("Error: ForgotPassword: %s", err.Error())
+		return
+	}
+
+	if err := u.playerRepository.SendNotification(pctx, cfg, &notifier.Message{
+		To:      result.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse this token to reset your password: %s\nThe token can be used only once and expires at %s.\nIf you did not request this, you can ignore this message.",
+			result.Username,
+			token,
+			expiresAt.Format(time.RFC1123),
+		),
+	}); err != nil {
+		log.Printf("Error: ForgotPassword: %s", err.Error())
+	}
+}
+
+func (u *playerUsecase) ResetPassword(pctx context.Context, cfg *config.Config, req *player.ResetPasswordReq) error {
+	if req.Token == "" {
+		return errors.New("error: token is required")
+	}
+	if err := validateNewPassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	reset, err := u.playerRepository.UseOnePasswordReset(pctx, hashToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	return u.updatePassword(pctx, cfg, reset.PlayerId, req.NewPassword)
+}
+
+func (u *playerUsecase) updatePassword(pctx context.Context, cfg *config.Config, playerId, newPassword string) error {
+	// Hashing password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("error: failed to hash password")
+	}
+
+	if err := u.playerRepository.UpdateOnePlayerPassword(pctx, playerId, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	// Any reset token issued before the change must not be usable anymore
+	if err := u.playerRepository.UpdateManyPasswordResetsUsed(pctx, playerId); err != nil {
+		log.Printf("Error: updatePassword: %s", err.Error())
+	}
+
+	// Sign out every session of the player
+	if _, err := u.playerRepository.RevokePlayerCredentials(pctx, cfg.Grpc.AuthUrl, &authPb.RevokePlayerCredentialsReq{
+		PlayerId: playerId,
+	}); err != nil {
+		log.Printf("Error: updatePassword: password is changed but sessions are not revoked: %s", err.Error())
+		return errors.New("error: password is changed but sessions are not revoked, please try again")
+	}
+
+	return nil
+}
+
+func validateNewPassword(password string) error {
+	if password == "" {
+		return errors.New("error: new password is required")
+	}
+	if len(password) > 32 {
+		return errors.New("error: new password must not be longer than 32 characters")
+	}
+	return nil
+}
+
+// Note that: only the hash of the token is stored
+func newToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("Error: newToken: %s", err.Error())
+		return "", "", errors.New("error: generate reset token failed")
+	}
+
+	token := hex.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	hashed := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hashed[:])
+}
+
 func (u *playerUsecase) DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) {
+	res := &payment.PaymentTransferRes{
+		OrderId:       req.OrderId,
+		InventoryId:   "",
+		TransactionId: "",
+		PlayerId:      req.PlayerId,
+		ItemId:        "",
+		Amount:        req.Amount,
+		Error:         "",
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		res.Error = "error: order is rolled back"
+		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, res)
+		return
+	}
+
 	// Get saving account
 	savingAccount, err := u.playerRepository.GetPlayerSavingAccount(pctx, req.PlayerId)
 	if err != nil {
-		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        "",
-			Amount:        req.Amount,
-			Error:         err.Error(),
-		})
+		res.Error = err.Error()
+		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, res)
 		return
 	}
 
 	if savingAccount.Balance < math.Abs(req.Amount) {
 		log.Printf("Error: DockedPlayerMoneyRes failed: %s", "not enough money")
-		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        "",
-			Amount:        req.Amount,
-			Error:         "error: not enough money",
-		})
+		res.Error = "error: not enough money"
+		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, res)
 		return
 	}
 
 	// Insert one player transaction
-	transactionId, err := u.playerRepository.InsertOnePlayerTranscation(pctx, &player.PlayerTransaction{
-		PlayerId:  req.PlayerId,
-		Amount:    req.Amount,
-		CreatedAt: utils.LocalTime(),
-	})
+	transactionId, err := u.insertOrderTransaction(pctx, req)
 	if err != nil {
-		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        "",
-			Amount:        req.Amount,
-			Error:         err.Error(),
-		})
+		res.Error = err.Error()
+		u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, res)
 		return
 	}
 
-	u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
+	res.TransactionId = transactionId.Hex()
+	u.playerRepository.DockedPlayerMoneyRes(pctx, cfg, res)
+}
+
+func (u *playerUsecase) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) {
+	res := &payment.PaymentTransferRes{
+		OrderId:       req.OrderId,
 		InventoryId:   "",
-		TransactionId: transactionId.Hex(),
+		TransactionId: "",
 		PlayerId:      req.PlayerId,
 		ItemId:        "",
 		Amount:        req.Amount,
 		Error:         "",
-	})
-}
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		res.Error = "error: order is rolled back"
+		u.playerRepository.AddPlayerMoneyRes(pctx, cfg, res)
+		return
+	}
 
-func (u *playerUsecase) AddPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *player.CreatePlayerTransactionReq) {
 	// Insert one player transaction
+	transactionId, err := u.insertOrderTransaction(pctx, req)
+	if err != nil {
+		res.Error = err.Error()
+		u.playerRepository.AddPlayerMoneyRes(pctx, cfg, res)
+		return
+	}
+
+	res.TransactionId = transactionId.Hex()
+	u.playerRepository.AddPlayerMoneyRes(pctx, cfg, res)
+}
+
+// Note that: the step of the order can come after its rollback when the payment did not wait for it,
+// the rollback is checked again after the insert so the transaction undoes itself
+func (u *playerUsecase) insertOrderTransaction(pctx context.Context, req *player.CreatePlayerTransactionReq) (primitive.ObjectID, error) {
 	transactionId, err := u.playerRepository.InsertOnePlayerTranscation(pctx, &player.PlayerTransaction{
+		OrderId:   req.OrderId,
 		PlayerId:  req.PlayerId,
 		Amount:    req.Amount,
 		CreatedAt: utils.LocalTime(),
 	})
 	if err != nil {
-		u.playerRepository.AddPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
-			InventoryId:   "",
-			TransactionId: "",
-			PlayerId:      req.PlayerId,
-			ItemId:        "",
-			Amount:        req.Amount,
-			Error:         err.Error(),
-		})
+		return primitive.NilObjectID, err
+	}
+
+	if u.isOrderRolledBack(pctx, req.OrderId) {
+		u.playerRepository.DeleteManyOrderTransactions(pctx, req.OrderId)
+		return primitive.NilObjectID, errors.New("error: order is rolled back")
+	}
+
+	return transactionId, nil
+}
+
+func (u *playerUsecase) isOrderRolledBack(pctx context.Context, orderId string) bool {
+	return orderId != "" && u.playerRepository.FindOneTransactionRollback(pctx, orderId)
+}
+
+func (u *playerUsecase) RollbackPlayerTransaction(pctx context.Context, req *player.RollbackPlayerTransactionReq) {
+	if req.OrderId == "" {
+		u.playerRepository.DeleteOnePlayerTransaction(pctx, req.TransactionId)
 		return
 	}
 
-	u.playerRepository.AddPlayerMoneyRes(pctx, cfg, &payment.PaymentTransferRes{
-		InventoryId:   "",
-		TransactionId: transactionId.Hex(),
-		PlayerId:      req.PlayerId,
-		ItemId:        "",
-		Amount:        req.Amount,
-		Error:         "",
+	// Note that: the rollback is kept before the delete, a transaction that is inserted in between sees it
+	if err := u.playerRepository.UpsertOneTransactionRollback(pctx, req.OrderId); err != nil {
+		return
+	}
+	u.playerRepository.DeleteManyOrderTransactions(pctx, req.OrderId)
+}
+
+const maxWishlistItems = 100
+
+func (u *playerUsecase) AddWishlistItem(pctx context.Context, playerId string, req *player.AddWishlistItemReq) (*player.PlayerWishlist, error) {
+	itemId := strings.TrimPrefix(req.ItemId, "item:")
+	if _, err := primitive.ObjectIDFromHex(itemId); err != nil {
+		return nil, errors.New("error: item_id is invalid")
+	}
+	playerId = "player:" + strings.TrimPrefix(playerId, "player:")
+
+	count, err := u.playerRepository.CountPlayerWishlists(pctx, bson.D{{"player_id", playerId}})
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWishlistItems {
+		return nil, fmt.Errorf("error: wishlist must not have more than %d items", maxWishlistItems)
+	}
+
+	result := &player.PlayerWishlist{
+		PlayerId:  playerId,
+		ItemId:    "item:" + itemId,
+		CreatedAt: utils.LocalTime(),
+	}
+	if err := u.playerRepository.InsertOnePlayerWishlist(pctx, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *playerUsecase) FindWishlist(pctx context.Context, playerId string) ([]*player.PlayerWishlist, error) {
+	return u.playerRepository.FindManyPlayerWishlists(
+		pctx,
+		bson.D{{"player_id", "player:" + strings.TrimPrefix(playerId, "player:")}},
+		[]*options.FindOptions{options.Find().SetSort(bson.D{{"_id", -1}}).SetLimit(maxWishlistItems)},
+	)
+}
+
+func (u *playerUsecase) RemoveWishlistItem(pctx context.Context, playerId, itemId string) error {
+	return u.playerRepository.DeleteOnePlayerWishlist(
+		pctx,
+		"player:"+strings.TrimPrefix(playerId, "player:"),
+		"item:"+strings.TrimPrefix(itemId, "item:"),
+	)
+}
+
+func (u *playerUsecase) DeletePlayerWishlists(pctx context.Context, req *player.PlayerDeletedEvent) error {
+	if err := u.playerRepository.DeleteManyPlayerWishlists(pctx, req.PlayerId); err != nil {
+		return err
+	}
+	return u.playerRepository.DeleteManyPlayerNotifications(pctx, req.PlayerId)
+}
+
+// NotifyItemAlert notifies every player who wishlisted the item, the players are notified in batches
+func (u *playerUsecase) NotifyItemAlert(pctx context.Context, req *item.ItemAlertEvent) {
+	message := ""
+	switch req.Type {
+	case item.AlertPriceDrop:
+		message = fmt.Sprintf("%s dropped from %v to %v", req.Title, req.PreviousPrice, req.Price)
+	case item.AlertRestock:
+		message = fmt.Sprintf("%s is available again for %v", req.Title, req.Price)
+	default:
+		log.Printf("Error: NotifyItemAlert failed: type %s is unknown", req.Type)
+		return
+	}
+
+	wishlists, err := u.playerRepository.FindManyPlayerWishlists(pctx, bson.D{{"item_id", req.ItemId}}, nil)
+	if err != nil {
+		return
+	}
+
+	now := utils.LocalTime()
+	notifications := make([]*player.PlayerNotification, 0)
+	for i, v := range wishlists {
+		notifications = append(notifications, &player.PlayerNotification{
+			PlayerId:      v.PlayerId,
+			AlertId:       req.AlertId,
+			Type:          req.Type,
+			ItemId:        req.ItemId,
+			Title:         req.Title,
+			Message:       message,
+			Price:         req.Price,
+			PreviousPrice: req.PreviousPrice,
+			CreatedAt:     now,
+		})
+
+		if len(notifications) == 1000 || i == len(wishlists)-1 {
+			if err := u.playerRepository.InsertManyPlayerNotifications(pctx, notifications); err != nil {
+				log.Printf("Error: NotifyItemAlert failed: alert %s is not notified to %d players", req.AlertId, len(notifications))
+			}
+			notifications = make([]*player.PlayerNotification, 0)
+		}
+	}
+}
+
+var notificationSorts = []*models.SortField{
+	{Name: "id", Field: "_id", Kind: models.SortKindId},
+}
+
+func (u *playerUsecase) FindNotifications(pctx context.Context, cfg *config.Config, playerId string, req *player.NotificationSearchReq) (*models.PaginateRes, error) {
+	// The newest notification is the first unless the direction is set
+	if req.Direction == "" {
+		req.Direction = "desc"
+	}
+	playerId = "player:" + strings.TrimPrefix(playerId, "player:")
+
+	paginate, err := models.ParsePaginate(cfg.Paginate.CursorSecret, "player_notifications:"+playerId+":"+req.Status, &req.PaginateReq, notificationSorts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := paginate.Query()
+
+	// Filter
+	filter := bson.D{{"player_id", playerId}}
+
+	switch req.Status {
+	case "":
+	case "read":
+		filter = append(filter, bson.E{"is_read", true})
+	case "unread":
+		filter = append(filter, bson.E{"is_read", false})
+	default:
+		return nil, errors.New("error: status must be read or unread")
+	}
+	if req.Status != "" {
+		query.Set("status", req.Status)
+	}
+
+	countFilter := append(bson.D{}, filter...)
+
+	if start, ok, err := paginate.Filter(); err != nil {
+		return nil, err
+	} else if ok {
+		filter = append(filter, start)
+	}
+
+	// Find
+	results, err := u.playerRepository.FindManyPlayerNotifications(pctx, filter, paginate.FindOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, prev := models.Page(paginate, results, func(v *player.PlayerNotification) (any, primitive.ObjectID) {
+		return v.Id, v.Id
 	})
+
+	// Count
+	total, err := u.playerRepository.CountPlayerNotifications(pctx, countFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate.NewPaginateRes(results, total, cfg.Paginate.NotificationNextPageBasedUrl, query, next, prev), nil
 }
 
-func (u *playerUsecase) RollbackPlayerTransaction(pctx context.Context, req *player.RollbackPlayerTransactionReq) {
-	u.playerRepository.DeleteOnePlayerTransaction(pctx, req.TransactionId)
+func (u *playerUsecase) ReadNotification(pctx context.Context, playerId, notificationId string) error {
+	notificationId = strings.TrimPrefix(notificationId, "notification:")
+	if _, err := primitive.ObjectIDFromHex(notificationId); err != nil {
+		return errors.New("error: notification_id is invalid")
+	}
+
+	return u.playerRepository.UpdateOnePlayerNotificationRead(pctx, "player:"+strings.TrimPrefix(playerId, "player:"), notificationId, utils.LocalTime())
+}
+
+func (u *playerUsecase) ReadAllNotifications(pctx context.Context, playerId string) (int64, error) {
+	return u.playerRepository.UpdateManyPlayerNotificationsRead(pctx, "player:"+strings.TrimPrefix(playerId, "player:"), utils.LocalTime())
 }
@@ -0,0 +1,110 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+)
+
+type (
+	BlobStoreService interface {
+		// Put stores the content under the key and returns its public url
+		Put(pctx context.Context, key, contentType string, body io.Reader) (string, error)
+		Delete(pctx context.Context, key string) error
+	}
+
+	localBlobStore struct {
+		root    string
+		baseUrl string
+	}
+)
+
+func NewBlobStore(cfg *config.Blob) BlobStoreService {
+	switch cfg.Type {
+	case "s3":
+		return newS3BlobStore(cfg)
+	default:
+		return &localBlobStore{root: cfg.LocalPath, baseUrl: strings.TrimSuffix(cfg.BaseUrl, "/")}
+	}
+}
+
+// Note that: a key is a relative slash separated path, e.g. avatars/<player_id>/<name>.png
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key || strings.Contains(key, "\\") {
+		return "", errors.New("error: blob key is invalid")
+	}
+	return cleaned, nil
+}
+
+// Dev only: store the blob on the local disk, the files are served by the http server
+func (s *localBlobStore) Put(pctx context.Context, key, contentType string, body io.Reader) (string, error) {
+	if s.root == "" {
+		return "", errors.New("error: blob local path is required")
+	}
+
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		log.Printf("Error: Create blob directory failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+
+	// Write to a temp file first, so a half written file is never served
+	file, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		log.Printf("Error: Create blob file failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		log.Printf("Error: Write blob file failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+	if err := file.Close(); err != nil {
+		log.Printf("Error: Close blob file failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		log.Printf("Error: Chmod blob file failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+
+	if err := os.Rename(file.Name(), target); err != nil {
+		log.Printf("Error: Move blob file failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+
+	return s.baseUrl + "/" + key, nil
+}
+
+func (s *localBlobStore) Delete(pctx context.Context, key string) error {
+	if s.root == "" {
+		return errors.New("error: blob local path is required")
+	}
+
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(s.root, filepath.FromSlash(key))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Error: Delete blob file failed: %s", err.Error())
+		return errors.New("error: delete blob failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+)
+
+// Note that: every key is written once, so the objects are cached by the clients and the cdn for a year
+const cacheControl = "public, max-age=31536000, immutable"
+
+type s3BlobStore struct {
+	cfg     config.BlobS3
+	baseUrl string
+	client  *http.Client
+}
+
+func newS3BlobStore(cfg *config.Blob) *s3BlobStore {
+	return &s3BlobStore{
+		cfg:     cfg.S3,
+		baseUrl: strings.TrimSuffix(cfg.BaseUrl, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *s3BlobStore) Put(pctx context.Context, key, contentType string, body io.Reader) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		log.Printf("Error: Read blob failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+
+	req, err := s.newRequest(pctx, http.MethodPut, key, content)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", cacheControl)
+
+	if err := s.do(req); err != nil {
+		log.Printf("Error: Put s3 object failed: %s", err.Error())
+		return "", errors.New("error: put blob failed")
+	}
+
+	return s.baseUrl + "/" + key, nil
+}
+
+func (s *s3BlobStore) Delete(pctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(pctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.do(req); err != nil {
+		log.Printf("Error: Delete s3 object failed: %s", err.Error())
+		return errors.New("error: delete blob failed")
+	}
+
+	return nil
+}
+
+func (s *s3BlobStore) do(req *http.Request) error {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// Note that: deleting a key that does not exist is a success in s3
+	if res.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("status %d: %s", res.StatusCode, string(message))
+	}
+	return nil
+}
+
+// Sign the request with the aws signature version 4
+func (s *s3BlobStore) newRequest(pctx context.Context, method, key string, content []byte) (*http.Request, error) {
+	if s.cfg.Endpoint == "" || s.cfg.Bucket == "" || s.cfg.AccessKey == "" || s.cfg.SecretKey == "" {
+		return nil, errors.New("error: blob s3 endpoint, bucket and keys are required")
+	}
+
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, errors.New("error: blob s3 endpoint is invalid")
+	}
+
+	host := endpoint.Host
+	path := "/" + encodeKey(key)
+	if s.cfg.PathStyle {
+		path = "/" + s.cfg.Bucket + path
+	} else {
+		host = s.cfg.Bucket + "." + host
+	}
+
+	req, err := http.NewRequestWithContext(pctx, method, fmt.Sprintf("%s://%s%s", endpoint.Scheme, host, path), bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.New("error: blob s3 request is invalid")
+	}
+
+	region := s.cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	payloadHash := sha256.Sum256(content)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	SignV4(req, s.cfg.AccessKey, s.cfg.SecretKey, region, time.Now())
+
+	return req, nil
+}
+
+// SignV4 signs the request of s3 with the aws signature version 4, the host and every header that is set
+// on the request are signed, the hash of the payload is the X-Amz-Content-Sha256 header
+func SignV4(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := now.UTC().Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "host" || name == "authorization" {
+			continue
+		}
+		headers[name] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+secretKey), date)
+	signingKey = hmacSha256(signingKey, region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// The query is sorted by the names, the names and the values are encoded as the uri encoding of aws
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]string, 0, len(query))
+	for _, name := range names {
+		values := append([]string{}, query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// Encode every segment of the key as the uri encoding of aws, the slashes are kept
+func encodeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func uriEncode(value string) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/database"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func authDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
@@ -33,6 +35,72 @@ func AuthMigrate(pctx context.Context, cfg *config.Config) {
 		log.Printf("Index: %s", index)
 	}
 
+	// login_attempts
+	col = db.Collection("login_attempts")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"key", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	for _, index := range indexs {
+		log.Printf("Index: %s", index)
+	}
+
+	// login_audits
+	col = db.Collection("login_audits")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"email", 1}, {"created_at", -1}}},
+		{Keys: bson.D{{"player_id", 1}, {"created_at", -1}}},
+		{Keys: bson.D{{"ip", 1}, {"created_at", -1}}},
+	})
+	for _, index := range indexs {
+		log.Printf("Index: %s", index)
+	}
+
+	// two_factors
+	col = db.Collection("two_factors")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	for _, index := range indexs {
+		log.Printf("Index: %s", index)
+	}
+
+	// login_challenges
+	col = db.Collection("login_challenges")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"token_hash", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"expires_at", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	for _, index := range indexs {
+		log.Printf("Index: %s", index)
+	}
+
+	// oidc_states
+	col = db.Collection("oidc_states")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"state_hash", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"expires_at", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	for _, index := range indexs {
+		log.Printf("Index: %s", index)
+	}
+
+	// player_bans
+	col = db.Collection("player_bans")
+
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}}, Options: options.Index().SetUnique(true)},
+		// Note that: a suspend is removed by mongo once it expires
+		{Keys: bson.D{{"expires_at", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	for _, index := range indexs {
+		log.Printf("Index: %s", index)
+	}
+
 	// roles
 	col = db.Collection("roles")
 
@@ -45,28 +113,41 @@ func AuthMigrate(pctx context.Context, cfg *config.Config) {
 	}
 
 	// roles data
-	documents := func() []any {
-		roles := []*auth.Role{
-			{
-				Title: "player",
-				Code:  0,
-			},
-			{
-				Title: "admin",
-				Code:  1,
+	// Note that: the roles are upserted by their code so the migration can run again on an existing deployment,
+	// the admin role gets every permission that was added since it was inserted
+	roles := []*auth.Role{
+		{
+			Title:       "player",
+			Code:        0,
+			Permissions: []string{},
+		},
+		{
+			Title:       "admin",
+			Code:        1,
+			Permissions: rbac.Permissions(),
+		},
+	}
+	for _, r := range roles {
+		result, err := col.UpdateOne(
+			pctx,
+			bson.M{"code": r.Code},
+			bson.M{
+				"$setOnInsert": bson.M{"title": r.Title},
+				"$addToSet":    bson.M{"permissions": bson.M{"$each": r.Permissions}},
 			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			panic(err)
 		}
+		log.Println("Migrate role completed: ", r.Title, result.UpsertedCount, result.ModifiedCount)
+	}
 
-		docs := make([]any, 0)
-		for _, r := range roles {
-			docs = append(docs, r)
-		}
-		return docs
-	}()
-
-	results, err := col.InsertMany(pctx, documents, nil)
+	// The offset is only set when there is none, a re-run must not replay the queue
+	col = db.Collection("auth_queue")
+	result, err := col.UpdateOne(pctx, bson.M{}, bson.M{"$setOnInsert": bson.M{"offset": -1}}, options.Update().SetUpsert(true))
 	if err != nil {
 		panic(err)
 	}
-	log.Println("Migrate auth completed: ", results)
+	log.Println("Migrate auth_queue completed: ", result.UpsertedCount)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/database"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func inventoryDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
@@ -22,6 +23,8 @@ func InventoryMigrate(pctx context.Context, cfg *config.Config) {
 
 	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{"player_id", 1}, {"item_id", 1}}},
+		{Keys: bson.D{{"order_id", 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{"removing_by", 1}}, Options: options.Index().SetSparse(true)},
 	})
 	for _, index := range indexs {
 		log.Printf("Index: %s", index)
@@ -1,17 +1,23 @@
 package migration
 
 import (
+	"bytes"
 	"context"
+	_ "embed"
 	"log"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
-	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/database"
-	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+//go:embed data/items.json
+var itemSeed []byte
+
 func itemDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
 	return database.DbConn(pctx, cfg).Database("item_db")
 }
@@ -23,53 +29,85 @@ func ItemMigrate(pctx context.Context, cfg *config.Config) {
 	col := db.Collection("items")
 	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{"_id", 1}}},
-		{Keys: bson.D{{"title", 1}}},
+		{Keys: bson.D{{"category", 1}, {"rarity", 1}}},
+		{Keys: bson.D{{"title", "text"}}, Options: options.Index().SetName("items_text")},
+		{Keys: bson.D{{"price", 1}}},
+		{Keys: bson.D{{"effective_price", 1}}},
+		{Keys: bson.D{{"created_at", -1}}},
 	})
 	for _, index := range indexs {
 		log.Printf("Index: %s", index)
 	}
 
-	documents := func() []any {
-		roles := []*item.Item{
-			{
-				Title:       "Diamond Sword",
-				Price:       1000,
-				ImageUrl:    "https://i.imgur.com/1Y8tQZM.png",
-				UsageStatus: true,
-				Damage:      100,
-				CreatedAt:   utils.LocalTime(),
-				UpdatedAt:   utils.LocalTime(),
-			},
-			{
-				Title:       "Iron Sword",
-				Price:       500,
-				ImageUrl:    "https://i.imgur.com/1Y8tQZM.png",
-				UsageStatus: true,
-				Damage:      50,
-				CreatedAt:   utils.LocalTime(),
-				UpdatedAt:   utils.LocalTime(),
-			},
-			{
-				Title:       "Wooden Sword",
-				Price:       100,
-				ImageUrl:    "https://i.imgur.com/1Y8tQZM.png",
-				UsageStatus: true,
-				Damage:      20,
-				CreatedAt:   utils.LocalTime(),
-				UpdatedAt:   utils.LocalTime(),
-			},
+	// Note that: the unique title makes an import that runs twice at the same time create an item once,
+	// the old index of the title that is not unique is dropped first because the same keys can not have two indexes
+	specs, _ := col.Indexes().ListSpecifications(pctx)
+	for _, spec := range specs {
+		if spec.Name == "title_1" && (spec.Unique == nil || !*spec.Unique) {
+			col.Indexes().DropOne(pctx, spec.Name)
 		}
+	}
+	titles, err := col.Indexes().CreateOne(pctx, mongo.IndexModel{Keys: bson.D{{"title", 1}}, Options: options.Index().SetUnique(true)})
+	if err != nil {
+		log.Printf("Error: the unique index of the title is not created, the titles that are duplicated must be renamed first: %s", err.Error())
+	} else {
+		log.Printf("Index: %s", titles)
+	}
 
-		docs := make([]any, 0)
-		for _, r := range roles {
-			docs = append(docs, r)
-		}
-		return docs
-	}()
+	revisions, _ := db.Collection("item_revisions").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"item_id", 1}, {"revision", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	for _, index := range revisions {
+		log.Printf("Index: %s", index)
+	}
 
-	results, err := col.InsertMany(pctx, documents, nil)
+	pricings, _ := db.Collection("item_campaigns").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"start_at", 1}, {"end_at", 1}}},
+		{Keys: bson.D{{"end_at", 1}}},
+	})
+	schedules, _ := db.Collection("item_price_schedules").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"item_id", 1}, {"start_at", -1}}},
+		{Keys: bson.D{{"start_at", 1}}},
+	})
+	for _, index := range append(pricings, schedules...) {
+		log.Printf("Index: %s", index)
+	}
+
+	watches, _ := db.Collection("item_price_watches").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"item_id", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	alerts, _ := db.Collection("item_alerts").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"published_at", 1}, {"_id", 1}}},
+	})
+	for _, index := range append(watches, alerts...) {
+		log.Printf("Index: %s", index)
+	}
+
+	// The items before the effective price start at their price, RefreshEffectivePrices applies the campaigns below
+	backfill, err := col.UpdateMany(pctx, bson.M{"effective_price": bson.M{"$exists": false}}, bson.A{
+		bson.M{"$set": bson.M{"effective_price": "$price"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("Migrate item effective_price completed: %d", backfill.ModifiedCount)
+
+	// Note that: the seed goes through the same importer as POST /item_v1/item/import, so running it again updates the items
+	usecase := itemUsecase.NewItemUsecase(itemRepository.NewItemRepository(db.Client()), nil)
+
+	results, err := usecase.ImportItems(pctx, "migration", itemUsecase.ImportFormatJson, bytes.NewReader(itemSeed), false)
 	if err != nil {
 		panic(err)
 	}
-	log.Println("Migrate item completed: ", results)
+	if results.Failed > 0 {
+		for _, e := range results.Errors {
+			log.Printf("Error: Seed item row %d %s: %s", e.Row, e.Title, e.Error)
+		}
+		panic("seed items failed")
+	}
+	log.Printf("Migrate item completed: %d created, %d updated, %d unchanged", results.Created, results.Updated, results.Unchanged)
+
+	if err := usecase.RefreshEffectivePrices(pctx); err != nil {
+		panic(err)
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/database"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func paymentDbConn(pctx context.Context, cfg *config.Config) *mongo.Database {
@@ -18,6 +19,45 @@ func PaymentMigrate(pctx context.Context, cfg *config.Config) {
 	db := paymentDbConn(pctx, cfg)
 	defer db.Client().Disconnect(pctx)
 
+	indexs, _ := db.Collection("coupons").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"code", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	uses, _ := db.Collection("coupon_player_uses").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"coupon_id", 1}, {"player_id", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	redemptions, _ := db.Collection("coupon_redemptions").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"coupon_id", 1}, {"player_id", 1}}},
+	})
+	for _, index := range append(append(indexs, uses...), redemptions...) {
+		log.Printf("Index: %s", index)
+	}
+
+	carts, _ := db.Collection("carts").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	for _, index := range carts {
+		log.Printf("Index: %s", index)
+	}
+
+	orders, _ := db.Collection("orders").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}, {"created_at", -1}}},
+		{Keys: bson.D{{"status", 1}, {"updated_at", 1}}},
+		{Keys: bson.D{{"items.inventory_id", 1}}, Options: options.Index().SetSparse(true)},
+	})
+	for _, index := range orders {
+		log.Printf("Index: %s", index)
+	}
+
+	rules, _ := db.Collection("risk_rules").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}}, Options: options.Index().SetUnique(true)},
+	})
+	decisions, _ := db.Collection("risk_decisions").Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}, {"_id", -1}}},
+	})
+	for _, index := range append(rules, decisions...) {
+		log.Printf("Index: %s", index)
+	}
+
 	col := db.Collection("payment_queue")
 
 	results, err := col.InsertOne(pctx, bson.M{"offset": -1}, nil)
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -28,6 +29,46 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 	indexs, _ := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{"_id", 1}}},
 		{Keys: bson.D{{"player_id", 1}}},
+		{Keys: bson.D{{"order_id", 1}}, Options: options.Index().SetSparse(true)},
+	})
+	log.Println(indexs)
+
+	col = db.Collection("password_resets")
+
+	// indexs
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"token_hash", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"player_id", 1}}},
+		// Note that: expired tokens are removed by mongo
+		{Keys: bson.D{{"expires_at", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	log.Println(indexs)
+
+	col = db.Collection("email_verifications")
+
+	// indexs
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"token_hash", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"player_id", 1}}},
+		{Keys: bson.D{{"expires_at", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	log.Println(indexs)
+
+	col = db.Collection("player_wishlists")
+
+	// indexs
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}, {"item_id", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"item_id", 1}}},
+	})
+	log.Println(indexs)
+
+	col = db.Collection("player_notifications")
+
+	// indexs
+	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{Keys: bson.D{{"player_id", 1}, {"alert_id", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"player_id", 1}, {"is_read", 1}, {"_id", -1}}},
 	})
 	log.Println(indexs)
 
@@ -37,9 +78,43 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 	indexs, _ = col.Indexes().CreateMany(pctx, []mongo.IndexModel{
 		{Keys: bson.D{{"_id", 1}}},
 		{Keys: bson.D{{"email", 1}}},
+		{Keys: bson.D{{"username", 1}}},
 	})
 	log.Println(indexs)
 
+	// Note that: an identity belongs to one player, the index was not unique before so it is dropped and created again
+	col.Indexes().DropOne(pctx, "identities.provider_1_identities.subject_1")
+	indexs, err := col.Indexes().CreateMany(pctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{"identities.provider", 1}, {"identities.subject", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"identities.subject": bson.M{"$exists": true},
+			}),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	log.Println(indexs)
+
+	// Note that: the players that were registered before the email verification are verified, so they are not blocked from buying,
+	// the migration can run again on an existing deployment for this backfill
+	verified, err := col.UpdateMany(pctx, bson.M{"is_verified": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"is_verified": true}})
+	if err != nil {
+		panic(err)
+	}
+	log.Println("Migrate players is_verified completed: ", verified.ModifiedCount)
+
+	// The seeds are only inserted into an empty database
+	count, err := col.CountDocuments(pctx, bson.M{})
+	if err != nil {
+		panic(err)
+	}
+	if count > 0 {
+		log.Println("Migrate players seeds skipped: ", count)
+		return
+	}
+
 	documents := func() []any {
 		roles := []*player.Player{
 			{
@@ -56,8 +131,9 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 						RoleCode:  0,
 					},
 				},
-				CreatedAt: utils.LocalTime(),
-				UpdatedAt: utils.LocalTime(),
+				IsVerified: true,
+				CreatedAt:  utils.LocalTime(),
+				UpdatedAt:  utils.LocalTime(),
 			},
 			{
 				Email: "player002@sekai.com",
@@ -73,8 +149,9 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 						RoleCode:  0,
 					},
 				},
-				CreatedAt: utils.LocalTime(),
-				UpdatedAt: utils.LocalTime(),
+				IsVerified: true,
+				CreatedAt:  utils.LocalTime(),
+				UpdatedAt:  utils.LocalTime(),
 			},
 			{
 				Email: "player003@sekai.com",
@@ -90,8 +167,9 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 						RoleCode:  0,
 					},
 				},
-				CreatedAt: utils.LocalTime(),
-				UpdatedAt: utils.LocalTime(),
+				IsVerified: true,
+				CreatedAt:  utils.LocalTime(),
+				UpdatedAt:  utils.LocalTime(),
 			},
 			{
 				Email: "admin001@sekai.com",
@@ -111,8 +189,9 @@ func PlayerMigrate(pctx context.Context, cfg *config.Config) {
 						RoleCode:  1,
 					},
 				},
-				CreatedAt: utils.LocalTime(),
-				UpdatedAt: utils.LocalTime(),
+				IsVerified: true,
+				CreatedAt:  utils.LocalTime(),
+				UpdatedAt:  utils.LocalTime(),
 			},
 		}
 
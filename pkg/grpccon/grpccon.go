@@ -7,12 +7,14 @@ import (
 	"net"
 
 	authPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authPb"
+	inventoryPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryPb"
 	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
 	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
 
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
@@ -22,6 +24,7 @@ type (
 		Auth() authPb.AuthGrpcServiceClient
 		Player() playerPb.PlayerGrpcServiceClient
 		Item() itemPb.ItemGrpcServiceClient
+		Inventory() inventoryPb.InventoryGrpcServiceClient
 	}
 
 	grpcClientFactory struct {
@@ -73,10 +76,18 @@ func (g *grpcClientFactory) Item() itemPb.ItemGrpcServiceClient {
 	return itemPb.NewItemGrpcServiceClient(g.client)
 }
 
+func (g *grpcClientFactory) Inventory() inventoryPb.InventoryGrpcServiceClient {
+	return inventoryPb.NewInventoryGrpcServiceClient(g.client)
+}
+
 func NewGrpcClient(host string) (GrpcClientFactoryHandler, error) {
 	opts := make([]grpc.DialOption, 0)
 
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if tlsConfig := ClientTlsConfig(); tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
 
 	clientConn, err := grpc.Dial(host, opts...)
 	if err != nil {
@@ -98,6 +109,10 @@ func NewGrpcServer(cfg *config.Jwt, host string) (*grpc.Server, net.Listener) {
 
 	opts = append(opts, grpc.UnaryInterceptor(grpcAuth.unaryAuthorization))
 
+	if tlsConfig := ServerTlsConfig(); tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
 	grpcServer := grpc.NewServer(opts...)
 
 	lis, err := net.Listen("tcp", host)
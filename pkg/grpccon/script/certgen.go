@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Dev only: generate a local CA and one certificate per service for gRPC mTLS
+// Usage: go run ./pkg/grpccon/script/certgen.go ./certs
+func main() {
+	outDir := func() string {
+		if len(os.Args) < 2 {
+			log.Fatal("Error: output directory is required")
+		}
+		return os.Args[1]
+	}()
+
+	if err := os.MkdirAll(outDir, 0700); err != nil {
+		log.Fatalf("Error: Create output directory failed: %s", err.Error())
+	}
+
+	// CA
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Fatalf("Error: Generate ca key failed: %s", err.Error())
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{CommonName: "hello-sekai-shop-dev-ca", Organization: []string{"Hello Sekai Shop"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		log.Fatalf("Error: Create ca certificate failed: %s", err.Error())
+	}
+	caCert, _ := x509.ParseCertificate(caDer)
+
+	writePem(filepath.Join(outDir, "ca.crt"), "CERTIFICATE", caDer)
+	writeKey(filepath.Join(outDir, "ca.key"), caKey)
+
+	// Services
+	services := []string{"auth", "player", "item", "inventory", "payment"}
+	for _, service := range services {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			log.Fatalf("Error: Generate %s key failed: %s", service, err.Error())
+		}
+
+		template := &x509.Certificate{
+			SerialNumber: serialNumber(),
+			Subject:      pkix.Name{CommonName: service, Organization: []string{"Hello Sekai Shop"}},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().AddDate(1, 0, 0),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			// Every service is both a gRPC server and a client of the others
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			DNSNames:    []string{service, "localhost"},
+			IPAddresses: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("0.0.0.0"), net.IPv6loopback},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			log.Fatalf("Error: Create %s certificate failed: %s", service, err.Error())
+		}
+
+		writePem(filepath.Join(outDir, service+".crt"), "CERTIFICATE", der)
+		writeKey(filepath.Join(outDir, service+".key"), key)
+
+		log.Printf("Generated certificate for: %s", service)
+	}
+
+	log.Printf("Certificates are stored in: %s", outDir)
+}
+
+func serialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		log.Fatalf("Error: Generate serial number failed: %s", err.Error())
+	}
+	return serial
+}
+
+func writeKey(path string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		log.Fatalf("Error: Marshal private key failed: %s", err.Error())
+	}
+	writePem(path, "EC PRIVATE KEY", der)
+}
+
+func writePem(path, blockType string, der []byte) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		log.Fatalf("Error: Open %s failed: %s", path, err.Error())
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		log.Fatalf("Error: Write %s failed: %s", path, err.Error())
+	}
+}
@@ -0,0 +1,178 @@
+package grpccon
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+)
+
+type (
+	certReloader struct {
+		caPath   string
+		certPath string
+		keyPath  string
+
+		mu      sync.RWMutex
+		cert    *tls.Certificate
+		caPool  *x509.CertPool
+		modTime time.Time
+	}
+)
+
+// Tls instance, nil means the gRPC connections are in plaintext
+var tlsReloader *certReloader
+var tlsOnce sync.Once
+
+// Note that: mTLS is enabled only when all of the certificate paths are set
+func SetTlsConfig(cfg *config.Grpc) {
+	tlsOnce.Do(func() {
+		if cfg.TlsCaPath == "" || cfg.TlsCertPath == "" || cfg.TlsKeyPath == "" {
+			log.Println("Warning: gRPC TLS is disabled, connections are in plaintext")
+			return
+		}
+
+		reloader := &certReloader{
+			caPath:   cfg.TlsCaPath,
+			certPath: cfg.TlsCertPath,
+			keyPath:  cfg.TlsKeyPath,
+		}
+		if err := reloader.reload(); err != nil {
+			log.Fatalf("Error: Load gRPC certificates failed: %s", err.Error())
+		}
+
+		tlsReloader = reloader
+	})
+}
+
+func (r *certReloader) latestModTime() time.Time {
+	latest := time.Time{}
+	for _, path := range []string{r.caPath, r.certPath, r.keyPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+func (r *certReloader) reload() error {
+	modTime := r.latestModTime()
+
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		log.Printf("Error: Load key pair failed: %s", err.Error())
+		return errors.New("error: load key pair failed")
+	}
+
+	caPem, err := os.ReadFile(r.caPath)
+	if err != nil {
+		log.Printf("Error: Read ca certificate failed: %s", err.Error())
+		return errors.New("error: read ca certificate failed")
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPem) {
+		return errors.New("error: ca certificate is invalid")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.caPool = caPool
+	r.modTime = modTime
+
+	return nil
+}
+
+// Note that: the files are checked on every handshake, a rotated certificate is picked up without restarting
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	stale := r.latestModTime().After(r.modTime)
+	r.mu.RUnlock()
+
+	if stale {
+		if err := r.reload(); err != nil {
+			log.Printf("Error: Reload gRPC certificates failed, keep using the old one: %s", err.Error())
+		} else {
+			log.Println("gRPC certificates reloaded")
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, r.caPool
+}
+
+func (r *certReloader) serverTlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, caPool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    caPool,
+			}, nil
+		},
+	}
+}
+
+// Note that: the client keeps its config for the life of the connection, so the server is verified by VerifyConnection
+// against the ca of the handshake instead of RootCAs that is fixed at the dial, a rotated ca is picked up without restarting
+func (r *certReloader) clientTlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("error: server certificate is required")
+			}
+
+			_, caPool := r.current()
+			opts := x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         caPool,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+	}
+}
+
+// ServerTlsConfig is the tls config of the gRPC server, nil when TLS is disabled
+func ServerTlsConfig() *tls.Config {
+	if tlsReloader == nil {
+		return nil
+	}
+	return tlsReloader.serverTlsConfig()
+}
+
+// ClientTlsConfig is the tls config of a gRPC client, nil when TLS is disabled
+func ClientTlsConfig() *tls.Config {
+	if tlsReloader == nil {
+		return nil
+	}
+	return tlsReloader.clientTlsConfig()
+}
@@ -19,6 +19,8 @@ type (
 	Claims struct {
 		PlayerId string `json:"player_id"`
 		RoleCode int    `json:"role_code"`
+		// Note that: true when the player has passed the two-factor authentication
+		Mfa bool `json:"mfa"`
 	}
 
 	AuthMapClaims struct {
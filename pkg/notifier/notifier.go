@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+)
+
+type (
+	Message struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	NotifierService interface {
+		Send(pctx context.Context, msg *Message) error
+	}
+
+	logNotifier struct{}
+
+	fileNotifier struct {
+		path string
+	}
+
+	smtpNotifier struct {
+		cfg *config.Notifier
+	}
+)
+
+// Note that: the file is shared by every notifier instance in the process
+var fileMu sync.Mutex
+
+func NewNotifier(cfg *config.Notifier) NotifierService {
+	switch cfg.Type {
+	case "file":
+		return &fileNotifier{path: cfg.FilePath}
+	case "smtp":
+		return &smtpNotifier{cfg: cfg}
+	default:
+		return &logNotifier{}
+	}
+}
+
+// Dev only: print the message to the console
+func (n *logNotifier) Send(pctx context.Context, msg *Message) error {
+	log.Printf("Notification to: %s | Subject: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// Dev only: append the message to a file
+func (n *fileNotifier) Send(pctx context.Context, msg *Message) error {
+	if n.path == "" {
+		return errors.New("error: notifier file path is required")
+	}
+
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		log.Printf("Error: Open notifier file failed: %s", err.Error())
+		return errors.New("error: send notification failed")
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body); err != nil {
+		log.Printf("Error: Write notifier file failed: %s", err.Error())
+		return errors.New("error: send notification failed")
+	}
+
+	return nil
+}
+
+func (n *smtpNotifier) Send(pctx context.Context, msg *Message) error {
+	if n.cfg.SmtpHost == "" || n.cfg.SmtpFrom == "" {
+		return errors.New("error: smtp host and sender are required")
+	}
+
+	// Prevent header injection
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return errors.New("error: recipient or subject is invalid")
+	}
+
+	var auth smtp.Auth
+	if n.cfg.SmtpUsername != "" {
+		auth = smtp.PlainAuth("", n.cfg.SmtpUsername, n.cfg.SmtpPassword, n.cfg.SmtpHost)
+	}
+
+	body := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s\r\n",
+		n.cfg.SmtpFrom,
+		msg.To,
+		msg.Subject,
+		time.Now().Format(time.RFC1123Z),
+		strings.ReplaceAll(msg.Body, "\n", "\r\n"),
+	)
+
+	if err := smtp.SendMail(net.JoinHostPort(n.cfg.SmtpHost, n.cfg.SmtpPort), auth, n.cfg.SmtpFrom, []string{msg.To}, []byte(body)); err != nil {
+		log.Printf("Error: Send mail failed: %s", err.Error())
+		return errors.New("error: send notification failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,399 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	Endpoints struct {
+		Issuer      string `json:"issuer"`
+		AuthUrl     string `json:"authorization_endpoint"`
+		TokenUrl    string `json:"token_endpoint"`
+		UserInfoUrl string `json:"userinfo_endpoint"`
+		JwksUrl     string `json:"jwks_uri"`
+	}
+
+	TokenRes struct {
+		AccessToken string `json:"access_token"`
+		IdToken     string `json:"id_token"`
+		TokenType   string `json:"token_type"`
+	}
+
+	Identity struct {
+		Subject       string
+		Email         string
+		EmailVerified bool
+		Name          string
+	}
+
+	jsonWebKeySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	jsonWebKey struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Cache of the discovery documents and the signing keys, keyed by url
+var discoveryCache sync.Map
+var keysCache sync.Map
+
+// Note that: a token with an unknown kid refreshes the keys of the url at most once a minute,
+// so tokens with made up kids can not make the provider be called on every login
+const keysRefreshInterval = time.Minute
+
+var (
+	keysMu          sync.Mutex
+	keysRefreshedAt = make(map[string]time.Time)
+)
+
+func Discover(pctx context.Context, provider *config.OidcProvider) (*Endpoints, error) {
+	endpoints := new(Endpoints)
+
+	if provider.Issuer != "" {
+		if cached, ok := discoveryCache.Load(provider.Issuer); ok {
+			*endpoints = *cached.(*Endpoints)
+		} else {
+			if err := getJson(pctx, strings.TrimSuffix(provider.Issuer, "/")+"/.well-known/openid-configuration", "", endpoints); err != nil {
+				return nil, err
+			}
+			if endpoints.Issuer != provider.Issuer {
+				log.Printf("Error: Discovered issuer %s does not match %s", endpoints.Issuer, provider.Issuer)
+				return nil, errors.New("error: oidc issuer mismatch")
+			}
+			discoveryCache.Store(provider.Issuer, &Endpoints{
+				Issuer:      endpoints.Issuer,
+				AuthUrl:     endpoints.AuthUrl,
+				TokenUrl:    endpoints.TokenUrl,
+				UserInfoUrl: endpoints.UserInfoUrl,
+				JwksUrl:     endpoints.JwksUrl,
+			})
+		}
+	}
+
+	// Explicit endpoints win over the discovered ones
+	if provider.AuthUrl != "" {
+		endpoints.AuthUrl = provider.AuthUrl
+	}
+	if provider.TokenUrl != "" {
+		endpoints.TokenUrl = provider.TokenUrl
+	}
+	if provider.UserInfoUrl != "" {
+		endpoints.UserInfoUrl = provider.UserInfoUrl
+	}
+
+	if endpoints.AuthUrl == "" || endpoints.TokenUrl == "" {
+		return nil, errors.New("error: oidc provider endpoints are not configured")
+	}
+
+	return endpoints, nil
+}
+
+func AuthCodeUrl(endpoints *Endpoints, provider *config.OidcProvider, state, nonce, codeChallenge string) string {
+	scopes := provider.Scopes
+	if scopes == "" {
+		scopes = "openid email profile"
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.ClientId)
+	query.Set("redirect_uri", provider.RedirectUrl)
+	query.Set("scope", scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(endpoints.AuthUrl, "?") {
+		separator = "&"
+	}
+	return endpoints.AuthUrl + separator + query.Encode()
+}
+
+func Exchange(pctx context.Context, endpoints *Endpoints, provider *config.OidcProvider, code, codeVerifier string) (*TokenRes, error) {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.RedirectUrl)
+	form.Set("client_id", provider.ClientId)
+	form.Set("client_secret", provider.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.TokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.New("error: oidc token request failed")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	result := new(TokenRes)
+	if err := doJson(req, result); err != nil {
+		return nil, err
+	}
+
+	if result.AccessToken == "" && result.IdToken == "" {
+		return nil, errors.New("error: oidc token response is empty")
+	}
+
+	return result, nil
+}
+
+// Note that: the signature, issuer, audience, expiry and nonce of the id token are all verified
+func VerifyIdToken(pctx context.Context, endpoints *Endpoints, provider *config.OidcProvider, rawIdToken, nonce string) (*Identity, error) {
+	if endpoints.JwksUrl == "" {
+		return nil, errors.New("error: oidc jwks is not configured")
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(
+		rawIdToken,
+		claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return findKey(pctx, endpoints.JwksUrl, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithIssuer(endpoints.Issuer),
+		jwt.WithAudience(provider.ClientId),
+		jwt.WithLeeway(30*time.Second),
+	); err != nil {
+		log.Printf("Error: Verify id token failed: %s", err.Error())
+		return nil, errors.New("error: id token is invalid")
+	}
+
+	if exp, err := claims.GetExpirationTime(); err != nil || exp == nil {
+		return nil, errors.New("error: id token has no expiry")
+	}
+
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, errors.New("error: id token nonce mismatch")
+	}
+
+	return identityFromClaims(claims), nil
+}
+
+// Note that: this is used for a provider without id token, the field names of the common providers are mapped
+func UserInfo(pctx context.Context, endpoints *Endpoints, accessToken string) (*Identity, error) {
+	if endpoints.UserInfoUrl == "" {
+		return nil, errors.New("error: oidc userinfo is not configured")
+	}
+
+	claims := make(map[string]any)
+	if err := getJson(pctx, endpoints.UserInfoUrl, accessToken, &claims); err != nil {
+		return nil, err
+	}
+
+	return identityFromClaims(claims), nil
+}
+
+func identityFromClaims(claims map[string]any) *Identity {
+	pick := func(keys ...string) string {
+		for _, key := range keys {
+			switch value := claims[key].(type) {
+			case string:
+				if value != "" {
+					return value
+				}
+			case float64:
+				return fmt.Sprintf("%.0f", value)
+			}
+		}
+		return ""
+	}
+
+	// Note that: only email_verified is trusted, a provider that does not send it is not verified,
+	// so its accounts are never linked by the email
+	emailVerified := false
+	switch value := claims["email_verified"].(type) {
+	case bool:
+		emailVerified = value
+	case string:
+		emailVerified = value == "true"
+	}
+
+	return &Identity{
+		Subject:       pick("sub", "id"),
+		Email:         strings.ToLower(pick("email")),
+		EmailVerified: emailVerified,
+		Name:          pick("preferred_username", "username", "name"),
+	}
+}
+
+// PKCE
+func NewCodeVerifier() (string, error) {
+	return RandomString(32)
+}
+
+func CodeChallenge(codeVerifier string) string {
+	hashed := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hashed[:])
+}
+
+func RandomString(size int) (string, error) {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("error: generate random string failed")
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func findKey(pctx context.Context, jwksUrl, kid string) (any, error) {
+	if cached, ok := keysCache.Load(jwksUrl); ok {
+		if key, ok := cached.(map[string]any)[kid]; ok {
+			return key, nil
+		}
+	}
+
+	// Unknown kid, the keys may have been rotated
+	if !allowKeysRefresh(jwksUrl) {
+		log.Printf("Error: Refresh of jwks %s is skipped, it was refreshed less than %s ago", jwksUrl, keysRefreshInterval)
+		return nil, errors.New("error: signing key not found")
+	}
+
+	set := new(jsonWebKeySet)
+	if err := getJson(pctx, jwksUrl, "", set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any)
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("Error: Parse jwk %s failed: %s", k.Kid, err.Error())
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	keysCache.Store(jwksUrl, keys)
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, errors.New("error: signing key not found")
+	}
+	return key, nil
+}
+
+func allowKeysRefresh(jwksUrl string) bool {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+
+	if refreshedAt, ok := keysRefreshedAt[jwksUrl]; ok && time.Since(refreshedAt) < keysRefreshInterval {
+		return false
+	}
+	keysRefreshedAt[jwksUrl] = time.Now()
+	return true
+}
+
+func (k *jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		curve := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384()}[k.Crv]
+		if curve == nil {
+			return nil, errors.New("error: curve is not supported")
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, errors.New("error: key type is not supported")
+	}
+}
+
+func getJson(pctx context.Context, rawUrl, bearer string, result any) error {
+	ctx, cancel := context.WithTimeout(pctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return errors.New("error: oidc request failed")
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	return doJson(req, result)
+}
+
+func doJson(req *http.Request, result any) error {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		log.Printf("Error: oidc request to %s failed: %s", req.URL.Host, err.Error())
+		return errors.New("error: oidc request failed")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return errors.New("error: oidc request failed")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		log.Printf("Error: oidc request to %s failed: %d %s", req.URL.Host, res.StatusCode, string(body))
+		return fmt.Errorf("error: oidc request failed with status %d", res.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		log.Printf("Error: Decode oidc response failed: %s", err.Error())
+		return errors.New("error: oidc response is invalid")
+	}
+
+	return nil
+}
@@ -1,10 +1,58 @@
 package rbac
 
-func IntToBinary(n, length int) []int {
-	binary := make([]int, length)
-	for i := 0; i < length; i++ {
-		binary[i] = n % 2
-		n /= 2
+import "strings"
+
+// Permissions
+const (
+	ItemCreate   = "item:create"
+	ItemEdit     = "item:edit"
+	ItemImport   = "item:import"
+	ItemPricing  = "item:pricing"
+	CouponManage = "coupon:manage"
+	OrderRefund  = "order:refund"
+	RiskManage   = "risk:manage"
+	RoleManage   = "role:manage"
+	AuthUnlock   = "auth:unlock"
+	AuthAudit    = "auth:audit"
+
+	PlayerSearch   = "player:search"
+	PlayerModerate = "player:moderate"
+)
+
+func Permissions() []string {
+	return []string{
+		ItemCreate,
+		ItemEdit,
+		ItemImport,
+		ItemPricing,
+		CouponManage,
+		OrderRefund,
+		RiskManage,
+		RoleManage,
+		AuthUnlock,
+		AuthAudit,
+		PlayerSearch,
+		PlayerModerate,
+	}
+}
+
+func IsValidPermission(permission string) bool {
+	for _, p := range Permissions() {
+		if p == permission {
+			return true
+		}
 	}
-	return binary
+	return false
+}
+
+// Note that: code 0 is the base role that every player has, other codes are bits of the role code
+func HasRole(playerRoleCode, roleCode int) bool {
+	if roleCode == 0 {
+		return true
+	}
+	return playerRoleCode&roleCode == roleCode
+}
+
+func NormalizePermission(permission string) string {
+	return strings.ToLower(strings.TrimSpace(permission))
 }
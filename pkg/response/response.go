@@ -1,6 +1,12 @@
 package response
 
-import "github.com/labstack/echo/v4"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
 
 type (
 	MsgResponse struct {
@@ -15,3 +21,25 @@ func ErrResponse(c echo.Context, statusCode int, message string) error {
 func SuccessResponse(c echo.Context, statusCode int, data any) error {
 	return c.JSON(statusCode, data)
 }
+
+// EventResponse writes one server-sent event and flushes it, the first event starts the stream
+func EventResponse(c echo.Context, event string, data any) error {
+	res := c.Response()
+	if !res.Committed {
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.WriteHeader(http.StatusOK)
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, body); err != nil {
+		return err
+	}
+	res.Flush()
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+)
+
+// Note that: the size is checked before the pixels are decoded, so a small file can not claim a huge image
+const MaxDimension = 4096
+
+// Decode reads a png, jpeg or gif image
+func Decode(content []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.New("error: image is invalid")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return nil, errors.New("error: image must not be larger than 4096x4096")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.New("error: image is invalid")
+	}
+	return img, nil
+}
+
+// Generate scales the image down to fit a size x size box and encodes it as png, a smaller image keeps its size
+func Generate(img image.Image, size int) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > size || height > size {
+		if width >= height {
+			height = max(1, height*size/width)
+			width = size
+		} else {
+			width = max(1, width*size/height)
+			height = size
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	// Every pixel of the thumbnail is the average of the box of the source that it covers
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					// Weight the colors by the alpha, so a transparent pixel does not darken the edges
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					b += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			pixel := color.NRGBA{A: uint8(a / n >> 8)}
+			if a > 0 {
+				pixel.R = uint8(r / a >> 8)
+				pixel.G = uint8(g / a >> 8)
+				pixel.B = uint8(b / a >> 8)
+			}
+			dst.SetNRGBA(x, y, pixel)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, dst); err != nil {
+		return nil, errors.New("error: encode thumbnail failed")
+	}
+	return buf.Bytes(), nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
 )
 
 func (s *server) authService() {
@@ -15,6 +16,9 @@ func (s *server) authService() {
 	usecase := authUsecase.NewAuthUsecase(repo)
 	httpHandler := authHandler.NewAuthHttpHandler(s.cfg, usecase)
 	grpcHandler := authHandler.NewAuthGrpcHandler(usecase)
+	queueHandler := authHandler.NewAuthQueueHandler(s.cfg, usecase)
+
+	go queueHandler.DeletePlayerCredentials()
 
 	// gRPC
 	go func() {
@@ -35,4 +39,31 @@ func (s *server) authService() {
 	auth.POST("/auth/login", httpHandler.Login)
 	auth.POST("/auth/refresh-token", httpHandler.RefreshToken)
 	auth.POST("/auth/logout", httpHandler.Logout)
+
+	// Social login
+	auth.GET("/auth/oidc/:provider/authorize", httpHandler.OidcAuthorize)
+	auth.GET("/auth/oidc/:provider/callback", httpHandler.OidcCallback)
+
+	// Two-factor authentication
+	auth.POST("/auth/login/2fa", httpHandler.LoginTwoFactor)
+	auth.POST("/auth/2fa/enroll", httpHandler.EnrollTwoFactor, s.middleware.JwtAuthorization)
+	auth.POST("/auth/2fa/confirm", httpHandler.ConfirmTwoFactor, s.middleware.JwtAuthorization)
+	auth.POST("/auth/2fa/disable", httpHandler.DisableTwoFactor, s.middleware.JwtAuthorization)
+	auth.POST("/auth/2fa/recovery-codes", httpHandler.RegenerateRecoveryCodes, s.middleware.JwtAuthorization)
+
+	// Login protection
+	auth.POST("/auth/login-attempts/unlock", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.UnlockLogin, rbac.AuthUnlock)))
+	auth.GET("/auth/login-audits", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindLoginAudits, rbac.AuthAudit)))
+
+	// Moderation
+	auth.POST("/auth/players/:player_id/ban", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.BanPlayer, rbac.PlayerModerate)))
+	auth.DELETE("/auth/players/:player_id/ban", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.UnbanPlayer, rbac.PlayerModerate)))
+
+	// Role management
+	auth.POST("/auth/roles", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.CreateRole, rbac.RoleManage)))
+	auth.GET("/auth/roles", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindAllRoles, rbac.RoleManage)))
+	auth.PUT("/auth/roles/:role_code/permissions", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.UpdateRolePermissions, rbac.RoleManage)))
+	auth.GET("/auth/players/:player_id/roles", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindPlayerRoles, rbac.RoleManage)))
+	auth.POST("/auth/players/:player_id/roles", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.GrantPlayerRoles, rbac.RoleManage)))
+	auth.DELETE("/auth/players/:player_id/roles/:role_code", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.RevokePlayerRole, rbac.RoleManage)))
 }
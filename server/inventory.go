@@ -1,21 +1,38 @@
 package server
 
 import (
+	"log"
+
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryHandler"
+	inventoryPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryPb"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
 )
 
 func (s *server) inventoryService() {
 	repo := inventoryRepository.NewInventoryRepository(s.db)
 	usecase := inventoryUsecase.NewInventoryUsecase(repo)
 	httpHandler := inventoryHandler.NewInventoryHttpHandler(s.cfg, usecase)
+	grpcHandler := inventoryHandler.NewInventoryGrpcHandler(s.cfg, usecase)
 	queueHandler := inventoryHandler.NewInventoryQueueHandler(s.cfg, usecase)
 
 	go queueHandler.AddPlayerItem()
 	go queueHandler.RollbackAddPlayerItem()
 	go queueHandler.RemovePlayerItem()
+	go queueHandler.RefundPlayerItem()
 	go queueHandler.RollbackRemovePlayerItem()
+	go queueHandler.DeletePlayerItems()
+
+	// gRPC
+	go func() {
+		grpcServer, lis := grpccon.NewGrpcServer(&s.cfg.Jwt, s.cfg.Grpc.InventoryUrl)
+
+		inventoryPb.RegisterInventoryGrpcServiceServer(grpcServer, grpcHandler)
+
+		log.Printf("Inventory gRPC server listening on %s", s.cfg.Grpc.InventoryUrl)
+		grpcServer.Serve(lis)
+	}()
 
 	inventory := s.app.Group("/inventory_v1")
 
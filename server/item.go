@@ -8,13 +8,17 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
 )
 
 func (s *server) itemService() {
 	repo := itemRepository.NewItemRepository(s.db)
-	usecase := itemUsecase.NewItemUsecase(repo)
+	usecase := itemUsecase.NewItemUsecase(repo, itemRepository.NewItemSearch(s.db))
 	httpHandler := itemHandler.NewItemHttpHandler(s.cfg, usecase)
 	grpcHandler := itemHandler.NewItemGrpcHandler(usecase)
+	queueHandler := itemHandler.NewItemQueueHandler(s.cfg, usecase)
+
+	go queueHandler.PublishItemAlerts()
 
 	// gRPC
 	go func() {
@@ -33,9 +37,24 @@ func (s *server) itemService() {
 	// Health Check
 	item.GET("", s.healthCheckService)
 
-	item.POST("/item", s.middleware.JwtAuthorization(s.middleware.RbacAuthorization(httpHandler.CreateItem, []int{1, 0})))
+	s.uploadsService(item)
+
+	item.POST("/item", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.CreateItem, rbac.ItemCreate)))
+	item.GET("/item/categories", httpHandler.FindItemCategories)
+	item.GET("/item/search", httpHandler.SearchItems)
+	item.POST("/item/import", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.ImportItems, rbac.ItemImport)))
+	item.GET("/item/export", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.ExportItems, rbac.ItemImport)))
+	item.POST("/item/campaigns", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.CreateItemCampaign, rbac.ItemPricing)))
+	item.GET("/item/campaigns", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindItemCampaigns, rbac.ItemPricing)))
+	item.PATCH("/item/campaigns/:campaign_id/end", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.EndItemCampaign, rbac.ItemPricing)))
 	item.GET("/item/:item_id", httpHandler.FindOneItem)
 	item.GET("/item", httpHandler.FindManyItems)
-	item.PATCH("/item/:item_id", s.middleware.JwtAuthorization(s.middleware.RbacAuthorization(httpHandler.EditItem, []int{1, 0})))
-	item.PATCH("/item/:item_id/is-activated", s.middleware.JwtAuthorization(s.middleware.RbacAuthorization(httpHandler.EnableOrDisableItem, []int{1, 0})))
+	item.PATCH("/item/:item_id", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.EditItem, rbac.ItemEdit)))
+	item.POST("/item/:item_id/image", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.UploadItemImage, rbac.ItemEdit)))
+	item.GET("/item/:item_id/revisions", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindItemRevisions, rbac.ItemEdit)))
+	item.POST("/item/:item_id/revisions/:revision/revert", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.RevertItem, rbac.ItemEdit)))
+	item.POST("/item/:item_id/price-schedules", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.CreateItemPriceSchedule, rbac.ItemPricing)))
+	item.GET("/item/:item_id/price-schedules", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindItemPriceSchedules, rbac.ItemPricing)))
+	item.DELETE("/item/:item_id/price-schedules/:schedule_id", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.CancelItemPriceSchedule, rbac.ItemPricing)))
+	item.PATCH("/item/:item_id/is-activated", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.EnableOrDisableItem, rbac.ItemEdit)))
 }
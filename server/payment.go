@@ -1,15 +1,33 @@
 package server
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentHandler"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
 )
 
 func (s *server) paymentService() {
 	repo := paymentRepository.NewPaymentRepository(s.db)
 	usecase := paymentUsecase.NewPaymentUsecase(repo)
 	httpHandler := paymentHandler.NewPaymentHttpHandler(s.cfg, usecase)
+	queueHandler := paymentHandler.NewPaymentQueueHandler(s.cfg, usecase)
+
+	go queueHandler.RecoverStaleOrders()
+
+	// Note that: the async sagas are waited for 3 step timeouts, a saga that is still running is recovered by the next start
+	s.shutdowns = append(s.shutdowns, func(pctx context.Context) {
+		ctx, cancel := context.WithTimeout(pctx, 3*time.Duration(s.cfg.Saga.StepTimeout)*time.Second)
+		defer cancel()
+
+		if err := usecase.WaitSagas(ctx); err != nil {
+			log.Printf("Error: WaitSagas failed: %s", err.Error())
+		}
+	})
 
 	payment := s.app.Group("/payment_v1")
 
@@ -18,4 +36,21 @@ func (s *server) paymentService() {
 
 	payment.POST("/payment/buy", httpHandler.BuyItem, s.middleware.JwtAuthorization)
 	payment.POST("/payment/sell", httpHandler.SellItem, s.middleware.JwtAuthorization)
+	payment.GET("/payment/orders/:order_id", httpHandler.FindOrder, s.middleware.JwtAuthorization)
+	payment.GET("/payment/orders/:order_id/events", httpHandler.OrderEvents, s.middleware.JwtAuthorization)
+	payment.POST("/payment/orders/:order_id/refund", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.RefundOrder, rbac.OrderRefund)))
+
+	payment.GET("/payment/cart", httpHandler.FindCart, s.middleware.JwtAuthorization)
+	payment.POST("/payment/cart/items", httpHandler.AddCartItem, s.middleware.JwtAuthorization)
+	payment.DELETE("/payment/cart/items/:item_id", httpHandler.RemoveCartItem, s.middleware.JwtAuthorization)
+	payment.DELETE("/payment/cart", httpHandler.ClearCart, s.middleware.JwtAuthorization)
+	payment.POST("/payment/cart/checkout", httpHandler.CheckoutCart, s.middleware.JwtAuthorization)
+
+	payment.POST("/payment/coupons", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.CreateCoupon, rbac.CouponManage)))
+	payment.GET("/payment/coupons", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindCoupons, rbac.CouponManage)))
+	payment.PATCH("/payment/coupons/:coupon_id/is-activated", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.EnableOrDisableCoupon, rbac.CouponManage)))
+
+	payment.GET("/payment/risk/players/:player_id", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindRiskRule, rbac.RiskManage)))
+	payment.PUT("/payment/risk/players/:player_id", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.UpsertRiskRule, rbac.RiskManage)))
+	payment.GET("/payment/risk/decisions", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindRiskDecisions, rbac.RiskManage)))
 }
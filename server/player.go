@@ -8,6 +8,7 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerUsecase"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
 )
 
 func (s *server) playerService() {
@@ -20,6 +21,8 @@ func (s *server) playerService() {
 	go queueHandler.DockedPlayerMoney()
 	go queueHandler.AddPlayerMoney()
 	go queueHandler.RollbackPlayerTransaction()
+	go queueHandler.DeletePlayerTransactions()
+	go queueHandler.NotifyItemAlerts()
 
 	// gRPC
 	go func() {
@@ -36,8 +39,27 @@ func (s *server) playerService() {
 	// Health Check
 	player.GET("", s.healthCheckService)
 
+	s.uploadsService(player)
+
 	player.POST("/player/register", httpHandler.CreatePlayer)
 	player.POST("/player/add-money", httpHandler.AddPlayerMoney, s.middleware.JwtAuthorization)
-	player.GET("/player/:player_id", httpHandler.FindOnePlayerProfile)
+	player.POST("/player/verify-email", httpHandler.VerifyEmail)
+	player.POST("/player/verify-email/resend", httpHandler.ResendEmailVerification)
+	player.PATCH("/player/me", httpHandler.UpdatePlayerProfile, s.middleware.JwtAuthorization)
+	player.POST("/player/me/email", httpHandler.ChangeEmail, s.middleware.JwtAuthorization)
+	player.POST("/player/me/avatar", httpHandler.UploadAvatar, s.middleware.JwtAuthorization)
+	player.DELETE("/player/me", httpHandler.DeletePlayer, s.middleware.JwtAuthorization)
+	player.GET("/player/me/wishlist", httpHandler.FindWishlist, s.middleware.JwtAuthorization)
+	player.POST("/player/me/wishlist", httpHandler.AddWishlistItem, s.middleware.JwtAuthorization)
+	player.DELETE("/player/me/wishlist/:item_id", httpHandler.RemoveWishlistItem, s.middleware.JwtAuthorization)
+	player.GET("/player/me/notifications", httpHandler.FindNotifications, s.middleware.JwtAuthorization)
+	player.PATCH("/player/me/notifications/:notification_id/read", httpHandler.ReadNotification, s.middleware.JwtAuthorization)
+	player.POST("/player/me/notifications/read-all", httpHandler.ReadAllNotifications, s.middleware.JwtAuthorization)
+	player.POST("/player/change-password", httpHandler.ChangePassword, s.middleware.JwtAuthorization)
+	player.POST("/player/forgot-password", httpHandler.ForgotPassword)
+	player.POST("/player/reset-password", httpHandler.ResetPassword)
+	player.GET("/player/admin/players", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.SearchPlayers, rbac.PlayerSearch)))
+	player.GET("/player/admin/players/:player_id", s.middleware.JwtAuthorization(s.middleware.PermissionAuthorization(httpHandler.FindPlayerOverview, rbac.PlayerSearch)))
+	player.GET("/player/:player_id", s.middleware.JwtAuthorization(s.middleware.PlayerIdParamOrPermissionAuthorization(httpHandler.FindOnePlayerProfile, rbac.PlayerSearch)))
 	player.GET("/player/saving-account/my-account", httpHandler.GetPlayerSavingAccount, s.middleware.JwtAuthorization)
 }
@@ -3,9 +3,11 @@ package server
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/middleware/middlewareHandler"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/middleware/middlewareRepository"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/middleware/middlewareUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
 	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -25,6 +28,9 @@ type (
 		db         *mongo.Client
 		cfg        *config.Config
 		middleware middlewareHandler.MiddlewareHandlerService
+		// Note that: the shutdowns run after the http server is shut down, the service stops when all of them are done
+		shutdowns []func(pctx context.Context)
+		done      chan struct{}
 	}
 )
 
@@ -34,6 +40,40 @@ func newMiddleware(cfg *config.Config) middlewareHandler.MiddlewareHandlerServic
 	return middlewareHandler.NewMiddlewareHandler(cfg, usecase)
 }
 
+// Dev only: serve the uploaded files from the local blob store, a key is never written twice so the files are cached for a year
+func (s *server) uploadsService(g *echo.Group) {
+	if (s.cfg.Blob.Type != "" && s.cfg.Blob.Type != "local") || s.cfg.Blob.LocalPath == "" {
+		return
+	}
+
+	g.GET("/uploads/*", echo.StaticDirectoryHandler(os.DirFS(s.cfg.Blob.LocalPath), false), func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			return next(c)
+		}
+	})
+}
+
+func newIPExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, v := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(v)
+		if err != nil {
+			log.Fatalf("Error: trusted proxy %s is invalid: %s", v, err.Error())
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 func (s *server) gracefulShutdown(pctx context.Context, quit <-chan os.Signal) {
 	log.Printf("Start service: %s", s.cfg.App.Name)
 
@@ -46,6 +86,11 @@ func (s *server) gracefulShutdown(pctx context.Context, quit <-chan os.Signal) {
 	if err := s.app.Shutdown(ctx); err != nil {
 		log.Fatalf("Error: %v", err)
 	}
+
+	for _, shutdown := range s.shutdowns {
+		shutdown(pctx)
+	}
+	close(s.done)
 }
 
 func (s *server) httpListening() {
@@ -60,14 +105,21 @@ func Start(pctx context.Context, cfg *config.Config, db *mongo.Client) {
 		db:         db,
 		cfg:        cfg,
 		middleware: newMiddleware(cfg),
+		done:       make(chan struct{}),
 	}
 
 	jwtauth.SetApiKey(cfg.Jwt.ApiSceretKey)
+	grpccon.SetTlsConfig(&cfg.Grpc)
+
+	// Real IP, X-Forwarded-For is only read from the trusted proxies so a client can not set its own ip
+	s.app.IPExtractor = newIPExtractor(cfg.App.TrustedProxies)
 
 	// Basic Middleware
-	// Request Timeout
+	// Request Timeout, the streams of events are skipped because the timeout holds the response until it is done
 	s.app.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
-		Skipper:      middleware.DefaultSkipper,
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/events")
+		},
 		ErrorMessage: "Error: Request Timeout",
 		Timeout:      30 * time.Second,
 	}))
@@ -105,4 +157,5 @@ func Start(pctx context.Context, cfg *config.Config, db *mongo.Client) {
 
 	// Listening
 	s.httpListening()
+	<-s.done
 }
@@ -0,0 +1,69 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentUsecase"
+	"github.com/stretchr/testify/assert"
+)
+
+// Cart
+// Add the same item twice
+// A disabled item is taken out of the cart
+// Checkout of a cart with a disabled item
+// Checkout of a cart that another checkout has claimed
+
+func TestCart(t *testing.T) {
+	ctx := context.Background()
+	cfg := new(config.Config)
+	sword := "item:65f5e4a1b4ba0d2b5c1e0a01"
+	shield := "item:65f5e4a1b4ba0d2b5c1e0a02"
+
+	repo := newTestPaymentRepository()
+	repo.items[sword] = &itemPb.Item{Id: sword, Title: "Diamond Sword", Price: 1000.5}
+	repo.items[shield] = &itemPb.Item{Id: shield, Title: "Iron Shield", Price: 200}
+	usecase := paymentUsecase.NewPaymentUsecase(repo)
+
+	fmt.Println("case -> 1")
+	_, err := usecase.AddCartItem(ctx, cfg, "player:001", &payment.AddCartItemReq{ItemId: sword})
+	assert.Nil(t, err)
+	_, err = usecase.AddCartItem(ctx, cfg, "player:001", &payment.AddCartItemReq{ItemId: shield})
+	assert.Nil(t, err)
+	res, err := usecase.AddCartItem(ctx, cfg, "player:001", &payment.AddCartItemReq{ItemId: sword, Quantity: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(res.Items))
+	assert.Equal(t, 3, res.Items[0].Quantity)
+	assert.Equal(t, 3201.5, res.Total)
+
+	_, err = usecase.AddCartItem(ctx, cfg, "player:001", &payment.AddCartItemReq{ItemId: sword, Quantity: 97})
+	assert.NotNil(t, err)
+
+	fmt.Println("case -> 2")
+	delete(repo.items, shield)
+	res, err = usecase.FindCart(ctx, cfg, "player:001")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{shield}, res.Removed)
+	assert.Equal(t, 1, len(repo.cart.Items))
+	assert.Equal(t, 3001.5, res.Total)
+
+	fmt.Println("case -> 3")
+	repo.cart.Items = append(repo.cart.Items, &payment.CartItem{ItemId: shield, Quantity: 1})
+	_, err = usecase.CheckoutCart(ctx, cfg, "player:001", new(payment.CartCheckoutReq))
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(repo.cart.Items))
+	assert.Empty(t, repo.cart.CheckingOut)
+
+	fmt.Println("case -> 4")
+	now := time.Now()
+	repo.cart.CheckingOut = "another"
+	repo.cart.CheckingOutAt = &now
+	_, err = usecase.CheckoutCart(ctx, cfg, "player:001", new(payment.CartCheckoutReq))
+	assert.NotNil(t, err)
+	assert.Equal(t, "another", repo.cart.CheckingOut)
+}
@@ -0,0 +1,85 @@
+package whydoweneedtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+// Coupon
+// Percent shared by the items
+// Fixed more than the order
+// Free item
+// Minimum spend without the free item
+// Expired and not active
+
+type (
+	testApplyCoupon struct {
+		coupon   *payment.Coupon
+		prices   []float64
+		expected []float64
+		discount float64
+	}
+)
+
+func TestCoupon(t *testing.T) {
+	now := time.Now()
+	newItems := func(prices []float64) []*payment.ItemServiceReqDatum {
+		items := make([]*payment.ItemServiceReqDatum, 0)
+		for i, price := range prices {
+			items = append(items, &payment.ItemServiceReqDatum{ItemId: fmt.Sprintf("item:%d", i), Price: price})
+		}
+		return items
+	}
+
+	tests := []testApplyCoupon{
+		{
+			coupon:   &payment.Coupon{BenefitType: payment.BenefitPercent, Value: 10},
+			prices:   []float64{100, 50.55},
+			expected: []float64{90, 45.49},
+			discount: 15.06,
+		},
+		{
+			coupon:   &payment.Coupon{BenefitType: payment.BenefitFixed, Value: 500},
+			prices:   []float64{100, 200},
+			expected: []float64{0, 0},
+			discount: 300,
+		},
+		{
+			coupon:   &payment.Coupon{BenefitType: payment.BenefitFreeItem, ItemId: "item:1"},
+			prices:   []float64{100, 200},
+			expected: []float64{100, 0},
+			discount: 200,
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+		items := newItems(test.prices)
+
+		discount := test.coupon.Apply(items)
+		assert.Equal(t, test.discount, discount)
+
+		total := 0.0
+		for j, v := range items {
+			assert.Equal(t, test.expected[j], v.Price)
+			total += v.Discount
+		}
+		assert.InDelta(t, test.discount, total, 0.001)
+	}
+
+	fmt.Println("case -> 4")
+	coupon := &payment.Coupon{BenefitType: payment.BenefitFreeItem, ItemId: "item:1", MinSpend: 150, IsActive: true, ExpiresAt: now.Add(time.Hour)}
+	assert.NotNil(t, coupon.Check(now, newItems([]float64{100, 200})))
+	assert.Nil(t, coupon.Check(now, newItems([]float64{100, 200, 50})))
+
+	fmt.Println("case -> 5")
+	coupon = &payment.Coupon{BenefitType: payment.BenefitPercent, Value: 10, IsActive: true, ExpiresAt: now}
+	assert.NotNil(t, coupon.Check(now, newItems([]float64{100})))
+	coupon.ExpiresAt = now.Add(time.Hour)
+	coupon.IsActive = false
+	assert.NotNil(t, coupon.Check(now, newItems([]float64{100})))
+}
@@ -0,0 +1,188 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerUsecase"
+	"github.com/stretchr/testify/assert"
+)
+
+// Email verification
+// Register creates an unverified player and sends a token
+// Register with an email that exists sends nothing
+// Verify without a token
+// Verify with a token that is not issued
+// Verify with the token
+// Verify with a token that is used already
+// Resend of a verified player sends nothing
+// Verify with a token that is expired
+// Resend of an unverified player sends a new token
+// Verify with a token of an email that is changed
+
+// Cases -> 13
+
+// Login of an unverified player
+// Unverified player when the config allows it
+// Unverified player when the config does not allow it
+// Verified player when the config does not allow it
+
+// Cases -> 3
+
+type (
+	testEmailVerify struct {
+		action string
+		email  string
+		// The token of a verify, the last token that is sent when it is empty
+		token            string
+		isExpired        bool
+		isEmailChanged   bool
+		isErr            bool
+		expectedVerified bool
+		expectedMessages int
+	}
+
+	testLoginUnverified struct {
+		isVerified           bool
+		allowUnverifiedLogin bool
+		isErr                bool
+	}
+)
+
+func TestEmailVerify(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+
+	repo := newTestPlayerRepository()
+	usecase := playerUsecase.NewPlayerUsecase(repo)
+
+	tests := []testEmailVerify{
+		{action: "register", email: "first@sekai.com", expectedMessages: 1},
+		{action: "register", email: "first@sekai.com", isErr: true, expectedMessages: 1},
+		{action: "verify", email: "first@sekai.com", token: "-", isErr: true, expectedMessages: 1},
+		{action: "verify", email: "first@sekai.com", token: "unknown", isErr: true, expectedMessages: 1},
+		{action: "verify", email: "first@sekai.com", expectedVerified: true, expectedMessages: 1},
+		{action: "verify", email: "first@sekai.com", isErr: true, expectedVerified: true, expectedMessages: 1},
+		{action: "resend", email: "first@sekai.com", expectedVerified: true, expectedMessages: 1},
+		{action: "register", email: "second@sekai.com", expectedMessages: 2},
+		{action: "verify", email: "second@sekai.com", isExpired: true, isErr: true, expectedMessages: 2},
+		{action: "resend", email: "second@sekai.com", expectedMessages: 3},
+		{action: "verify", email: "second@sekai.com", expectedVerified: true, expectedMessages: 3},
+		{action: "register", email: "third@sekai.com", expectedMessages: 4},
+		{action: "verify", email: "third@sekai.com", isEmailChanged: true, isErr: true, expectedMessages: 4},
+	}
+
+	tokenPattern := regexp.MustCompile(`verify your email: (\S+)`)
+	messageCount := func() int {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		return len(repo.messages)
+	}
+	// The players are found by the email that they register with
+	playerIds := make(map[string]string)
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		repo.mu.Lock()
+		if test.isExpired {
+			for _, v := range repo.emailVerifications {
+				v.ExpiresAt = time.Now().Add(-time.Second)
+			}
+		}
+		if test.isEmailChanged {
+			repo.players[playerIds[test.email]].Email = "changed@sekai.com"
+		}
+		repo.mu.Unlock()
+
+		var err error
+		switch test.action {
+		case "register":
+			var res *player.PlayerProfile
+			res, err = usecase.CreatePlayer(ctx, cfg, &player.CreatePlayerReq{
+				Email:    test.email,
+				Password: "123456",
+				Username: fmt.Sprintf("player%03d", i+1),
+			})
+			if err == nil {
+				assert.False(t, res.IsVerified)
+				playerIds[test.email] = res.Id
+			}
+		case "resend":
+			err = usecase.ResendEmailVerification(ctx, cfg, &player.ResendEmailVerificationReq{Email: test.email})
+			// Note that: the token is sent after the return
+			assert.Eventually(t, func() bool { return messageCount() == test.expectedMessages }, time.Second, 10*time.Millisecond)
+		case "verify":
+			token := test.token
+			switch token {
+			case "-":
+				token = ""
+			case "":
+				repo.mu.Lock()
+				matches := tokenPattern.FindStringSubmatch(repo.messages[len(repo.messages)-1].Body)
+				repo.mu.Unlock()
+				if assert.Len(t, matches, 2) {
+					token = matches[1]
+				}
+			}
+			err = usecase.VerifyEmail(ctx, &player.VerifyEmailReq{Token: token})
+		}
+
+		if test.isErr {
+			assert.NotEmpty(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+
+		repo.mu.Lock()
+		assert.Equal(t, test.expectedVerified, repo.players[playerIds[test.email]].IsVerified)
+		assert.Equal(t, test.expectedMessages, len(repo.messages))
+		repo.mu.Unlock()
+	}
+}
+
+func TestLoginUnverified(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []testLoginUnverified{
+		{isVerified: false, allowUnverifiedLogin: true, isErr: false},
+		{isVerified: false, allowUnverifiedLogin: false, isErr: true},
+		{isVerified: true, allowUnverifiedLogin: false, isErr: false},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		cfg := NewTestConfig()
+		cfg.Verify.AllowUnverifiedLogin = test.allowUnverifiedLogin
+
+		repo := newTestAuthRepository()
+		repo.profiles["success@sekai.com"] = &playerPb.PlayerProfile{
+			Id:         "001",
+			Email:      "success@sekai.com",
+			Username:   "player001",
+			IsVerified: test.isVerified,
+			CreatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+			UpdatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+		}
+		repo.passwords["success@sekai.com"] = "123456"
+		usecase := authUsecase.NewAuthUsecase(repo)
+
+		result, err := usecase.Login(ctx, cfg, &auth.PlayerLoginReq{Email: "success@sekai.com", Password: "123456"})
+
+		if test.isErr {
+			assert.NotEmpty(t, err)
+			assert.Empty(t, repo.credentials)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, "player:001", result.Credential.PlayerId)
+		}
+	}
+}
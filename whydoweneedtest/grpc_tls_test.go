@@ -0,0 +1,123 @@
+package whydoweneedtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/grpccon"
+	"github.com/stretchr/testify/assert"
+)
+
+// gRPC mTLS
+// Handshake with the first ca
+// Handshake after the ca and the certificate are rotated, the configs are not made again
+
+// writeTestCerts writes a new ca and one certificate of localhost signed by it, the serial of the certificate is returned
+func writeTestCerts(t *testing.T, dir string, modTime time.Time) *big.Int {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.Nil(t, err)
+	caCert, _ := x509.ParseCertificate(caDer)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	serial := big.NewInt(time.Now().UnixNano() + 1)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	assert.Nil(t, err)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+
+	files := map[string]*pem.Block{
+		"ca.crt":  {Type: "CERTIFICATE", Bytes: caDer},
+		"tls.crt": {Type: "CERTIFICATE", Bytes: der},
+		"tls.key": {Type: "EC PRIVATE KEY", Bytes: keyDer},
+	}
+	for name, block := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, pem.EncodeToMemory(block), 0600))
+		assert.Nil(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	return serial
+}
+
+// The handshake returns the serial of the certificate that the server showed
+func testTlsHandshake(serverCfg, clientCfg *tls.Config) (*big.Int, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	clientCfg = clientCfg.Clone()
+	clientCfg.ServerName = "localhost"
+
+	server := tls.Server(serverConn, serverCfg)
+	client := tls.Client(clientConn, clientCfg)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Handshake()
+	}()
+
+	if err := client.Handshake(); err != nil {
+		serverConn.Close()
+		<-errs
+		return nil, err
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return client.ConnectionState().PeerCertificates[0].SerialNumber, nil
+}
+
+func TestGrpcTls(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestCerts(t, dir, time.Now().Add(-time.Minute))
+
+	grpccon.SetTlsConfig(&config.Grpc{
+		TlsCaPath:   filepath.Join(dir, "ca.crt"),
+		TlsCertPath: filepath.Join(dir, "tls.crt"),
+		TlsKeyPath:  filepath.Join(dir, "tls.key"),
+	})
+	serverCfg := grpccon.ServerTlsConfig()
+	clientCfg := grpccon.ClientTlsConfig()
+
+	fmt.Println("case -> 1")
+	serial, err := testTlsHandshake(serverCfg, clientCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, first, serial)
+
+	fmt.Println("case -> 2")
+	second := writeTestCerts(t, dir, time.Now().Add(time.Minute))
+	serial, err = testTlsHandshake(serverCfg, clientCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, second, serial)
+}
@@ -0,0 +1,94 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefundPlayerItemRes
+// The items in the inventory are removed
+// An item that was consumed is skipped
+// An item that is being removed by a sell fails the refund
+// An item that was removed by a sell fails the refund
+
+// Cases -> 4
+
+type (
+	testRefundPlayerItem struct {
+		// The inventory ids of the refund, by what happened to them before the refund
+		kept        int
+		consumed    int
+		claimed     int
+		sold        int
+		expectedIds int
+		isErr       bool
+	}
+)
+
+func TestRefundPlayerItem(t *testing.T) {
+	ctx := context.Background()
+	cfg := new(config.Config)
+
+	tests := []testRefundPlayerItem{
+		{kept: 2, expectedIds: 2, isErr: false},
+		{kept: 1, consumed: 1, expectedIds: 1, isErr: false},
+		{kept: 1, claimed: 1, isErr: true},
+		{kept: 1, sold: 1, isErr: true},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		repo := newTestInventoryRepository()
+		usecase := inventoryUsecase.NewInventoryUsecase(repo)
+
+		sellId := primitive.NewObjectID().Hex()
+		refundId := primitive.NewObjectID().Hex()
+		req := &inventory.UpdateInventoryReq{OrderId: refundId, PlayerId: "player:001", Items: make([]*inventory.UpdateInventoryItem, 0)}
+
+		newItem := func() *inventory.Inventory {
+			v := &inventory.Inventory{Id: primitive.NewObjectID(), PlayerId: "player:001", ItemId: "item:001"}
+			req.Items = append(req.Items, &inventory.UpdateInventoryItem{InventoryId: v.Id.Hex(), ItemId: v.ItemId})
+			return v
+		}
+		for j := 0; j < test.kept; j++ {
+			v := newItem()
+			repo.items[v.Id.Hex()] = v
+		}
+		for j := 0; j < test.consumed; j++ {
+			newItem()
+		}
+		for j := 0; j < test.claimed; j++ {
+			v := newItem()
+			v.RemovingBy = sellId
+			repo.items[v.Id.Hex()] = v
+		}
+		for j := 0; j < test.sold; j++ {
+			v := newItem()
+			repo.removed[sellId] = &inventory.InventoryRemoved{OrderId: sellId, PlayerId: "player:001", Items: []*inventory.Inventory{v}}
+		}
+
+		usecase.RefundPlayerItemRes(ctx, cfg, req)
+
+		assert.Len(t, repo.replies, 1)
+		res := repo.replies[0]
+		if test.isErr {
+			assert.NotEmpty(t, res.Error)
+			assert.Len(t, repo.items, test.kept+test.claimed)
+			for _, v := range repo.items {
+				assert.NotEqual(t, refundId, v.RemovingBy)
+			}
+		} else {
+			assert.Empty(t, res.Error)
+			assert.Len(t, res.InventoryIds, test.expectedIds)
+			assert.Empty(t, repo.items)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Item alerts
+// The first price is only watched
+// A lower price is a price_drop
+// A higher price is not an alert
+// An item that is enabled again is a restock
+
+func TestItemAlert(t *testing.T) {
+	repo := &testItemRepository{
+		item: &item.Item{
+			Id:          primitive.NewObjectID(),
+			Title:       "Diamond Sword",
+			Price:       1000,
+			Damage:      100,
+			Category:    item.CategoryWeapon,
+			Rarity:      item.RarityCommon,
+			UsageStatus: true,
+			Revision:    1,
+		},
+	}
+	usecase := itemUsecase.NewItemUsecase(repo, nil)
+	ctx := context.Background()
+	itemId := repo.item.Id.Hex()
+
+	fmt.Println("case -> 1")
+	_, err := usecase.EditItem(ctx, "player:admin", itemId, &item.ItemUpdateReq{Price: 1200})
+	assert.Nil(t, err)
+	assert.Len(t, repo.alerts, 0)
+
+	fmt.Println("case -> 2")
+	_, err = usecase.EditItem(ctx, "player:admin", itemId, &item.ItemUpdateReq{Price: 900})
+	assert.Nil(t, err)
+	assert.Len(t, repo.alerts, 1)
+	assert.Equal(t, item.AlertPriceDrop, repo.alerts[0].Type)
+	assert.Equal(t, 900.0, repo.alerts[0].Price)
+	assert.Equal(t, 1200.0, repo.alerts[0].PreviousPrice)
+
+	fmt.Println("case -> 3")
+	_, err = usecase.EditItem(ctx, "player:admin", itemId, &item.ItemUpdateReq{Price: 950})
+	assert.Nil(t, err)
+	assert.Len(t, repo.alerts, 1)
+
+	fmt.Println("case -> 4")
+	_, err = usecase.EnableOrDisableItem(ctx, "player:admin", itemId)
+	assert.Nil(t, err)
+	assert.Len(t, repo.alerts, 1)
+
+	_, err = usecase.EnableOrDisableItem(ctx, "player:admin", itemId)
+	assert.Nil(t, err)
+	assert.Len(t, repo.alerts, 2)
+	assert.Equal(t, item.AlertRestock, repo.alerts[1].Type)
+}
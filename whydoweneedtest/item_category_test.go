@@ -0,0 +1,109 @@
+package whydoweneedtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/stretchr/testify/assert"
+)
+
+// Attributes of the item categories
+// Weapon attributes are converted to the types of the schema
+// Attribute that is not in the schema of the category
+// Required attribute that is missing
+// Int attribute that is not an integer
+// Number that is out of the range
+// String that is not in the enum
+// Number that is a string
+// Bool that is not a boolean
+// Consumable attributes, a string is trimmed and an optional attribute can be left out
+// Category that is not known
+
+// Cases -> 10
+
+type (
+	testItemAttributes struct {
+		category    string
+		attributes  map[string]any
+		expected    map[string]any
+		expectedErr string
+	}
+)
+
+func TestItemAttributes(t *testing.T) {
+	tests := []testItemAttributes{
+		{
+			category:   item.CategoryWeapon,
+			attributes: map[string]any{"durability": float64(100), "attack_speed": 1.5, "element": "fire", "two_handed": true},
+			expected:   map[string]any{"durability": int64(100), "attack_speed": 1.5, "element": "fire", "two_handed": true},
+		},
+		{
+			category:    item.CategoryWeapon,
+			attributes:  map[string]any{"defense": float64(10)},
+			expectedErr: "error: attribute defense is not allowed for category weapon",
+		},
+		{
+			category:    item.CategoryArmor,
+			attributes:  map[string]any{"defense": float64(10)},
+			expectedErr: "error: attribute slot is required for category armor",
+		},
+		{
+			category:    item.CategoryArmor,
+			attributes:  map[string]any{"defense": float64(10), "slot": "head", "durability": 1.5},
+			expectedErr: "error: attribute durability must be an integer",
+		},
+		{
+			category:    item.CategoryArmor,
+			attributes:  map[string]any{"defense": float64(-1), "slot": "head"},
+			expectedErr: "error: attribute defense must not be less than 0",
+		},
+		{
+			category:    item.CategoryArmor,
+			attributes:  map[string]any{"defense": float64(10), "slot": "tail"},
+			expectedErr: "error: attribute slot must be one of head, chest, legs, feet, hands, shield",
+		},
+		{
+			category:    item.CategoryConsumable,
+			attributes:  map[string]any{"effect": "heal", "potency": "10"},
+			expectedErr: "error: attribute potency must be a number",
+		},
+		{
+			category:    item.CategoryConsumable,
+			attributes:  map[string]any{"effect": "heal", "potency": float64(10), "stackable": "yes"},
+			expectedErr: "error: attribute stackable must be a boolean",
+		},
+		{
+			category:   item.CategoryConsumable,
+			attributes: map[string]any{"effect": " heal ", "potency": float64(50)},
+			expected:   map[string]any{"effect": "heal", "potency": int64(50)},
+		},
+		{
+			category:    "pet",
+			attributes:  map[string]any{},
+			expectedErr: "error: category must be one of weapon, armor, consumable",
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		result, err := func() (map[string]any, error) {
+			schema, err := item.FindCategorySchema(test.category)
+			if err != nil {
+				return nil, err
+			}
+			return schema.ValidateAttributes(test.attributes)
+		}()
+
+		if test.expectedErr != "" {
+			if assert.NotNil(t, err) {
+				assert.Equal(t, test.expectedErr, err.Error())
+			}
+			assert.Nil(t, result)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, result)
+		}
+	}
+}
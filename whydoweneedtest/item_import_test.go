@@ -0,0 +1,123 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImportItems
+// A new title creates the item
+// A row that is the same as the item is unchanged
+// A row that changes the item updates it
+// Dry run writes nothing
+// A title that is duplicated in the file fails the second row
+// A title that another import creates meanwhile updates that item
+// A find of the title that fails fails the row
+
+// Cases -> 7
+
+type (
+	testImportItems struct {
+		csv             string
+		dryRun          bool
+		isRaced         bool
+		isTitleFindFail bool
+		expected        *item.ItemImportRes
+		// The items that are inserted and the revisions that are written
+		expectedCreated   int
+		expectedRevisions int
+	}
+)
+
+func TestImportItems(t *testing.T) {
+	ctx := context.Background()
+	header := "title,price,damage,category,rarity\n"
+
+	tests := []testImportItems{
+		{
+			csv:               header + "Golden Sword,500,50,weapon,common\n",
+			expected:          &item.ItemImportRes{Total: 1, Created: 1},
+			expectedCreated:   1,
+			expectedRevisions: 1,
+		},
+		{
+			csv:      header + "Diamond Sword,1000,100,weapon,common\n",
+			expected: &item.ItemImportRes{Total: 1, Unchanged: 1},
+		},
+		{
+			csv:               header + "Diamond Sword,1500,100,weapon,common\n",
+			expected:          &item.ItemImportRes{Total: 1, Updated: 1},
+			expectedRevisions: 1,
+		},
+		{
+			csv:      header + "Golden Sword,500,50,weapon,common\nDiamond Sword,1500,100,weapon,common\n",
+			dryRun:   true,
+			expected: &item.ItemImportRes{DryRun: true, Total: 2, Created: 1, Updated: 1},
+		},
+		{
+			csv:               header + "Golden Sword,500,50,weapon,common\nGolden Sword,600,50,weapon,common\n",
+			expected:          &item.ItemImportRes{Total: 2, Created: 1, Failed: 1},
+			expectedCreated:   1,
+			expectedRevisions: 1,
+		},
+		{
+			csv:               header + "Silver Sword,800,80,weapon,common\n",
+			isRaced:           true,
+			expected:          &item.ItemImportRes{Total: 1, Updated: 1},
+			expectedRevisions: 1,
+		},
+		{
+			csv:             header + "Golden Sword,500,50,weapon,common\n",
+			isTitleFindFail: true,
+			expected:        &item.ItemImportRes{Total: 1, Failed: 1},
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		repo := &testItemRepository{
+			item: &item.Item{
+				Id:          primitive.NewObjectID(),
+				Title:       "Diamond Sword",
+				Price:       1000,
+				Damage:      100,
+				Category:    item.CategoryWeapon,
+				Rarity:      item.RarityCommon,
+				UsageStatus: true,
+				Revision:    1,
+			},
+			isTitleFindFail: test.isTitleFindFail,
+		}
+		if test.isRaced {
+			repo.raced = &item.Item{
+				Id:          primitive.NewObjectID(),
+				Title:       "Silver Sword",
+				Price:       700,
+				Damage:      80,
+				Category:    item.CategoryWeapon,
+				Rarity:      item.RarityCommon,
+				UsageStatus: true,
+				Revision:    1,
+			}
+		}
+		usecase := itemUsecase.NewItemUsecase(repo, nil)
+
+		result, err := usecase.ImportItems(ctx, "player:admin", itemUsecase.ImportFormatCsv, strings.NewReader(test.csv), test.dryRun)
+
+		assert.Nil(t, err)
+		assert.Len(t, result.Errors, test.expected.Failed)
+		result.Errors = nil
+		test.expected.Errors = nil
+		assert.Equal(t, test.expected, result)
+		assert.Len(t, repo.created, test.expectedCreated)
+		assert.Len(t, repo.revisions, test.expectedRevisions)
+	}
+}
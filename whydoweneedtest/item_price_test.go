@@ -0,0 +1,120 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Effective price
+// No campaign and no schedule
+// Percent campaign of a category
+// The lowest price of two campaigns
+// A campaign that has ended or not started
+// A schedule that has started, a superseded one and one in the future
+// The price of FindItemsInIds is the effective price
+// The effective price of a campaign is written to the item
+
+type (
+	testResolvePrice struct {
+		schedules []*item.ItemPriceSchedule
+		campaigns []*item.ItemCampaign
+		expected  float64
+	}
+)
+
+func TestResolvePrice(t *testing.T) {
+	now := time.Now()
+	itemId := "item:" + primitive.NewObjectID().Hex()
+
+	// A campaign of a category does not list the item, the item without a category is a weapon
+	campaign := func(discountType string, value float64, category string, start, end time.Duration) *item.ItemCampaign {
+		result := &item.ItemCampaign{
+			Id:            primitive.NewObjectID(),
+			DiscountType:  discountType,
+			DiscountValue: value,
+			ItemIds:       []string{itemId},
+			Category:      category,
+			StartAt:       now.Add(start),
+			EndAt:         now.Add(end),
+		}
+		if category != "" {
+			result.ItemIds = nil
+		}
+		return result
+	}
+	schedule := func(price float64, start time.Duration) *item.ItemPriceSchedule {
+		return &item.ItemPriceSchedule{ItemId: itemId, Price: price, StartAt: now.Add(start)}
+	}
+	superseded := schedule(10, -time.Minute)
+	superseded.SupersededAt = &now
+
+	anotherItem := campaign(item.DiscountPercent, 90, "", -time.Hour, time.Hour)
+	anotherItem.ItemIds = []string{"item:" + primitive.NewObjectID().Hex()}
+
+	tests := []testResolvePrice{
+		{
+			expected: 1000,
+		},
+		{
+			campaigns: []*item.ItemCampaign{anotherItem, campaign(item.DiscountPercent, 25, item.CategoryWeapon, -time.Hour, time.Hour)},
+			expected:  750,
+		},
+		{
+			campaigns: []*item.ItemCampaign{
+				campaign(item.DiscountPercent, 10, "", -time.Hour, time.Hour),
+				campaign(item.DiscountFixed, 300, "", -time.Hour, time.Hour),
+			},
+			expected: 700,
+		},
+		{
+			campaigns: []*item.ItemCampaign{
+				campaign(item.DiscountPercent, 50, "", -2*time.Hour, -time.Hour),
+				campaign(item.DiscountPercent, 50, "", time.Hour, 2*time.Hour),
+			},
+			expected: 1000,
+		},
+		{
+			schedules: []*item.ItemPriceSchedule{schedule(800, -2*time.Hour), schedule(900, -time.Hour), superseded, schedule(1, time.Hour)},
+			campaigns: []*item.ItemCampaign{campaign(item.DiscountFixed, 1000, "", -time.Hour, time.Hour)},
+			expected:  0,
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+		result := item.ResolvePrice(itemId, "", 1000, test.schedules, test.campaigns, now)
+		assert.Equal(t, test.expected, result.Price)
+	}
+
+	// The base price of the last case is the price of the latest schedule that has started
+	result := item.ResolvePrice(itemId, "", 1000, tests[4].schedules, tests[4].campaigns, now)
+	assert.Equal(t, 900.0, result.BasePrice)
+	assert.NotNil(t, result.Campaign)
+
+	fmt.Println("case -> 6")
+	repo := &testItemRepository{
+		campaigns: []*item.ItemCampaign{campaign(item.DiscountPercent, 20, "", -time.Hour, time.Hour)},
+		items: []*item.ItemShowCase{
+			{ItemId: itemId, Title: "Diamond Sword", Price: 1000, Category: item.CategoryWeapon},
+		},
+	}
+	usecase := itemUsecase.NewItemUsecase(repo, nil)
+
+	res, err := usecase.FindItemInIds(context.Background(), &itemPb.FindItemsInIdsReq{Ids: []string{itemId}})
+	assert.Nil(t, err)
+	assert.Equal(t, 800.0, res.Items[0].Price)
+
+	fmt.Println("case -> 7")
+	repo.items[0].Price, repo.items[0].EffectivePrice = 1000, 1000
+	err = usecase.RefreshEffectivePrices(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]float64{itemId: 800}, repo.effectivePrices)
+}
@@ -0,0 +1,87 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Item revisions
+// Edit writes a revision with the before, the after and the admin
+// Disable writes a revision
+// Revert is a new revision with the item of the reverted one
+// A failed revision puts the item back
+// Import of the same item writes no revision
+
+func TestItemRevision(t *testing.T) {
+	repo := &testItemRepository{
+		item: &item.Item{
+			Id:          primitive.NewObjectID(),
+			Title:       "Diamond Sword",
+			Price:       1000,
+			Damage:      100,
+			Category:    item.CategoryWeapon,
+			Rarity:      item.RarityCommon,
+			UsageStatus: true,
+			Revision:    1,
+		},
+	}
+	usecase := itemUsecase.NewItemUsecase(repo, nil)
+	ctx := context.Background()
+	itemId := repo.item.Id.Hex()
+
+	fmt.Println("case -> 1")
+	res, err := usecase.EditItem(ctx, "player:admin", itemId, &item.ItemUpdateReq{Price: 1500})
+	assert.Nil(t, err)
+	assert.Equal(t, 1500.0, res.Price)
+	assert.Equal(t, 2, res.Revision)
+
+	assert.Len(t, repo.revisions, 1)
+	assert.Equal(t, item.RevisionActionEdit, repo.revisions[0].Action)
+	assert.Equal(t, "player:admin", repo.revisions[0].PlayerId)
+	assert.Equal(t, "item:"+itemId, repo.revisions[0].ItemId)
+	assert.Equal(t, 1000.0, repo.revisions[0].Before.Price)
+	assert.Equal(t, 1500.0, repo.revisions[0].After.Price)
+
+	fmt.Println("case -> 2")
+	isActive, err := usecase.EnableOrDisableItem(ctx, "player:admin", itemId)
+	assert.Nil(t, err)
+	assert.False(t, isActive)
+	assert.Equal(t, item.RevisionActionDisable, repo.revisions[1].Action)
+	assert.Equal(t, 3, repo.revisions[1].Revision)
+
+	fmt.Println("case -> 3")
+	res, err = usecase.RevertItem(ctx, "player:other", itemId, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1500.0, res.Price)
+	assert.Equal(t, 4, res.Revision)
+	assert.True(t, repo.item.UsageStatus)
+	assert.Equal(t, item.RevisionActionRevert, repo.revisions[2].Action)
+	assert.Equal(t, 2, repo.revisions[2].RevertedFrom)
+
+	_, err = usecase.RevertItem(ctx, "player:other", itemId, 4)
+	assert.NotNil(t, err)
+	assert.Len(t, repo.revisions, 3)
+
+	fmt.Println("case -> 4")
+	repo.isRevisionFail = true
+	_, err = usecase.EditItem(ctx, "player:admin", itemId, &item.ItemUpdateReq{Price: 2000})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1500.0, repo.item.Price)
+	assert.Equal(t, 4, repo.item.Revision)
+	repo.isRevisionFail = false
+
+	fmt.Println("case -> 5")
+	csv := "title,price,damage,category,rarity\nDiamond Sword,1500,100,weapon,common\n"
+	importRes, err := usecase.ImportItems(ctx, "player:admin", itemUsecase.ImportFormatCsv, strings.NewReader(csv), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, importRes.Unchanged)
+	assert.Equal(t, 0, importRes.Updated)
+	assert.Len(t, repo.revisions, 3)
+}
@@ -0,0 +1,133 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SearchItems with the memory search backend
+// Relevance
+// Category filter with facets
+// Price range sorted by price
+// Special characters in the text
+// Invalid sort
+
+// SearchItemsPipeline of the mongo search backend
+// Relevance sorts on the text score that is added before the facet
+// Price sort without a text has no text score
+
+type (
+	testSearchItems struct {
+		req      *item.ItemFullTextSearchReq
+		expected []string
+		facets   map[string]int64
+		isErr    bool
+	}
+)
+
+func TestSearchItems(t *testing.T) {
+	now := time.Now()
+	newItem := func(title, category string, price float64, damage int, age time.Duration) *item.Item {
+		return &item.Item{
+			Id:             primitive.NewObjectID(),
+			Title:          title,
+			Price:          price,
+			EffectivePrice: price,
+			Damage:         damage,
+			Category:       category,
+			UsageStatus:    true,
+			CreatedAt:      now.Add(-age),
+		}
+	}
+
+	items := []*item.Item{
+		newItem("Diamond Sword", item.CategoryWeapon, 1000, 100, 3*time.Hour),
+		newItem("Iron Sword", item.CategoryWeapon, 500, 50, 2*time.Hour),
+		newItem("Iron Shield", item.CategoryArmor, 400, 0, time.Hour),
+		newItem("Health Potion", item.CategoryConsumable, 20, 0, 0),
+	}
+	disabled := newItem("Iron Iron Hammer", item.CategoryWeapon, 300, 30, 0)
+	disabled.UsageStatus = false
+	items = append(items, disabled)
+
+	usecase := itemUsecase.NewItemUsecase(&testItemRepository{}, itemRepository.NewItemMemorySearch(items))
+	ctx := context.Background()
+
+	tests := []testSearchItems{
+		{
+			req:      &item.ItemFullTextSearchReq{Q: "iron sword"},
+			expected: []string{"Iron Sword", "Diamond Sword", "Iron Shield"},
+			facets:   map[string]int64{item.CategoryWeapon: 2, item.CategoryArmor: 1},
+		},
+		{
+			req:      &item.ItemFullTextSearchReq{Q: "iron", Category: item.CategoryArmor},
+			expected: []string{"Iron Shield"},
+			facets:   map[string]int64{item.CategoryWeapon: 1, item.CategoryArmor: 1},
+		},
+		{
+			req:      &item.ItemFullTextSearchReq{MinPrice: "100", MaxPrice: "600", Sort: item.SortPriceDesc},
+			expected: []string{"Iron Sword", "Iron Shield"},
+			facets:   map[string]int64{item.CategoryWeapon: 1, item.CategoryArmor: 1},
+		},
+		{
+			req:      &item.ItemFullTextSearchReq{Q: "(a+)+$ .*"},
+			expected: []string{},
+			facets:   map[string]int64{},
+		},
+		{
+			req:   &item.ItemFullTextSearchReq{Sort: item.SortRelevance},
+			isErr: true,
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		result, err := usecase.SearchItems(ctx, test.req)
+
+		if test.isErr {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+
+		titles := make([]string, 0)
+		for _, v := range result.Data {
+			titles = append(titles, v.Title)
+		}
+		assert.Equal(t, test.expected, titles)
+		assert.Equal(t, test.facets, result.Facets.Category)
+	}
+}
+
+func TestSearchItemsPipeline(t *testing.T) {
+	facetSort := func(pipeline bson.A) bson.D {
+		facet := pipeline[len(pipeline)-1].(bson.D)[0].Value.(bson.D)
+		for _, stage := range facet[0].Value.(bson.A) {
+			if stage.(bson.D)[0].Key == "$sort" {
+				return stage.(bson.D)[0].Value.(bson.D)
+			}
+		}
+		return nil
+	}
+
+	fmt.Println("case -> 1")
+	pipeline := itemRepository.SearchItemsPipeline(&item.ItemSearchQuery{Text: "iron", Sort: item.SortRelevance, Limit: 10})
+	assert.Len(t, pipeline, 3)
+	assert.Equal(t, bson.D{{"$addFields", bson.D{{"score", bson.D{{"$meta", "textScore"}}}}}}, pipeline[1])
+	assert.Equal(t, bson.D{{"score", -1}, {"_id", 1}}, facetSort(pipeline))
+
+	fmt.Println("case -> 2")
+	pipeline = itemRepository.SearchItemsPipeline(&item.ItemSearchQuery{Sort: item.SortPriceAsc, Limit: 10})
+	assert.Len(t, pipeline, 2)
+	assert.Equal(t, bson.D{{"effective_price", 1}, {"_id", 1}}, facetSort(pipeline))
+}
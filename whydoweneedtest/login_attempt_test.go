@@ -0,0 +1,116 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
+	"github.com/stretchr/testify/assert"
+)
+
+// Login brute-force protection
+// Wrong password is counted
+// Valid password within the delay after the delay threshold is refused
+// Wrong password after the delay is counted
+// Wrong password at the lock threshold locks the email
+// Valid password while the email is locked
+// Valid password after an admin unlock logs in and clears the count
+// Unlock without an email or an ip
+
+// Cases -> 9
+
+type (
+	testLoginAttempt struct {
+		// The delay of the last failure is over before the login
+		isDelayOver bool
+		// An admin unlocks the email and the ip before the login
+		isUnlocked          bool
+		password            string
+		isErr               bool
+		expectedFailedCount int64
+		isLocked            bool
+	}
+)
+
+func TestLoginAttempt(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	cfg.Login.LockThreshold = 5
+
+	repo := newTestAuthRepository()
+	repo.profiles["success@sekai.com"] = &playerPb.PlayerProfile{
+		Id:         "001",
+		Email:      "success@sekai.com",
+		Username:   "player001",
+		IsVerified: true,
+		CreatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+		UpdatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+	}
+	repo.passwords["success@sekai.com"] = "123456"
+	usecase := authUsecase.NewAuthUsecase(repo)
+
+	tests := []testLoginAttempt{
+		{password: "000000", isErr: true, expectedFailedCount: 1},
+		{password: "000000", isErr: true, expectedFailedCount: 2},
+		{password: "000000", isErr: true, expectedFailedCount: 3},
+		{password: "123456", isErr: true, expectedFailedCount: 3},
+		{isDelayOver: true, password: "000000", isErr: true, expectedFailedCount: 4},
+		{isDelayOver: true, password: "000000", isErr: true, expectedFailedCount: 0, isLocked: true},
+		{isDelayOver: true, password: "123456", isErr: true, expectedFailedCount: 0, isLocked: true},
+		{isUnlocked: true, password: "123456", isErr: false, expectedFailedCount: 0},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		if test.isDelayOver {
+			repo.mu.Lock()
+			for _, attempt := range repo.attempts {
+				attempt.LastFailedAt = attempt.LastFailedAt.Add(-time.Duration(cfg.Login.MaxDelayDuration+1) * time.Second)
+			}
+			repo.mu.Unlock()
+		}
+		if test.isUnlocked {
+			count, err := usecase.UnlockLogin(ctx, &auth.UnlockLoginReq{Email: "Success@sekai.com", Ip: "127.0.0.1"})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), count)
+		}
+
+		result, err := usecase.Login(ctx, cfg, &auth.PlayerLoginReq{
+			Email:     "success@sekai.com",
+			Password:  test.password,
+			Ip:        "127.0.0.1",
+			UserAgent: "sekai-test",
+		})
+
+		if test.isErr {
+			assert.NotEmpty(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, "player:001", result.Credential.PlayerId)
+		}
+
+		attempt, ok := repo.attempts["email:success@sekai.com"]
+		if test.expectedFailedCount > 0 || test.isLocked {
+			if assert.True(t, ok) {
+				assert.Equal(t, test.expectedFailedCount, attempt.FailedCount)
+				assert.Equal(t, test.isLocked, time.Now().Before(attempt.LockedUntil))
+			}
+		} else {
+			assert.False(t, ok)
+		}
+
+		audit := repo.audits[len(repo.audits)-1]
+		assert.Equal(t, !test.isErr, audit.IsSuccess)
+		assert.Equal(t, "127.0.0.1", audit.Ip)
+		assert.Equal(t, "sekai-test", audit.UserAgent)
+	}
+
+	fmt.Printf("case -> %d\n", len(tests)+1)
+	_, err := usecase.UnlockLogin(ctx, &auth.UnlockLoginReq{})
+	assert.NotEmpty(t, err)
+}
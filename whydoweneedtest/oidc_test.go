@@ -0,0 +1,262 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// OIDC with a mock provider
+// Success
+// Wrong code verifier (PKCE)
+// Wrong nonce
+// Token signed by an unknown key
+// Token signed by an unknown key again, the keys are not refreshed twice in a minute
+
+type (
+	mockOidcProvider struct {
+		server     *httptest.Server
+		key        *rsa.PrivateKey
+		signingKey *rsa.PrivateKey
+		mu         sync.Mutex
+		codes      map[string]url.Values
+		// The count of the requests of the keys
+		jwksCount int
+	}
+
+	testOidc struct {
+		name         string
+		codeVerifier string
+		nonce        string
+		unknownKey   bool
+		isErr        bool
+	}
+)
+
+func newMockOidcProvider(t *testing.T) *mockOidcProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockOidcProvider{key: key, signingKey: key, codes: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	// The player consents right away
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		code, _ := oidc.RandomString(16)
+
+		p.mu.Lock()
+		p.codes[code] = query
+		p.mu.Unlock()
+
+		http.Redirect(w, r, query.Get("redirect_uri")+"?code="+code+"&state="+query.Get("state"), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		p.mu.Lock()
+		query, ok := p.codes[r.Form.Get("code")]
+		delete(p.codes, r.Form.Get("code"))
+		p.mu.Unlock()
+
+		hashed := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !ok || r.Form.Get("client_secret") != "secret" || base64.RawURLEncoding.EncodeToString(hashed[:]) != query.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            p.server.URL,
+			"aud":            query.Get("client_id"),
+			"sub":            "mock-subject-001",
+			"email":          "Social@Sekai.com",
+			"email_verified": true,
+			"name":           "social001",
+			"nonce":          query.Get("nonce"),
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = "mock-key"
+
+		p.mu.Lock()
+		idToken, _ := token.SignedString(p.signingKey)
+		p.mu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "mock-access-token",
+			"id_token":     idToken,
+			"token_type":   "Bearer",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.jwksCount++
+		p.mu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kid": "mock-key",
+					"kty": "RSA",
+					"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// Follow the authorization url like a browser and return the code of the redirect
+func (p *mockOidcProvider) authorize(t *testing.T, authorizationUrl string) (string, string) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	res, err := client.Get(authorizationUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestOidcLogin(t *testing.T) {
+	mockProvider := newMockOidcProvider(t)
+	repo := authRepository.NewAuthRepository(nil)
+
+	ctx := context.Background()
+
+	provider := &config.OidcProvider{
+		Name:         "mock",
+		Issuer:       mockProvider.server.URL,
+		ClientId:     "hello-sekai-shop",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://localhost:1323/auth_v1/auth/oidc/mock/callback",
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []testOidc{
+		{name: "success", isErr: false},
+		{name: "wrong code verifier", codeVerifier: "wrong-verifier", isErr: true},
+		{name: "wrong nonce", nonce: "wrong-nonce", isErr: true},
+		{name: "unknown key", unknownKey: true, isErr: true},
+		{name: "unknown key again", unknownKey: true, isErr: true},
+	}
+
+	for _, test := range tests {
+		codeVerifier, _ := oidc.NewCodeVerifier()
+		nonce, _ := oidc.RandomString(16)
+		state, _ := oidc.RandomString(16)
+
+		authorizationUrl, err := repo.OidcAuthCodeUrl(ctx, provider, state, nonce, oidc.CodeChallenge(codeVerifier))
+		assert.NoError(t, err, test.name)
+
+		code, returnedState := mockProvider.authorize(t, authorizationUrl)
+		assert.Equal(t, state, returnedState, test.name)
+
+		if test.codeVerifier != "" {
+			codeVerifier = test.codeVerifier
+		}
+		if test.nonce != "" {
+			nonce = test.nonce
+		}
+		mockProvider.mu.Lock()
+		mockProvider.signingKey = mockProvider.key
+		if test.unknownKey {
+			mockProvider.signingKey = otherKey
+		}
+		mockProvider.mu.Unlock()
+
+		identity, err := repo.OidcIdentity(ctx, provider, code, codeVerifier, nonce)
+		if test.isErr {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, "mock-subject-001", identity.Subject)
+		assert.Equal(t, "social@sekai.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "social001", identity.Name)
+	}
+
+	// The keys are fetched by the first token, the unknown keys in the same minute do not fetch them again
+	mockProvider.mu.Lock()
+	assert.Equal(t, 1, mockProvider.jwksCount)
+	mockProvider.mu.Unlock()
+}
+
+// OIDC userinfo
+// A provider that sends email_verified
+// A provider that sends only verified is not trusted
+// A provider that sends no claim of the verify
+
+func TestOidcUserInfo(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		claims        map[string]any
+		emailVerified bool
+	}{
+		{name: "case -> 1", claims: map[string]any{"sub": "001", "email": "Social@sekai.com", "email_verified": true}, emailVerified: true},
+		{name: "case -> 2", claims: map[string]any{"id": "002", "email": "social@sekai.com", "verified": true}, emailVerified: false},
+		{name: "case -> 3", claims: map[string]any{"id": "003", "email": "social@sekai.com"}, emailVerified: false},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(test.claims)
+		}))
+
+		identity, err := oidc.UserInfo(ctx, &oidc.Endpoints{UserInfoUrl: server.URL}, "access-token")
+		server.Close()
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, "social@sekai.com", identity.Email, test.name)
+		assert.Equal(t, test.emailVerified, identity.EmailVerified, test.name)
+	}
+}
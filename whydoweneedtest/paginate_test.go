@@ -0,0 +1,80 @@
+package whydoweneedtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cursor pagination
+// Next and previous cursors of a middle page
+// Tampered cursor
+// Cursor of another sort
+
+type (
+	testPaginateDoc struct {
+		id    primitive.ObjectID
+		price float64
+	}
+)
+
+func TestCursorPaginate(t *testing.T) {
+	secret := "test-secret"
+	sorts := []*models.SortField{
+		{Name: "id", Field: "_id", Kind: models.SortKindId},
+		{Name: "price", Field: "price", Kind: models.SortKindNumber},
+	}
+	key := func(v *testPaginateDoc) (any, primitive.ObjectID) { return v.price, v.id }
+
+	docs := []*testPaginateDoc{
+		{id: primitive.NewObjectID(), price: 10},
+		{id: primitive.NewObjectID(), price: 20},
+		{id: primitive.NewObjectID(), price: 30},
+	}
+
+	fmt.Println("case -> 1")
+	first, err := models.ParsePaginate(secret, "item", &models.PaginateReq{Limit: 2, Sort: "price"}, sorts)
+	assert.Nil(t, err)
+
+	page, next, prev := models.Page(first, append([]*testPaginateDoc{}, docs...), key)
+	assert.Equal(t, docs[:2], page)
+	assert.NotEmpty(t, next)
+	assert.Empty(t, prev)
+
+	second, err := models.ParsePaginate(secret, "item", &models.PaginateReq{Limit: 2, Sort: "price", Start: next}, sorts)
+	assert.Nil(t, err)
+
+	filter, ok, err := second.Filter()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "$or", filter.Key)
+
+	page, next, prev = models.Page(second, docs[2:], key)
+	assert.Equal(t, docs[2:], page)
+	assert.Empty(t, next)
+	assert.NotEmpty(t, prev)
+
+	// The previous page is read backward, so the documents come in the reverse order
+	back, err := models.ParsePaginate(secret, "item", &models.PaginateReq{Limit: 2, Sort: "price", Start: prev}, sorts)
+	assert.Nil(t, err)
+
+	page, next, prev = models.Page(back, []*testPaginateDoc{docs[1], docs[0]}, key)
+	assert.Equal(t, docs[:2], page)
+	assert.NotEmpty(t, next)
+	assert.Empty(t, prev)
+
+	fmt.Println("case -> 2")
+	_, err = models.ParsePaginate(secret, "item", &models.PaginateReq{Limit: 2, Sort: "price", Start: next + "x"}, sorts)
+	assert.NotNil(t, err)
+	_, err = models.ParsePaginate("another-secret", "item", &models.PaginateReq{Limit: 2, Sort: "price", Start: next}, sorts)
+	assert.NotNil(t, err)
+
+	fmt.Println("case -> 3")
+	_, err = models.ParsePaginate(secret, "item", &models.PaginateReq{Limit: 2, Sort: "id", Start: next}, sorts)
+	assert.NotNil(t, err)
+	_, err = models.ParsePaginate(secret, "inventory:player:1", &models.PaginateReq{Limit: 2, Sort: "price", Start: next}, sorts)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,140 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Change password and reset password
+// Change with a wrong current password
+// Change to the same password
+// Change revokes the sessions
+// Forgot password of an email that is not found sends nothing
+// Forgot password sends a reset token
+// Reset with a token that is not issued
+// Reset with the token revokes the sessions
+// Reset with a token that is used already
+// Change makes the token that is issued before it unusable
+// Reset with a token that is expired
+// Reset whose sessions are not revoked fails after the password is changed
+// Reset without a new password
+
+// Cases -> 16
+
+type (
+	testPassword struct {
+		action string
+		email  string
+		// The current password of a change, the token of a reset, the last token that is sent when it is empty
+		current     string
+		newPassword string
+		isExpired   bool
+		isRevokeErr bool
+		isErr       bool
+		// The password of the player after the case
+		expectedPassword string
+		expectedMessages int
+		expectedRevoked  int
+	}
+)
+
+func TestPassword(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+	playerId := primitive.NewObjectID()
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	repo := newTestPlayerRepository()
+	repo.players[playerId.Hex()] = &player.Player{
+		Id:       playerId,
+		Email:    "success@sekai.com",
+		Username: "player001",
+		Password: string(hashed),
+	}
+	usecase := playerUsecase.NewPlayerUsecase(repo)
+
+	tests := []testPassword{
+		{action: "change", current: "000000", newPassword: "654321", isErr: true, expectedPassword: "123456"},
+		{action: "change", current: "123456", newPassword: "123456", isErr: true, expectedPassword: "123456"},
+		{action: "change", current: "123456", newPassword: "654321", expectedPassword: "654321", expectedRevoked: 1},
+		{action: "forgot", email: "unknown@sekai.com", expectedPassword: "654321", expectedRevoked: 1},
+		{action: "forgot", email: "success@sekai.com", expectedPassword: "654321", expectedMessages: 1, expectedRevoked: 1},
+		{action: "reset", current: "unknown", newPassword: "abcdef", isErr: true, expectedPassword: "654321", expectedMessages: 1, expectedRevoked: 1},
+		{action: "reset", newPassword: "abcdef", expectedPassword: "abcdef", expectedMessages: 1, expectedRevoked: 2},
+		{action: "reset", newPassword: "qwerty", isErr: true, expectedPassword: "abcdef", expectedMessages: 1, expectedRevoked: 2},
+		{action: "forgot", email: "success@sekai.com", expectedPassword: "abcdef", expectedMessages: 2, expectedRevoked: 2},
+		{action: "change", current: "abcdef", newPassword: "qwerty", expectedPassword: "qwerty", expectedMessages: 2, expectedRevoked: 3},
+		{action: "reset", newPassword: "zxcvbn", isErr: true, expectedPassword: "qwerty", expectedMessages: 2, expectedRevoked: 3},
+		{action: "forgot", email: "success@sekai.com", expectedPassword: "qwerty", expectedMessages: 3, expectedRevoked: 3},
+		{action: "reset", newPassword: "zxcvbn", isExpired: true, isErr: true, expectedPassword: "qwerty", expectedMessages: 3, expectedRevoked: 3},
+		{action: "forgot", email: "success@sekai.com", expectedPassword: "qwerty", expectedMessages: 4, expectedRevoked: 3},
+		{action: "reset", newPassword: "zxcvbn", isRevokeErr: true, isErr: true, expectedPassword: "zxcvbn", expectedMessages: 4, expectedRevoked: 3},
+		{action: "reset", newPassword: "", isErr: true, expectedPassword: "zxcvbn", expectedMessages: 4, expectedRevoked: 3},
+	}
+
+	tokenPattern := regexp.MustCompile(`reset your password: (\S+)`)
+	messageCount := func() int {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		return len(repo.messages)
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		repo.mu.Lock()
+		repo.isRevokeFail = test.isRevokeErr
+		if test.isExpired {
+			for _, v := range repo.passwordResets {
+				v.ExpiresAt = time.Now().Add(-time.Second)
+			}
+		}
+		repo.mu.Unlock()
+
+		var err error
+		switch test.action {
+		case "change":
+			err = usecase.ChangePassword(ctx, cfg, &player.ChangePasswordReq{
+				PlayerId:        "player:" + playerId.Hex(),
+				CurrentPassword: test.current,
+				NewPassword:     test.newPassword,
+			})
+		case "forgot":
+			err = usecase.ForgotPassword(ctx, cfg, &player.ForgotPasswordReq{Email: test.email})
+			// Note that: the token is sent after the return
+			assert.Eventually(t, func() bool { return messageCount() == test.expectedMessages }, time.Second, 10*time.Millisecond)
+		case "reset":
+			token := test.current
+			if token == "" {
+				repo.mu.Lock()
+				matches := tokenPattern.FindStringSubmatch(repo.messages[len(repo.messages)-1].Body)
+				repo.mu.Unlock()
+				if assert.Len(t, matches, 2) {
+					token = matches[1]
+				}
+			}
+			err = usecase.ResetPassword(ctx, cfg, &player.ResetPasswordReq{Token: token, NewPassword: test.newPassword})
+		}
+
+		if test.isErr {
+			assert.NotEmpty(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+
+		repo.mu.Lock()
+		assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(repo.players[playerId.Hex()].Password), []byte(test.expectedPassword)))
+		assert.Equal(t, test.expectedMessages, len(repo.messages))
+		assert.Equal(t, test.expectedRevoked, len(repo.revoked))
+		repo.mu.Unlock()
+	}
+}
@@ -0,0 +1,58 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+)
+
+// CheckPermission
+// Player without any extra role
+// Admin
+// Player with a new role added after admin
+// Unknown permission
+
+type (
+	testCheckPermission struct {
+		ctx        context.Context
+		roleCode   int
+		permission string
+		expected   bool
+	}
+)
+
+func TestCheckPermission(t *testing.T) {
+	repoMock := new(authRepository.AuthRepositoryMock)
+	usecase := authUsecase.NewAuthUsecase(repoMock)
+
+	ctx := context.Background()
+
+	repoMock.On("FindAllRoles", ctx).Return([]*auth.Role{
+		{Title: "player", Code: 0, Permissions: []string{}},
+		{Title: "admin", Code: 1, Permissions: []string{rbac.ItemCreate, rbac.ItemEdit, rbac.RoleManage}},
+		{Title: "editor", Code: 2, Permissions: []string{rbac.ItemEdit}},
+	}, nil)
+
+	tests := []testCheckPermission{
+		{ctx: ctx, roleCode: 0, permission: rbac.ItemCreate, expected: false},
+		{ctx: ctx, roleCode: 1, permission: rbac.ItemCreate, expected: true},
+		{ctx: ctx, roleCode: 2, permission: rbac.ItemEdit, expected: true},
+		{ctx: ctx, roleCode: 2, permission: rbac.ItemCreate, expected: false},
+		{ctx: ctx, roleCode: 3, permission: "item:delete", expected: false},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		result, err := usecase.CheckPermission(test.ctx, test.roleCode, test.permission)
+
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, result.IsAllowed)
+	}
+}
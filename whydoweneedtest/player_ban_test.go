@@ -0,0 +1,138 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
+	"github.com/stretchr/testify/assert"
+)
+
+// Ban and suspend a player
+// Ban yourself
+// Ban without a reason
+// Suspend without an expiry
+// Ban of a kind that is not known
+// Ban of a player that is not found
+// Ban signs out every session of the player
+// Login of a banned player
+// Access token of a banned player
+// Unban lets the player login again
+// Unban of a player that is not banned
+// Login of a suspended player
+// Login after the suspension is over
+
+// Cases -> 13
+
+type (
+	testPlayerBan struct {
+		action    string
+		playerId  string
+		kind      string
+		reason    string
+		expiresIn time.Duration
+		// The suspension is over before the action
+		isExpired   bool
+		isErr       bool
+		expectedErr string
+	}
+)
+
+func TestPlayerBan(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+
+	repo := newTestAuthRepository()
+	repo.profiles["success@sekai.com"] = &playerPb.PlayerProfile{
+		Id:         "001",
+		Email:      "success@sekai.com",
+		Username:   "player001",
+		IsVerified: true,
+		CreatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+		UpdatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+	}
+	repo.passwords["success@sekai.com"] = "123456"
+	usecase := authUsecase.NewAuthUsecase(repo)
+
+	// The session that the ban signs out
+	_, err := usecase.Login(ctx, cfg, &auth.PlayerLoginReq{Email: "success@sekai.com", Password: "123456"})
+	assert.Nil(t, err)
+
+	tests := []testPlayerBan{
+		{action: "ban", playerId: "player:admin", kind: "ban", reason: "cheating", isErr: true, expectedErr: "error: you can not ban yourself"},
+		{action: "ban", playerId: "player:001", kind: "ban", reason: " ", isErr: true},
+		{action: "ban", playerId: "player:001", kind: "suspend", reason: "spamming", isErr: true},
+		{action: "ban", playerId: "player:001", kind: "mute", reason: "spamming", isErr: true},
+		{action: "ban", playerId: "player:002", kind: "ban", reason: "cheating", isErr: true},
+		{action: "ban", playerId: "player:001", kind: "ban", reason: "cheating"},
+		{action: "login", isErr: true, expectedErr: "error: player is banned: cheating"},
+		{action: "token"},
+		{action: "unban", playerId: "player:001"},
+		{action: "unban", playerId: "player:001", isErr: true, expectedErr: "error: player is not banned"},
+		{action: "ban", playerId: "player:001", kind: "suspend", reason: "spamming", expiresIn: time.Hour},
+		{action: "login", isErr: true, expectedErr: "error: player is suspended until"},
+		{action: "login", isExpired: true},
+	}
+
+	accessToken := ""
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		if test.isExpired {
+			repo.mu.Lock()
+			repo.bans["001"].ExpiresAt = time.Now().Add(-time.Second)
+			repo.mu.Unlock()
+		}
+
+		switch test.action {
+		case "ban":
+			req := &auth.BanPlayerReq{
+				PlayerId:  test.playerId,
+				CreatedBy: "player:admin",
+				Kind:      test.kind,
+				Reason:    test.reason,
+			}
+			if test.expiresIn > 0 {
+				req.ExpiresAt = time.Now().Add(test.expiresIn)
+			}
+			_, err = usecase.BanPlayer(ctx, cfg, req)
+			if err == nil {
+				assert.Empty(t, repo.credentials)
+			}
+		case "unban":
+			err = usecase.UnbanPlayer(ctx, test.playerId)
+		case "login":
+			var result *auth.ProfileIntercepter
+			result, err = usecase.Login(ctx, cfg, &auth.PlayerLoginReq{Email: "success@sekai.com", Password: "123456"})
+			if err == nil {
+				accessToken = result.Credential.AccessToken
+			}
+		case "token":
+			// Note that: the token of a banned player is not valid, it is not an error so the middleware can say why
+			repo.mu.Lock()
+			repo.credentials["banned"] = &auth.Credential{PlayerId: "player:001", AccessToken: "access:banned"}
+			repo.mu.Unlock()
+
+			res, _ := usecase.AccessTokenSearch(ctx, "access:banned")
+			assert.False(t, res.IsValid)
+			assert.True(t, res.IsBanned)
+			continue
+		}
+
+		if test.isErr {
+			if assert.NotNil(t, err) && test.expectedErr != "" {
+				assert.Contains(t, err.Error(), test.expectedErr)
+			}
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+
+	res, err := usecase.AccessTokenSearch(ctx, accessToken)
+	assert.Nil(t, err)
+	assert.True(t, res.IsValid)
+}
@@ -0,0 +1,76 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Player roles
+// Grant keeps the roles that the player has
+// Grant of a role that the player has already
+// Revoke removes only the role codes
+// Grants at the same moment keep every role
+// Grant and revoke of a player that is not found
+
+func TestPlayerRoles(t *testing.T) {
+	ctx := context.Background()
+	playerId := primitive.NewObjectID().Hex()
+
+	repo := newTestPlayerRepository()
+	repo.players[playerId] = &player.Player{
+		Id:          primitive.NewObjectID(),
+		PlayerRoles: []player.PlayerRole{{RoleTitle: "player", RoleCode: 0}},
+	}
+	usecase := playerUsecase.NewPlayerUsecase(repo)
+
+	roleCodes := func(res *playerPb.PlayerRolesRes) []int32 {
+		codes := make([]int32, 0)
+		for _, v := range res.Roles {
+			codes = append(codes, v.RoleCode)
+		}
+		return codes
+	}
+
+	fmt.Println("case -> 1")
+	res, err := usecase.AddPlayerRoles(ctx, &playerPb.AddPlayerRolesReq{PlayerId: playerId, Roles: []*playerPb.PlayerRole{{RoleTitle: "admin", RoleCode: 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{0, 1}, roleCodes(res))
+
+	fmt.Println("case -> 2")
+	res, err = usecase.AddPlayerRoles(ctx, &playerPb.AddPlayerRolesReq{PlayerId: playerId, Roles: []*playerPb.PlayerRole{{RoleTitle: "admin", RoleCode: 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{0, 1}, roleCodes(res))
+
+	fmt.Println("case -> 3")
+	res, err = usecase.RemovePlayerRoles(ctx, &playerPb.RemovePlayerRolesReq{PlayerId: playerId, RoleCodes: []int32{1, 7}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{0}, roleCodes(res))
+
+	fmt.Println("case -> 4")
+	var wg sync.WaitGroup
+	for i := 2; i < 12; i++ {
+		wg.Add(1)
+		go func(code int32) {
+			defer wg.Done()
+			usecase.AddPlayerRoles(ctx, &playerPb.AddPlayerRolesReq{PlayerId: playerId, Roles: []*playerPb.PlayerRole{{RoleTitle: fmt.Sprintf("role-%d", code), RoleCode: code}}})
+		}(int32(i))
+	}
+	wg.Wait()
+	res, err = usecase.FindPlayerRoles(ctx, playerId)
+	assert.Nil(t, err)
+	assert.Len(t, res.Roles, 11)
+
+	fmt.Println("case -> 5")
+	_, err = usecase.AddPlayerRoles(ctx, &playerPb.AddPlayerRolesReq{PlayerId: primitive.NewObjectID().Hex(), Roles: []*playerPb.PlayerRole{{RoleTitle: "admin", RoleCode: 1}}})
+	assert.NotNil(t, err)
+	_, err = usecase.RemovePlayerRoles(ctx, &playerPb.RemovePlayerRolesReq{PlayerId: primitive.NewObjectID().Hex(), RoleCodes: []int32{1}})
+	assert.NotNil(t, err)
+}
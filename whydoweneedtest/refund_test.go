@@ -0,0 +1,97 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentUsecase"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Refund
+// A sell can not be refunded
+// An amount more than the total
+// A buy that is refunded already
+// A buy that is claimed by another refund
+// An amount less than 0
+// A buy whose items were sold in part or all
+
+func TestRefund(t *testing.T) {
+	ctx := context.Background()
+	cfg := new(config.Config)
+
+	newOrder := func(orderType, status, refundId string) *payment.Order {
+		return &payment.Order{
+			Id:       primitive.NewObjectID(),
+			PlayerId: "player:001",
+			Type:     orderType,
+			Status:   status,
+			Items:    []*payment.OrderItem{{ItemId: "item:001", Price: 100, InventoryId: primitive.NewObjectID().Hex()}},
+			Total:    100,
+			RefundId: refundId,
+		}
+	}
+
+	sell := newOrder(payment.OrderSell, payment.OrderCompleted, "")
+	refunded := newOrder(payment.OrderBuy, payment.OrderCompleted, primitive.NewObjectID().Hex())
+	bought := newOrder(payment.OrderBuy, payment.OrderCompleted, "")
+
+	repo := newTestPaymentRepository()
+	for _, v := range []*payment.Order{sell, refunded, bought} {
+		repo.orders[v.Id.Hex()] = v
+	}
+	usecase := paymentUsecase.NewPaymentUsecase(repo)
+
+	fmt.Println("case -> 1")
+	_, err := usecase.RefundOrder(ctx, cfg, "player:admin", sell.Id.Hex(), &payment.RefundOrderReq{Reason: "support"})
+	assert.NotNil(t, err)
+
+	fmt.Println("case -> 2")
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", bought.Id.Hex(), &payment.RefundOrderReq{Amount: 100.01, Reason: "support"})
+	assert.NotNil(t, err)
+
+	fmt.Println("case -> 3")
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", refunded.Id.Hex(), &payment.RefundOrderReq{Reason: "support"})
+	assert.NotNil(t, err)
+
+	fmt.Println("case -> 4")
+	repo.claimedBy = primitive.NewObjectID().Hex()
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", bought.Id.Hex(), &payment.RefundOrderReq{Amount: 40, Reason: "support"})
+	assert.EqualError(t, err, "error: order is refunded already")
+	assert.Len(t, repo.orders, 4)
+	for _, v := range repo.orders {
+		if v.Type == payment.OrderRefund {
+			assert.Equal(t, payment.OrderFailed, v.Status)
+			assert.Equal(t, 40.0, v.Total)
+			assert.Equal(t, bought.Id.Hex(), v.RefundOf)
+		}
+	}
+
+	fmt.Println("case -> 5")
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", bought.Id.Hex(), &payment.RefundOrderReq{Amount: -1, Reason: "support"})
+	assert.EqualError(t, err, "error: amount is less than 0")
+
+	fmt.Println("case -> 6")
+	partly := newOrder(payment.OrderBuy, payment.OrderCompleted, "")
+	partly.Items = append(partly.Items, &payment.OrderItem{ItemId: "item:002", Price: 60, InventoryId: primitive.NewObjectID().Hex()})
+	partly.Total = 160
+	repo.orders[partly.Id.Hex()] = partly
+	repo.sold = []string{partly.Items[1].InventoryId}
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", partly.Id.Hex(), &payment.RefundOrderReq{Amount: 120, Reason: "support"})
+	assert.NotNil(t, err)
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", partly.Id.Hex(), &payment.RefundOrderReq{Reason: "support"})
+	assert.EqualError(t, err, "error: order is refunded already")
+	for _, v := range repo.orders {
+		if v.Type == payment.OrderRefund && v.RefundOf == partly.Id.Hex() {
+			assert.Equal(t, 100.0, v.Total)
+		}
+	}
+
+	repo.sold = append(repo.sold, partly.Items[0].InventoryId)
+	_, err = usecase.RefundOrder(ctx, cfg, "player:admin", partly.Id.Hex(), &payment.RefundOrderReq{Reason: "support"})
+	assert.NotNil(t, err)
+}
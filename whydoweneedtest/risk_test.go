@@ -0,0 +1,88 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Risk rules
+// A buy over the max order value
+// A buy after too many orders in a minute
+// A buy over the daily spend cap
+// A buy of a new account
+// A new account that is allowed by its rule
+// A buy that is counted by its own check
+
+func TestRisk(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		Risk: config.Risk{
+			DailySpendCap:      5000,
+			MaxOrderValue:      800,
+			MaxOrdersPerMinute: 3,
+			NewAccountAge:      3600,
+		},
+	}
+	buyReq := func() *payment.ItemServiceReq {
+		return &payment.ItemServiceReq{Items: []*payment.ItemServiceReqDatum{{ItemId: "item:65f5e4a1b4ba0d2b5c1e0a01"}}}
+	}
+
+	repo := newTestPaymentRepository()
+	repo.createdAt = utils.LocalTime().Add(-48 * time.Hour)
+	repo.items["item:65f5e4a1b4ba0d2b5c1e0a01"] = &itemPb.Item{Id: "item:65f5e4a1b4ba0d2b5c1e0a01", Title: "Diamond Sword", Price: 1000}
+	usecase := paymentUsecase.NewPaymentUsecase(repo)
+
+	fmt.Println("case -> 1")
+	_, err := usecase.BuyItem(ctx, cfg, "player:001", buyReq())
+	assert.NotNil(t, err)
+	assert.Equal(t, payment.RuleMaxOrderValue, repo.decisions[0].Rule)
+	assert.False(t, repo.decisions[0].IsAllowed)
+	assert.Equal(t, payment.OrderFailed, repo.orders[repo.decisions[0].OrderId].Status)
+
+	fmt.Println("case -> 2")
+	repo.rule = &payment.RiskRule{PlayerId: "player:001", MaxOrderValue: 2000}
+	// Note that: the count and the spend include the order of the buy, it is inserted before the check
+	repo.count = 4
+	_, err = usecase.BuyItem(ctx, cfg, "player:001", buyReq())
+	assert.NotNil(t, err)
+	assert.Equal(t, payment.RuleVelocity, repo.decisions[1].Rule)
+
+	fmt.Println("case -> 3")
+	repo.count = 0
+	repo.spent = 5500
+	_, err = usecase.BuyItem(ctx, cfg, "player:001", buyReq())
+	assert.NotNil(t, err)
+	assert.Equal(t, payment.RuleDailySpendCap, repo.decisions[2].Rule)
+
+	fmt.Println("case -> 4")
+	repo.spent = 0
+	repo.createdAt = utils.LocalTime().Add(-10 * time.Minute)
+	_, err = usecase.BuyItem(ctx, cfg, "player:001", buyReq())
+	assert.NotNil(t, err)
+	assert.Equal(t, payment.RuleNewAccount, repo.decisions[3].Rule)
+
+	fmt.Println("case -> 5")
+	repo.rule.AllowNewAccount = true
+	limits := repo.rule.Limits(&payment.RiskLimits{DailySpendCap: 5000, MaxOrderValue: 800, MaxOrdersPerMinute: 3, NewAccountAge: 3600})
+	rule, _ := limits.Evaluate(&payment.RiskCheck{Total: 1000, AccountAge: 10 * time.Minute})
+	assert.Empty(t, rule)
+	assert.Equal(t, 2000.0, limits.MaxOrderValue)
+	assert.Equal(t, int64(0), limits.NewAccountAge)
+
+	fmt.Println("case -> 6")
+	rule, _ = limits.Evaluate(&payment.RiskCheck{Total: 1000, SpentToday: 5000, OrdersLastMinute: 3})
+	assert.Empty(t, rule)
+	rule, _ = limits.Evaluate(&payment.RiskCheck{Total: 1000, SpentToday: 5000, OrdersLastMinute: 4})
+	assert.Equal(t, payment.RuleVelocity, rule)
+	rule, _ = limits.Evaluate(&payment.RiskCheck{Total: 1000, SpentToday: 5000.01, OrdersLastMinute: 3})
+	assert.Equal(t, payment.RuleDailySpendCap, rule)
+}
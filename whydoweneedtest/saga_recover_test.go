@@ -0,0 +1,100 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Saga recover of the async orders
+// An async buy whose saga fails is compensated before the sagas are waited
+// A stale buy is compensated by its order id and its coupon is released
+// A stale refund gives the claim of its buy back
+// A watcher of the order is notified when the order is saved
+// An order that is settled after it is found stale is not saved again and is not compensated
+
+func TestSagaRecover(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		// Note that: no broker listens on this url, the first step of the saga fails
+		Kafka:  config.Kafka{Url: "127.0.0.1:1"},
+		Saga:   config.Saga{StepTimeout: 1},
+		Verify: config.Verify{AllowUnverifiedBuy: true},
+	}
+
+	repo := newTestPaymentRepository()
+	repo.items["item:65f5e4a1b4ba0d2b5c1e0a01"] = &itemPb.Item{Id: "item:65f5e4a1b4ba0d2b5c1e0a01", Title: "Diamond Sword", Price: 1000}
+	usecase := paymentUsecase.NewPaymentUsecase(repo)
+
+	newOrder := func(orderType string) *payment.Order {
+		order := &payment.Order{
+			PlayerId:  "player:001",
+			Type:      orderType,
+			Status:    payment.OrderPending,
+			Step:      payment.StepDebit,
+			Total:     100,
+			UpdatedAt: utils.LocalTime().Add(-time.Minute),
+		}
+		repo.InsertOneOrder(ctx, order)
+		return order
+	}
+
+	fmt.Println("case -> 1")
+	res, err := usecase.BuyItemAsync(ctx, cfg, "player:001", &payment.ItemServiceReq{Items: []*payment.ItemServiceReqDatum{{ItemId: "item:65f5e4a1b4ba0d2b5c1e0a01"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, payment.OrderPending, res.Status)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	assert.Nil(t, usecase.WaitSagas(waitCtx))
+	assert.Equal(t, payment.OrderFailed, repo.orders[res.OrderId].Status)
+	assert.Contains(t, repo.rollbacks, "transaction:"+res.OrderId)
+
+	fmt.Println("case -> 2")
+	bought := newOrder(payment.OrderBuy)
+	bought.RedemptionId = primitive.NewObjectID().Hex()
+	assert.Nil(t, usecase.RecoverStaleOrders(ctx, cfg))
+	assert.Equal(t, payment.OrderFailed, bought.Status)
+	assert.Contains(t, repo.rollbacks, "add:"+bought.Id.Hex())
+	assert.Contains(t, repo.rollbacks, "transaction:"+bought.Id.Hex())
+	assert.Equal(t, []string{"coupon:001"}, repo.released)
+
+	fmt.Println("case -> 3")
+	refunded := newOrder(payment.OrderBuy)
+	refunded.Status = payment.OrderCompleted
+	refund := newOrder(payment.OrderRefund)
+	refund.RefundOf = refunded.Id.Hex()
+	refunded.RefundId = refund.Id.Hex()
+	assert.Nil(t, usecase.RecoverStaleOrders(ctx, cfg))
+	assert.Equal(t, payment.OrderFailed, refund.Status)
+	assert.Contains(t, repo.rollbacks, "remove:"+refund.Id.Hex())
+	assert.Empty(t, refunded.RefundId)
+
+	fmt.Println("case -> 4")
+	stale := newOrder(payment.OrderSell)
+	changed, stop := usecase.WatchOrder(stale.Id.Hex())
+	defer stop()
+	assert.Nil(t, usecase.RecoverStaleOrders(ctx, cfg))
+	select {
+	case <-changed:
+	default:
+		t.Error("watcher of the order is not notified")
+	}
+
+	fmt.Println("case -> 5")
+	settled := newOrder(payment.OrderSell)
+	repo.statuses[settled.Id.Hex()] = payment.OrderCompleted
+	assert.Nil(t, usecase.RecoverStaleOrders(ctx, cfg))
+	assert.Equal(t, payment.OrderCompleted, repo.statuses[settled.Id.Hex()])
+	assert.NotContains(t, repo.rollbacks, "transaction:"+settled.Id.Hex())
+	assert.NotContains(t, repo.rollbacks, "remove:"+settled.Id.Hex())
+}
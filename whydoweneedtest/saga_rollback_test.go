@@ -0,0 +1,45 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerUsecase"
+	"github.com/stretchr/testify/assert"
+)
+
+// Saga rollback of the player transactions
+// A debit of an order that is rolled back already
+// A rollback that comes while the debit is inserted
+// A debit of an order that is not rolled back
+
+func TestSagaRollback(t *testing.T) {
+	ctx := context.Background()
+	cfg := new(config.Config)
+
+	repo := newTestPlayerRepository()
+	usecase := playerUsecase.NewPlayerUsecase(repo)
+
+	fmt.Println("case -> 1")
+	usecase.RollbackPlayerTransaction(ctx, &player.RollbackPlayerTransactionReq{OrderId: "order:001"})
+	usecase.DockedPlayerMoneyRes(ctx, cfg, &player.CreatePlayerTransactionReq{OrderId: "order:001", PlayerId: "player:001", Amount: -100})
+	assert.Len(t, repo.transactions, 0)
+	assert.Equal(t, "order:001", repo.replies[0].OrderId)
+	assert.NotEmpty(t, repo.replies[0].Error)
+
+	fmt.Println("case -> 2")
+	repo.rollbackOnInsert = "order:002"
+	usecase.DockedPlayerMoneyRes(ctx, cfg, &player.CreatePlayerTransactionReq{OrderId: "order:002", PlayerId: "player:001", Amount: -100})
+	assert.Len(t, repo.transactions, 0)
+	assert.NotEmpty(t, repo.replies[1].Error)
+
+	fmt.Println("case -> 3")
+	usecase.DockedPlayerMoneyRes(ctx, cfg, &player.CreatePlayerTransactionReq{OrderId: "order:003", PlayerId: "player:001", Amount: -100})
+	assert.Len(t, repo.transactions, 1)
+	assert.Equal(t, "order:003", repo.replies[2].OrderId)
+	assert.Empty(t, repo.replies[2].Error)
+	assert.NotEmpty(t, repo.replies[2].TransactionId)
+}
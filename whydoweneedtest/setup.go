@@ -1,10 +1,1013 @@
 package whydoweneedtest
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
 	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	authPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/inventory/inventoryRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item"
+	itemPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/payment/paymentRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerRepository"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/jwtauth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/notifier"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/protobuf/proto"
 )
 
 func NewTestConfig() *config.Config {
 	cfg := config.LoadConfig("../env/test/.env")
 	return &cfg
 }
+
+// The repositories below keep their data in memory, a method that no test uses is not implemented and panics
+type (
+	testPaymentRepository struct {
+		paymentRepository.PaymentRepositoryService
+		mu sync.Mutex
+		// The items that are not in the map are disabled
+		items  map[string]*itemPb.Item
+		cart   *payment.Cart
+		orders map[string]*payment.Order
+		// The statuses that are saved, an order that is not pending is not saved again
+		statuses  map[string]string
+		rule      *payment.RiskRule
+		spent     float64
+		count     int64
+		createdAt time.Time
+		decisions []*payment.RiskDecision
+		// The inventory ids that were sold
+		sold []string
+		// The refund of another request that claims every buy first
+		claimedBy string
+		rollbacks []string
+		released  []string
+	}
+
+	testItemRepository struct {
+		itemRepository.ItemRepositoryService
+		campaigns []*item.ItemCampaign
+		schedules []*item.ItemPriceSchedule
+		items     []*item.ItemShowCase
+		// The effective prices that are written, by item id
+		effectivePrices map[string]float64
+		// The item of the revisions, it is found by FindManyItems when it is enabled
+		item           *item.Item
+		revisions      []*item.ItemRevision
+		isRevisionFail bool
+		watches        map[string]float64
+		alerts         []*item.ItemAlert
+		// The items that are inserted, and the item that another import inserts while a row of its title is imported
+		created         []*item.Item
+		raced           *item.Item
+		isTitleFindFail bool
+	}
+
+	testPlayerRepository struct {
+		playerRepository.PlayerRepositoryService
+		mu           sync.Mutex
+		players      map[string]*player.Player
+		transactions map[primitive.ObjectID]*player.PlayerTransaction
+		rollbacks    map[string]bool
+		replies      []*payment.PaymentTransferRes
+		// The order that is rolled back when its transaction is inserted
+		rollbackOnInsert   string
+		passwordResets     []*player.PasswordReset
+		emailVerifications []*player.EmailVerification
+		messages           []*notifier.Message
+		// The players whose sessions are revoked
+		revoked      []string
+		isRevokeFail bool
+	}
+
+	testInventoryRepository struct {
+		inventoryRepository.InventoryRepositoryService
+		// The inventories of the player by id, and the removed items that are kept by order id
+		items   map[string]*inventory.Inventory
+		removed map[string]*inventory.InventoryRemoved
+		replies []*payment.PaymentTransferRes
+	}
+
+	testAuthRepository struct {
+		authRepository.AuthRepositoryService
+		mu sync.Mutex
+		// The profiles and the passwords are found by email
+		profiles    map[string]*playerPb.PlayerProfile
+		passwords   map[string]string
+		attempts    map[string]*auth.LoginAttempt
+		twoFactors  map[string]*auth.TwoFactor
+		challenges  map[string]*auth.LoginChallenge
+		credentials map[string]*auth.Credential
+		audits      []*auth.LoginAudit
+		bans        map[string]*auth.PlayerBan
+	}
+)
+
+func newTestPaymentRepository() *testPaymentRepository {
+	return &testPaymentRepository{
+		items:    make(map[string]*itemPb.Item),
+		orders:   make(map[string]*payment.Order),
+		statuses: make(map[string]string),
+	}
+}
+
+func (r *testPaymentRepository) FindItemsInIds(pctx context.Context, grpcUrl string, req *itemPb.FindItemsInIdsReq) (*itemPb.FindItemsInIdsRes, error) {
+	result := &itemPb.FindItemsInIdsRes{Items: make([]*itemPb.Item, 0)}
+	for _, id := range req.Ids {
+		if v, ok := r.items[id]; ok {
+			result.Items = append(result.Items, v)
+		}
+	}
+	return result, nil
+}
+
+func (r *testPaymentRepository) FindOnePlayerProfile(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	return &playerPb.PlayerProfile{Id: req.PlayerId, IsVerified: true, CreatedAt: r.createdAt.String()}, nil
+}
+
+func (r *testPaymentRepository) FindOneCart(pctx context.Context, playerId string) (*payment.Cart, error) {
+	if r.cart == nil {
+		return &payment.Cart{PlayerId: playerId, Items: make([]*payment.CartItem, 0)}, nil
+	}
+	return r.cart, nil
+}
+
+func (r *testPaymentRepository) SaveOneCart(pctx context.Context, req *payment.Cart) error {
+	req.Version++
+	r.cart = req
+	return nil
+}
+
+func (r *testPaymentRepository) ClaimOneCart(pctx context.Context, playerId string, version int, claim string, now, staleBefore time.Time) (*payment.Cart, error) {
+	if r.cart == nil || r.cart.Version != version || (r.cart.CheckingOut != "" && !r.cart.CheckingOutAt.Before(staleBefore)) {
+		return nil, errors.New("error: cart is being checked out or has been changed by another request, try again")
+	}
+	r.cart.CheckingOut = claim
+	r.cart.CheckingOutAt = &now
+	return r.cart, nil
+}
+
+func (r *testPaymentRepository) ReleaseOneCart(pctx context.Context, playerId, claim string) error {
+	if r.cart != nil && r.cart.CheckingOut == claim {
+		r.cart.CheckingOut = ""
+		r.cart.CheckingOutAt = nil
+	}
+	return nil
+}
+
+func (r *testPaymentRepository) InsertOneOrder(pctx context.Context, req *payment.Order) (primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req.Id = primitive.NewObjectID()
+	r.orders[req.Id.Hex()] = req
+	r.statuses[req.Id.Hex()] = req.Status
+	return req.Id, nil
+}
+
+func (r *testPaymentRepository) UpdateOneOrder(pctx context.Context, req *payment.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.statuses[req.Id.Hex()] != payment.OrderPending {
+		return paymentRepository.ErrOrderSettled
+	}
+	r.statuses[req.Id.Hex()] = req.Status
+	return nil
+}
+
+func (r *testPaymentRepository) FindOneOrder(pctx context.Context, orderId string) (*payment.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.orders[orderId]; ok {
+		return v, nil
+	}
+	return nil, errors.New("error: order not found")
+}
+
+func (r *testPaymentRepository) FindManyStaleOrders(pctx context.Context, before time.Time) ([]*payment.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]*payment.Order, 0)
+	for _, v := range r.orders {
+		if v.Status == payment.OrderPending && v.UpdatedAt.Before(before) {
+			results = append(results, v)
+		}
+	}
+	return results, nil
+}
+
+func (r *testPaymentRepository) ClaimOneOrderRefund(pctx context.Context, orderId, refundId string) (bool, error) {
+	if r.claimedBy != "" {
+		return false, nil
+	}
+	r.orders[orderId].RefundId = refundId
+	return true, nil
+}
+
+func (r *testPaymentRepository) ReleaseOneOrderRefund(pctx context.Context, orderId, refundId string) error {
+	if v, ok := r.orders[orderId]; ok && v.RefundId == refundId {
+		v.RefundId = ""
+	}
+	return nil
+}
+
+func (r *testPaymentRepository) FindManySoldInventoryIds(pctx context.Context, playerId string, inventoryIds []string) ([]string, error) {
+	results := make([]string, 0)
+	for _, v := range inventoryIds {
+		for _, s := range r.sold {
+			if v == s {
+				results = append(results, v)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (r *testPaymentRepository) RollbackTransaction(pctx context.Context, cfg *config.Config, req *player.RollbackPlayerTransactionReq) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollbacks = append(r.rollbacks, "transaction:"+req.OrderId)
+	return nil
+}
+
+func (r *testPaymentRepository) RollbackAddPlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollbacks = append(r.rollbacks, "add:"+req.OrderId)
+	return nil
+}
+
+func (r *testPaymentRepository) RollbackRemovePlayerItem(pctx context.Context, cfg *config.Config, req *inventory.RollbackPlayerInventoryReq) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollbacks = append(r.rollbacks, "remove:"+req.OrderId)
+	return nil
+}
+
+func (r *testPaymentRepository) FindOneCouponRedemption(pctx context.Context, redemptionId string) (*payment.CouponRedemption, error) {
+	return &payment.CouponRedemption{Id: utils.ConvertToObjectId(redemptionId), CouponId: "coupon:001", PlayerId: "player:001"}, nil
+}
+
+func (r *testPaymentRepository) UpdateOneCouponRedemptionStatus(pctx context.Context, redemptionId primitive.ObjectID, from, to string) (bool, error) {
+	return true, nil
+}
+
+func (r *testPaymentRepository) ReleaseCouponUse(pctx context.Context, couponId string) error {
+	r.released = append(r.released, couponId)
+	return nil
+}
+
+func (r *testPaymentRepository) ReleaseCouponPlayerUse(pctx context.Context, couponId, playerId string) error {
+	return nil
+}
+
+func (r *testPaymentRepository) FindOneRiskRule(pctx context.Context, playerId string) (*payment.RiskRule, error) {
+	return r.rule, nil
+}
+
+func (r *testPaymentRepository) SumPlayerSpend(pctx context.Context, playerId string, since time.Time) (float64, error) {
+	return r.spent, nil
+}
+
+func (r *testPaymentRepository) CountPlayerOrders(pctx context.Context, playerId string, since time.Time) (int64, error) {
+	return r.count, nil
+}
+
+func (r *testPaymentRepository) InsertOneRiskDecision(pctx context.Context, req *payment.RiskDecision) error {
+	r.decisions = append(r.decisions, req)
+	return nil
+}
+
+// The filters of the repository are done by the resolver too, so every campaign and schedule is returned
+func (r *testItemRepository) FindManyItemCampaigns(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemCampaign, error) {
+	return r.campaigns, nil
+}
+
+func (r *testItemRepository) FindManyItemPriceSchedules(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemPriceSchedule, error) {
+	return r.schedules, nil
+}
+
+func (r *testItemRepository) SupersedeItemPriceSchedules(pctx context.Context, itemId string, now time.Time) error {
+	return nil
+}
+
+func (r *testItemRepository) UpdateOneItemEffectivePrice(pctx context.Context, itemId string, price float64) error {
+	if r.effectivePrices == nil {
+		r.effectivePrices = make(map[string]float64)
+	}
+	r.effectivePrices[itemId] = price
+	return nil
+}
+
+func (r *testItemRepository) FindManyItems(pctx context.Context, filter primitive.D, opts []*options.FindOptions) ([]*item.ItemShowCase, error) {
+	results := append(make([]*item.ItemShowCase, 0), r.items...)
+	if r.item != nil && r.item.UsageStatus {
+		results = append(results, &item.ItemShowCase{ItemId: "item:" + r.item.Id.Hex(), Title: r.item.Title, Price: r.item.Price, EffectivePrice: r.item.EffectivePrice})
+	}
+	return results, nil
+}
+
+func (r *testItemRepository) FindOneItem(pctx context.Context, itemId string) (*item.Item, error) {
+	if r.item == nil || r.item.Id.Hex() != itemId {
+		return nil, errors.New("error: item not found")
+	}
+	result := *r.item
+	return &result, nil
+}
+
+func (r *testItemRepository) IsUniqueItem(pctx context.Context, title string) bool {
+	return r.item == nil || r.item.Title != title
+}
+
+func (r *testItemRepository) UpdateOneItemRevision(pctx context.Context, itemId string, revision int, req primitive.M) (*item.Item, error) {
+	if r.item.Revision != revision {
+		return nil, errors.New("error: item has been changed by another request, try again")
+	}
+
+	raw, _ := bson.Marshal(r.item)
+	doc := bson.M{}
+	bson.Unmarshal(raw, &doc)
+	for k, v := range req {
+		doc[k] = v
+	}
+	doc["revision"] = revision + 1
+
+	raw, _ = bson.Marshal(doc)
+	result := new(item.Item)
+	bson.Unmarshal(raw, result)
+
+	r.item = result
+	after := *result
+	return &after, nil
+}
+
+func (r *testItemRepository) RestoreOneItemRevision(pctx context.Context, req *item.Item, revision int) error {
+	if r.item.Revision != revision {
+		return errors.New("error: restore one item failed")
+	}
+	result := *req
+	r.item = &result
+	return nil
+}
+
+func (r *testItemRepository) FindOneItemByTitle(pctx context.Context, title string) (*item.Item, error) {
+	if r.isTitleFindFail {
+		return nil, errors.New("error: find item by title failed")
+	}
+	for _, v := range append([]*item.Item{r.item}, r.created...) {
+		if v != nil && v.Title == title {
+			result := *v
+			return &result, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testItemRepository) InsertOneItem(pctx context.Context, req *item.Item) (primitive.ObjectID, error) {
+	if r.raced != nil && r.raced.Title == req.Title {
+		r.item, r.raced = r.raced, nil
+		return primitive.NilObjectID, itemRepository.ErrItemTitleExists
+	}
+	if existed, _ := r.FindOneItemByTitle(pctx, req.Title); existed != nil {
+		return primitive.NilObjectID, itemRepository.ErrItemTitleExists
+	}
+	req.Id = primitive.NewObjectID()
+	r.created = append(r.created, req)
+	return req.Id, nil
+}
+
+func (r *testItemRepository) InsertOneItemRevision(pctx context.Context, req *item.ItemRevision) error {
+	if r.isRevisionFail {
+		return errors.New("error: insert one item revision failed")
+	}
+	r.revisions = append(r.revisions, req)
+	return nil
+}
+
+func (r *testItemRepository) FindOneItemRevision(pctx context.Context, itemId string, revision int) (*item.ItemRevision, error) {
+	for _, v := range r.revisions {
+		if v.ItemId == itemId && v.Revision == revision {
+			return v, nil
+		}
+	}
+	return nil, errors.New("error: item revision not found")
+}
+
+func (r *testItemRepository) SwapItemPriceWatch(pctx context.Context, itemId string, price float64, now time.Time) (float64, bool, error) {
+	if r.watches == nil {
+		r.watches = make(map[string]float64)
+	}
+	previous, ok := r.watches[itemId]
+	r.watches[itemId] = price
+	return previous, ok && previous != price, nil
+}
+
+func (r *testItemRepository) InsertOneItemAlert(pctx context.Context, req *item.ItemAlert) error {
+	r.alerts = append(r.alerts, req)
+	return nil
+}
+
+func newTestPlayerRepository() *testPlayerRepository {
+	return &testPlayerRepository{
+		players:      make(map[string]*player.Player),
+		transactions: make(map[primitive.ObjectID]*player.PlayerTransaction),
+		rollbacks:    make(map[string]bool),
+	}
+}
+
+func (r *testPlayerRepository) FindOnePlayerProfileToRefresh(pctx context.Context, playerId string) (*player.Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.players[playerId]
+	if !ok {
+		return nil, errors.New("error: player profile not found")
+	}
+	result := *v
+	result.PlayerRoles = append(make([]player.PlayerRole, 0), v.PlayerRoles...)
+	return &result, nil
+}
+
+// The roles are added like $addToSet, a role that is the same is not added twice
+func (r *testPlayerRepository) AddManyPlayerRoles(pctx context.Context, playerId string, roles []player.PlayerRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.players[playerId]
+	if !ok {
+		return errors.New("error: player not found")
+	}
+	for _, role := range roles {
+		isExist := false
+		for _, current := range v.PlayerRoles {
+			if current == role {
+				isExist = true
+				break
+			}
+		}
+		if !isExist {
+			v.PlayerRoles = append(v.PlayerRoles, role)
+		}
+	}
+	return nil
+}
+
+func (r *testPlayerRepository) RemoveManyPlayerRoles(pctx context.Context, playerId string, roleCodes []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.players[playerId]
+	if !ok {
+		return errors.New("error: player not found")
+	}
+	roles := make([]player.PlayerRole, 0)
+	for _, current := range v.PlayerRoles {
+		isRemoved := false
+		for _, code := range roleCodes {
+			if current.RoleCode == code {
+				isRemoved = true
+				break
+			}
+		}
+		if !isRemoved {
+			roles = append(roles, current)
+		}
+	}
+	v.PlayerRoles = roles
+	return nil
+}
+
+func (r *testPlayerRepository) GetPlayerSavingAccount(pctx context.Context, playerId string) (*player.PlayerSavingAccount, error) {
+	return &player.PlayerSavingAccount{PlayerId: playerId, Balance: 1000}, nil
+}
+
+func (r *testPlayerRepository) InsertOnePlayerTranscation(pctx context.Context, req *player.PlayerTransaction) (primitive.ObjectID, error) {
+	req.Id = primitive.NewObjectID()
+	r.transactions[req.Id] = req
+	if req.OrderId == r.rollbackOnInsert {
+		r.rollbacks[req.OrderId] = true
+	}
+	return req.Id, nil
+}
+
+func (r *testPlayerRepository) DeleteManyOrderTransactions(pctx context.Context, orderId string) error {
+	for id, v := range r.transactions {
+		if v.OrderId == orderId {
+			delete(r.transactions, id)
+		}
+	}
+	return nil
+}
+
+func (r *testPlayerRepository) UpsertOneTransactionRollback(pctx context.Context, orderId string) error {
+	r.rollbacks[orderId] = true
+	return nil
+}
+
+func (r *testPlayerRepository) FindOneTransactionRollback(pctx context.Context, orderId string) bool {
+	return r.rollbacks[orderId]
+}
+
+func (r *testPlayerRepository) DockedPlayerMoneyRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
+	r.replies = append(r.replies, req)
+	return nil
+}
+
+func (r *testPlayerRepository) IsUniquePlayer(pctx context.Context, email, username string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.players {
+		if v.Email == email || v.Username == username {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *testPlayerRepository) InsertOnePlayer(pctx context.Context, req *player.Player) (primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := *req
+	result.Id = primitive.NewObjectID()
+	r.players[result.Id.Hex()] = &result
+	return result.Id, nil
+}
+
+func (r *testPlayerRepository) FindOnePlayerProfile(pctx context.Context, playerId string) (*player.PlayerProfileBson, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.players[playerId]
+	if !ok {
+		return nil, errors.New("error: player profile not found")
+	}
+	return &player.PlayerProfileBson{
+		Id:         v.Id,
+		Email:      v.Email,
+		Username:   v.Username,
+		IsVerified: v.IsVerified,
+		CreatedAt:  v.CreatedAt,
+		UpdatedAt:  v.UpdatedAt,
+	}, nil
+}
+
+func (r *testPlayerRepository) FindOnePlayerCredential(pctx context.Context, email string) (*player.Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.players {
+		if v.Email == email {
+			result := *v
+			return &result, nil
+		}
+	}
+	return nil, errors.New("error: email is invalid")
+}
+
+func (r *testPlayerRepository) UpdateOnePlayerPassword(pctx context.Context, playerId, hashedPassword string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.players[playerId]
+	if !ok {
+		return errors.New("error: update player password failed")
+	}
+	v.Password = hashedPassword
+	return nil
+}
+
+func (r *testPlayerRepository) InsertOnePasswordReset(pctx context.Context, req *player.PasswordReset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := *req
+	r.passwordResets = append(r.passwordResets, &result)
+	return nil
+}
+
+func (r *testPlayerRepository) UseOnePasswordReset(pctx context.Context, tokenHash string) (*player.PasswordReset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := utils.LocalTime()
+	for _, v := range r.passwordResets {
+		if v.TokenHash == tokenHash && !v.IsUsed && v.ExpiresAt.After(now) {
+			v.IsUsed = true
+			v.UsedAt = now
+			result := *v
+			return &result, nil
+		}
+	}
+	return nil, errors.New("error: reset token is invalid or expired")
+}
+
+func (r *testPlayerRepository) UpdateManyPasswordResetsUsed(pctx context.Context, playerId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.passwordResets {
+		if v.PlayerId == playerId && !v.IsUsed {
+			v.IsUsed = true
+			v.UsedAt = utils.LocalTime()
+		}
+	}
+	return nil
+}
+
+func (r *testPlayerRepository) InsertOneEmailVerification(pctx context.Context, req *player.EmailVerification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := *req
+	r.emailVerifications = append(r.emailVerifications, &result)
+	return nil
+}
+
+func (r *testPlayerRepository) UseOneEmailVerification(pctx context.Context, tokenHash string) (*player.EmailVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := utils.LocalTime()
+	for _, v := range r.emailVerifications {
+		if v.TokenHash == tokenHash && !v.IsUsed && v.ExpiresAt.After(now) {
+			v.IsUsed = true
+			v.UsedAt = now
+			result := *v
+			return &result, nil
+		}
+	}
+	return nil, errors.New("error: verify token is invalid or expired")
+}
+
+// The email is matched too like the repository, a token of an old email does not verify the player
+func (r *testPlayerRepository) UpdateOnePlayerVerified(pctx context.Context, playerId, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.players[playerId]
+	if !ok || v.Email != email {
+		return errors.New("error: player not found")
+	}
+	v.IsVerified = true
+	return nil
+}
+
+func (r *testPlayerRepository) SendNotification(pctx context.Context, cfg *config.Config, msg *notifier.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func (r *testPlayerRepository) RevokePlayerCredentials(pctx context.Context, grpcUrl string, req *authPb.RevokePlayerCredentialsReq) (*authPb.RevokePlayerCredentialsRes, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.isRevokeFail {
+		return nil, errors.New("error: revoke player credentials failed")
+	}
+	r.revoked = append(r.revoked, req.PlayerId)
+	return &authPb.RevokePlayerCredentialsRes{}, nil
+}
+
+func newTestInventoryRepository() *testInventoryRepository {
+	return &testInventoryRepository{
+		items:   make(map[string]*inventory.Inventory),
+		removed: make(map[string]*inventory.InventoryRemoved),
+	}
+}
+
+func (r *testInventoryRepository) FindOneInventoryRollback(pctx context.Context, orderId string) bool {
+	return false
+}
+
+func (r *testInventoryRepository) RemoveManyInventories(pctx context.Context, playerId, orderId string, inventoryIds []string) ([]*inventory.Inventory, error) {
+	results := make([]*inventory.Inventory, 0)
+	for _, id := range inventoryIds {
+		v, ok := r.items[id]
+		if !ok {
+			continue
+		}
+		if v.RemovingBy != "" && v.RemovingBy != orderId {
+			r.ReleaseManyRemovingItems(pctx, orderId)
+			return nil, errors.New("error: item is being removed by another order, try again")
+		}
+		v.RemovingBy = orderId
+		results = append(results, v)
+	}
+	return results, nil
+}
+
+func (r *testInventoryRepository) ReleaseManyRemovingItems(pctx context.Context, orderId string) error {
+	for _, v := range r.items {
+		if v.RemovingBy == orderId {
+			v.RemovingBy = ""
+		}
+	}
+	return nil
+}
+
+func (r *testInventoryRepository) DeleteManyRemovingItems(pctx context.Context, orderId string) error {
+	for id, v := range r.items {
+		if v.RemovingBy == orderId {
+			delete(r.items, id)
+		}
+	}
+	return nil
+}
+
+func (r *testInventoryRepository) InsertOneInventoryRemoved(pctx context.Context, req *inventory.InventoryRemoved) error {
+	r.removed[req.OrderId] = req
+	return nil
+}
+
+func (r *testInventoryRepository) FindManyRemovedInventoryIds(pctx context.Context, playerId, orderId string, inventoryIds []string) ([]string, error) {
+	results := make([]string, 0)
+	for _, removed := range r.removed {
+		if removed.OrderId == orderId || removed.PlayerId != playerId {
+			continue
+		}
+		for _, v := range removed.Items {
+			for _, id := range inventoryIds {
+				if v.Id.Hex() == id {
+					results = append(results, id)
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+func (r *testInventoryRepository) RefundPlayerItemRes(pctx context.Context, cfg *config.Config, req *payment.PaymentTransferRes) error {
+	r.replies = append(r.replies, req)
+	return nil
+}
+
+func newTestAuthRepository() *testAuthRepository {
+	return &testAuthRepository{
+		profiles:    make(map[string]*playerPb.PlayerProfile),
+		passwords:   make(map[string]string),
+		attempts:    make(map[string]*auth.LoginAttempt),
+		twoFactors:  make(map[string]*auth.TwoFactor),
+		challenges:  make(map[string]*auth.LoginChallenge),
+		credentials: make(map[string]*auth.Credential),
+		bans:        make(map[string]*auth.PlayerBan),
+	}
+}
+
+func (r *testAuthRepository) CredentialSearch(pctx context.Context, grpcUrl string, req *playerPb.CredentialSearchReq) (*playerPb.PlayerProfile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.profiles[req.Email]
+	if !ok || r.passwords[req.Email] != req.Password {
+		return nil, authRepository.ErrCredentialInvalid
+	}
+	return proto.Clone(v).(*playerPb.PlayerProfile), nil
+}
+
+func (r *testAuthRepository) FindOnePlayerProfileToRefresh(pctx context.Context, grpcUrl string, req *playerPb.FindOnePlayerProfileToRefreshReq) (*playerPb.PlayerProfile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.profiles {
+		if v.Id == req.PlayerId {
+			return proto.Clone(v).(*playerPb.PlayerProfile), nil
+		}
+	}
+	return nil, errors.New("error: player profile not found")
+}
+
+// A ban that is expired is not found like the repository
+func (r *testAuthRepository) FindOnePlayerBan(pctx context.Context, playerId string) (*auth.PlayerBan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.bans[playerId]
+	if !ok || (!v.ExpiresAt.IsZero() && !v.ExpiresAt.After(utils.LocalTime())) {
+		return nil, nil
+	}
+	result := *v
+	return &result, nil
+}
+
+func (r *testAuthRepository) UpsertOnePlayerBan(pctx context.Context, req *auth.PlayerBan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := *req
+	r.bans[req.PlayerId] = &result
+	return nil
+}
+
+func (r *testAuthRepository) DeleteOnePlayerBan(pctx context.Context, playerId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.bans[playerId]; !ok {
+		return 0, nil
+	}
+	delete(r.bans, playerId)
+	return 1, nil
+}
+
+func (r *testAuthRepository) FindOneAccessToken(pctx context.Context, accessToken string) (*auth.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.credentials {
+		if v.AccessToken == accessToken {
+			return v, nil
+		}
+	}
+	return nil, errors.New("error: access token not found")
+}
+
+func (r *testAuthRepository) DeleteManyPlayerCredentials(pctx context.Context, playerId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := int64(0)
+	for id, v := range r.credentials {
+		if v.PlayerId == "player:"+playerId || v.PlayerId == playerId {
+			delete(r.credentials, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *testAuthRepository) AccessToken(cfg *config.Config, claims *jwtauth.Claims) string {
+	return "access:" + claims.PlayerId
+}
+
+func (r *testAuthRepository) RefreshToken(cfg *config.Config, claims *jwtauth.Claims) string {
+	return "refresh:" + claims.PlayerId
+}
+
+func (r *testAuthRepository) InsertOnePlayerCredential(pctx context.Context, req *auth.Credential) (primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req.Id = primitive.NewObjectID()
+	r.credentials[req.Id.Hex()] = req
+	return req.Id, nil
+}
+
+func (r *testAuthRepository) FindOnePlayerCredential(pctx context.Context, credentialId string) (*auth.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.credentials[credentialId]; ok {
+		return v, nil
+	}
+	return nil, errors.New("error: player credential not found")
+}
+
+func (r *testAuthRepository) InsertOneLoginAudit(pctx context.Context, req *auth.LoginAudit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.audits = append(r.audits, req)
+	return nil
+}
+
+func (r *testAuthRepository) FindOneLoginAttempt(pctx context.Context, key string) (*auth.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.attempts[key]; ok {
+		result := *v
+		return &result, nil
+	}
+	return nil, errors.New("error: login attempt not found")
+}
+
+func (r *testAuthRepository) IncreaseOneLoginAttempt(pctx context.Context, key string, now, windowStart time.Time) (*auth.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.attempts[key]
+	if !ok {
+		v = &auth.LoginAttempt{Key: key}
+		r.attempts[key] = v
+	}
+	if v.LastFailedAt.Before(windowStart) {
+		v.FailedCount = 0
+	}
+	v.FailedCount++
+	v.LastFailedAt = now
+	v.UpdatedAt = now
+
+	result := *v
+	return &result, nil
+}
+
+func (r *testAuthRepository) LockOneLoginAttempt(pctx context.Context, key string, threshold int64, lockedUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.attempts[key]; ok && v.FailedCount >= threshold {
+		v.FailedCount = 0
+		v.LockedUntil = lockedUntil
+	}
+	return nil
+}
+
+func (r *testAuthRepository) DeleteOneLoginAttempt(pctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.attempts[key]; !ok {
+		return 0, nil
+	}
+	delete(r.attempts, key)
+	return 1, nil
+}
+
+func (r *testAuthRepository) FindOneTwoFactor(pctx context.Context, playerId string) (*auth.TwoFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.twoFactors[playerId]; ok {
+		result := *v
+		return &result, nil
+	}
+	return nil, nil
+}
+
+func (r *testAuthRepository) UpdateOneTwoFactorLastUsedStep(pctx context.Context, playerId string, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.twoFactors[playerId]
+	if !ok || v.LastUsedStep >= step {
+		return errors.New("error: code is already used")
+	}
+	v.LastUsedStep = step
+	return nil
+}
+
+func (r *testAuthRepository) UseOneTwoFactorRecoveryCode(pctx context.Context, playerId, codeHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.twoFactors[playerId]; ok {
+		for i, hash := range v.RecoveryCodes {
+			if hash == codeHash {
+				v.RecoveryCodes = append(v.RecoveryCodes[:i:i], v.RecoveryCodes[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.New("error: code is invalid")
+}
+
+func (r *testAuthRepository) InsertOneLoginChallenge(pctx context.Context, req *auth.LoginChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.challenges[req.TokenHash] = req
+	return nil
+}
+
+func (r *testAuthRepository) FindOneLoginChallenge(pctx context.Context, tokenHash string) (*auth.LoginChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.challenges[tokenHash]; ok && v.ExpiresAt.After(utils.LocalTime()) {
+		return v, nil
+	}
+	return nil, errors.New("error: challenge token is invalid or expired")
+}
+
+func (r *testAuthRepository) DeleteOneLoginChallenge(pctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.challenges, tokenHash)
+	return nil
+}
@@ -0,0 +1,163 @@
+package whydoweneedtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/config"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/item/itemUsecase"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/thumbnail"
+	"github.com/stretchr/testify/assert"
+)
+
+// Thumbnail
+// A png, a jpeg and a gif are decoded and scaled down to fit the size
+// An image that is smaller than the size keeps its size
+// An image that is larger than 4096 on one side is not decoded
+// A file that is not an image is invalid
+
+// Cases -> 6
+
+// UploadItemImage, the type of the image is sniffed from the content
+// A file that is not an image is not a supported format
+// A bmp image is not a supported format
+// A png that is larger than 4096 on one side is sniffed as a png and is not decoded
+
+// Cases -> 3
+
+type (
+	testThumbnail struct {
+		content        []byte
+		size           int
+		expectedWidth  int
+		expectedHeight int
+		isErr          bool
+	}
+
+	testUploadItemImageSniff struct {
+		content  []byte
+		expected string
+	}
+)
+
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(buf, img, nil)
+	case "gif":
+		err = gif.Encode(buf, img, nil)
+	default:
+		err = png.Encode(buf, img)
+	}
+	assert.Nil(t, err)
+	return buf.Bytes()
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []testThumbnail{
+		{
+			content:        encodeTestImage(t, "png", 400, 200),
+			size:           128,
+			expectedWidth:  128,
+			expectedHeight: 64,
+		},
+		{
+			content:        encodeTestImage(t, "jpeg", 200, 400),
+			size:           128,
+			expectedWidth:  64,
+			expectedHeight: 128,
+		},
+		{
+			content:        encodeTestImage(t, "gif", 300, 300),
+			size:           128,
+			expectedWidth:  128,
+			expectedHeight: 128,
+		},
+		{
+			content:        encodeTestImage(t, "png", 100, 50),
+			size:           128,
+			expectedWidth:  100,
+			expectedHeight: 50,
+		},
+		{
+			content: encodeTestImage(t, "png", thumbnail.MaxDimension+1, 1),
+			size:    128,
+			isErr:   true,
+		},
+		{
+			content: []byte("this is not an image"),
+			size:    128,
+			isErr:   true,
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		img, err := thumbnail.Decode(test.content)
+		if test.isErr {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+
+		thumb, err := thumbnail.Generate(img, test.size)
+		assert.Nil(t, err)
+
+		result, err := png.Decode(bytes.NewReader(thumb))
+		assert.Nil(t, err)
+		assert.Equal(t, test.expectedWidth, result.Bounds().Dx())
+		assert.Equal(t, test.expectedHeight, result.Bounds().Dy())
+	}
+}
+
+func TestUploadItemImageSniff(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Blob: config.Blob{MaxItemImageSize: 1 << 20}}
+	usecase := itemUsecase.NewItemUsecase(&testItemRepository{}, nil)
+
+	// A bmp header of a 1x1 image, it is sniffed as image/bmp
+	bmp := []byte("BM")
+	bmp = append(bmp, make([]byte, 56)...)
+
+	tests := []testUploadItemImageSniff{
+		{
+			content:  []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+			expected: "error: image must be a png, jpeg or gif image",
+		},
+		{
+			content:  bmp,
+			expected: "error: image must be a png, jpeg or gif image",
+		},
+		{
+			content:  encodeTestImage(t, "png", 1, thumbnail.MaxDimension+1),
+			expected: "error: image must not be larger than 4096x4096",
+		},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		res, err := usecase.UploadItemImage(ctx, cfg, "player:001", "item:65f5e4a1b4ba0d2b5c1e0a01", bytes.NewReader(test.content))
+		assert.Nil(t, res)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, test.expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package whydoweneedtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/pkg/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+// Validate
+// The test vectors of RFC 6238 (SHA1), the last 6 digits of the 8 digits codes
+// A code that is not of the time step
+// A code that is not 6 digits
+
+// Cases -> 8
+
+type (
+	testTotpValidate struct {
+		code     string
+		time     int64
+		expected int64
+		isValid  bool
+	}
+)
+
+func TestTotpValidate(t *testing.T) {
+	// Note that: the secret of the vectors is the ascii "12345678901234567890" in base32
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []testTotpValidate{
+		{code: "287082", time: 59, expected: 1, isValid: true},
+		{code: "081804", time: 1111111109, expected: 37037036, isValid: true},
+		{code: "050471", time: 1111111111, expected: 37037037, isValid: true},
+		{code: "005924", time: 1234567890, expected: 41152263, isValid: true},
+		{code: "279037", time: 2000000000, expected: 66666666, isValid: true},
+		{code: "353130", time: 20000000000, expected: 666666666, isValid: true},
+		{code: "287082", time: 1111111109, expected: 0, isValid: false},
+		{code: "94287082", time: 59, expected: 0, isValid: false},
+	}
+
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		step, ok := totp.Validate(secret, test.code, time.Unix(test.time, 0))
+
+		assert.Equal(t, test.isValid, ok)
+		assert.Equal(t, test.expected, step)
+	}
+}
@@ -0,0 +1,107 @@
+package whydoweneedtest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth"
+	"github.com/Rayato159/hello-sekai-shop-tutorial/modules/auth/authUsecase"
+	playerPb "github.com/Rayato159/hello-sekai-shop-tutorial/modules/player/playerPb"
+	"github.com/stretchr/testify/assert"
+)
+
+// LoginTwoFactor
+// Wrong code keeps the challenge
+// Wrong code after the delay threshold keeps the challenge
+// Valid code after the wrong codes logs in
+// Wrong codes until the lock, the challenge is dropped by the lock
+// Challenge that is dropped
+// Valid code while the codes are locked
+// Valid code after the lock is over logs in
+
+// Cases -> 13
+
+type (
+	testLoginTwoFactor struct {
+		// A new challenge is issued before the code is sent
+		isNewChallenge bool
+		// The lock is over before the code is sent
+		isUnlocked      bool
+		code            string
+		isErr           bool
+		isChallengeKept bool
+	}
+)
+
+func TestLoginTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	cfg := NewTestConfig()
+
+	recoveryCodeHash := func(code string) string {
+		hashed := sha256.Sum256([]byte(code))
+		return hex.EncodeToString(hashed[:])
+	}
+
+	repo := newTestAuthRepository()
+	repo.profiles["success@sekai.com"] = &playerPb.PlayerProfile{
+		Id:         "001",
+		Email:      "success@sekai.com",
+		Username:   "player001",
+		IsVerified: true,
+		CreatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+		UpdatedAt:  "0001-01-01 00:00:00 +0000 UTC",
+	}
+	repo.passwords["success@sekai.com"] = "123456"
+	repo.twoFactors["player:001"] = &auth.TwoFactor{
+		PlayerId:      "player:001",
+		Secret:        "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		IsEnabled:     true,
+		RecoveryCodes: []string{recoveryCodeHash("AAAAAAAA"), recoveryCodeHash("BBBBBBBB")},
+	}
+	usecase := authUsecase.NewAuthUsecase(repo)
+
+	tests := []testLoginTwoFactor{
+		{isNewChallenge: true, code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: true},
+		{code: "AAAA-AAAA", isErr: false, isChallengeKept: false},
+		{isNewChallenge: true, code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: true},
+		{code: "000000", isErr: true, isChallengeKept: false},
+		{code: "BBBB-BBBB", isErr: true, isChallengeKept: false},
+		{isNewChallenge: true, code: "BBBB-BBBB", isErr: true, isChallengeKept: false},
+		{isNewChallenge: true, isUnlocked: true, code: "BBBB-BBBB", isErr: false, isChallengeKept: false},
+	}
+
+	challengeToken := ""
+	for i, test := range tests {
+		fmt.Printf("case -> %d\n", i+1)
+
+		if test.isNewChallenge {
+			res, err := usecase.Login(ctx, cfg, &auth.PlayerLoginReq{Email: "success@sekai.com", Password: "123456"})
+			assert.Nil(t, err)
+			assert.NotNil(t, res.Challenge)
+			challengeToken = res.Challenge.ChallengeToken
+		}
+		if test.isUnlocked {
+			repo.attempts["2fa:player:001"].LockedUntil = time.Now().Add(-time.Second)
+		}
+
+		result, err := usecase.LoginTwoFactor(ctx, cfg, &auth.LoginTwoFactorReq{ChallengeToken: challengeToken, Code: test.code})
+
+		if test.isErr {
+			assert.NotEmpty(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, "player:001", result.Credential.PlayerId)
+		}
+		assert.Equal(t, test.isChallengeKept, len(repo.challenges) == 1)
+	}
+}